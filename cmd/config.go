@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/vinibsi/todo-api/internal/config"
+)
+
+const configUsage = `Usage: todo-api [flags] config print
+
+Commands:
+  print  show the effective configuration, with secrets redacted
+`
+
+// runConfig executa o subcomando config e retorna o código de saída
+func runConfig(conf *config.Config, args []string) int {
+	if len(args) != 1 || args[0] != "print" {
+		fmt.Fprint(os.Stderr, configUsage)
+		return 2
+	}
+	return exitCode(conf.Print(os.Stdout))
+}
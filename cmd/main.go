@@ -1,15 +1,35 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/auth"
 	"github.com/vinibsi/todo-api/internal/config"
 	"github.com/vinibsi/todo-api/internal/controller"
+	"github.com/vinibsi/todo-api/internal/event"
+	"github.com/vinibsi/todo-api/internal/idempotency"
+	"github.com/vinibsi/todo-api/internal/live"
+	"github.com/vinibsi/todo-api/internal/middleware"
+	"github.com/vinibsi/todo-api/internal/reminder"
 	"github.com/vinibsi/todo-api/internal/repository"
 	"github.com/vinibsi/todo-api/internal/service"
+	"github.com/vinibsi/todo-api/internal/trash"
+	"github.com/vinibsi/todo-api/internal/webhook"
 	"github.com/vinibsi/todo-api/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 func main() {
@@ -18,41 +38,307 @@ func main() {
 		log.Println("No .env file found, using system variables")
 	}
 
-	conf := config.Load()
+	// Padrões, arquivo (-config ou CONFIG_FILE), variáveis de ambiente e flags
+	conf, args, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if conf.Log.Level != "debug" {
+		gin.SetMode(gin.ReleaseMode)
+	}
 
-	db, err := database.Connect(conf.DatabaseUrl)
+	// Subcomandos: migrate up|down [n]|status|create <nome> e config print
+	if len(args) > 0 {
+		os.Exit(runCommand(conf, args))
+	}
+
+	db, err := connectDatabase(conf)
 	if err != nil {
 		log.Fatal("Database connection failed:", err)
 	}
 
+	if conf.Database.MigrateOnStart {
+		if err := database.Migrate(db); err != nil {
+			log.Fatal("Database migration failed:", err)
+		}
+	}
+
 	// Inicializa camadas
+	tokens := auth.NewTokenManager(conf.Auth.JWTSecret, conf.Auth.AccessTokenTTL, conf.Auth.RefreshTokenTTL)
+
+	userRepo := repository.NewUserRepository(db)
+	authService := service.NewAuthService(userRepo, tokens)
+	authController := controller.NewAuthController(authService)
+
+	apiTokenService := service.NewAPITokenService(repository.NewAPITokenRepository(db))
+	apiTokenController := controller.NewAPITokenController(apiTokenService)
+
+	projectRepo := repository.NewProjectRepository(db)
+	projectService := service.NewProjectService(projectRepo)
+
+	tagRepo := repository.NewTagRepository(db)
+	tagController := controller.NewTagController(service.NewTagService(tagRepo))
+
+	webhookRepo := repository.NewWebhookRepository(db)
+	webhookController := controller.NewWebhookController(service.NewWebhookService(webhookRepo))
+
+	// Eventos das tarefas vão para os webhooks e para o stream SSE
+	broker := event.NewBroker(conf.Events.ReplaySize, 0)
+	eventController := controller.NewEventController(broker, conf.Events.Heartbeat)
+
 	todoRepo := repository.NewTodoRepository(db)
-	todoService := service.NewTodoService(todoRepo)
+	trashRetention := time.Duration(conf.Todos.TrashRetentionDays) * 24 * time.Hour
+	todoService := service.NewTodoService(todoRepo, projectRepo, tagRepo,
+		service.WithMaxDepth(conf.Todos.MaxDepth),
+		service.WithAutoCompleteParent(conf.Todos.AutoCompleteParent),
+		service.WithPublisher(event.Publishers{webhook.NewDispatcher(webhookRepo), broker}),
+		service.WithHistory(repository.NewTodoHistoryRepository(db)),
+		service.WithTransactor(repository.NewTransactor(db)),
+		service.WithTrashRetention(trashRetention),
+	)
 	todoController := controller.NewTodoController(todoService)
+	projectController := controller.NewProjectController(projectService, todoService)
+	liveController := controller.NewLiveController(todoService, projectService, broker,
+		live.Config{PingInterval: conf.WebSocket.PingInterval}, conf.WebSocket.AllowedOrigins)
+
+	reminderRepo := repository.NewReminderRepository(db)
+	reminderController := controller.NewReminderController(service.NewReminderService(reminderRepo, todoRepo))
+	calendarController := controller.NewCalendarController(service.NewCalendarService(repository.NewCalendarFeedRepository(db)), todoService, conf.Server.TrustProxyHeaders)
+
+	idempotencyRepo := repository.NewIdempotencyRepository(db)
+	idempotent := middleware.Idempotency(idempotencyRepo, middleware.IdempotencyConfig{
+		TTL:          conf.Idempotency.TTL,
+		LockTimeout:  conf.Idempotency.LockTimeout,
+		MaxBodyBytes: int64(conf.Idempotency.MaxBodyBytes),
+	})
 
 	// Configura rotas
-	router := setupRoutes(todoController)
+	router := setupRoutes(tokens, apiTokenService, middleware.Problems(conf.IsDevelopment()), corsMiddleware(conf.CORS), idempotent, authController, apiTokenController, todoController, projectController, tagController, reminderController, calendarController, webhookController, eventController, liveController)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Agendador de lembretes em segundo plano
+	if conf.Reminders.Enabled {
+		var notifier reminder.Notifier = reminder.NewLogNotifier(nil)
+		if conf.Reminders.WebhookURL != "" {
+			notifier = reminder.NewWebhookNotifier(conf.Reminders.WebhookURL)
+		}
+
+		scheduler := reminder.NewScheduler(reminderRepo, notifier, reminder.Config{Interval: conf.Reminders.Interval})
+		scheduler.Start(ctx)
+		defer scheduler.Stop()
+	}
+
+	// Entrega dos webhooks em segundo plano
+	if conf.Webhooks.Enabled {
+		worker := webhook.NewWorker(webhookRepo, webhook.Config{
+			Interval:    conf.Webhooks.Interval,
+			MaxAttempts: conf.Webhooks.MaxAttempts,
+			Timeout:     conf.Webhooks.Timeout,
+		})
+		worker.Start(ctx)
+		defer worker.Stop()
+	}
+
+	// Limpeza da lixeira em segundo plano
+	if trashRetention > 0 {
+		purger := trash.NewPurger(todoRepo, trash.Config{
+			Retention: trashRetention,
+			Interval:  conf.Todos.TrashPurgeInterval,
+		})
+		purger.Start(ctx)
+		defer purger.Stop()
+	}
+
+	// Limpeza das chaves de idempotência vencidas
+	cleaner := idempotency.NewCleaner(idempotencyRepo, idempotency.Config{})
+	cleaner.Start(ctx)
+	defer cleaner.Stop()
 
 	// Inicia servidor
-	log.Printf("Server running on port %s", conf.Port)
-	log.Fatal(router.Run(":" + conf.Port))
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", conf.Server.Port),
+		Handler:           router,
+		ReadTimeout:       conf.Server.ReadTimeout,
+		ReadHeaderTimeout: conf.Server.ReadHeaderTimeout,
+		WriteTimeout:      conf.Server.WriteTimeout,
+		IdleTimeout:       conf.Server.IdleTimeout,
+	}
+	// Streams abertos não terminam sozinhos; fechar o broker libera as conexões
+	server.RegisterOnShutdown(broker.Close)
+	go func() {
+		log.Printf("Server running on port %d", conf.Server.Port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	// Encerra de forma limpa ao receber SIGINT/SIGTERM
+	<-ctx.Done()
+	log.Println("Shutting down server...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), conf.Server.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server shutdown failed: %v", err)
+	}
+}
+
+// runCommand executa um subcomando e retorna o código de saída
+func runCommand(conf *config.Config, args []string) int {
+	switch args[0] {
+	case "migrate":
+		return runMigrate(conf, args[1:])
+	case "config":
+		return runConfig(conf, args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q, use migrate or config\n", args[0])
+		return 2
+	}
+}
+
+// connectDatabase abre o banco com o pool e o nível de log da configuração
+func connectDatabase(conf *config.Config) (*gorm.DB, error) {
+	return database.ConnectWith(conf.Database.URL, database.Options{
+		MaxOpenConns:    conf.Database.MaxOpenConns,
+		MaxIdleConns:    conf.Database.MaxIdleConns,
+		ConnMaxLifetime: conf.Database.ConnMaxLifetime,
+		ConnMaxIdleTime: conf.Database.ConnMaxIdleTime,
+		LogLevel:        sqlLogLevel(conf.Log.Level),
+	})
+}
+
+// sqlLogLevel mostra as consultas SQL apenas no nível debug
+func sqlLogLevel(level string) logger.LogLevel {
+	switch level {
+	case "debug":
+		return logger.Info
+	case "error":
+		return logger.Error
+	default:
+		return logger.Warn
+	}
 }
 
-func setupRoutes(todoController *controller.TodoController) *gin.Engine {
+func corsMiddleware(conf config.CORSConfig) gin.HandlerFunc {
+	return middleware.CORS(middleware.CORSConfig{
+		AllowedOrigins:   conf.AllowedOrigins,
+		AllowedMethods:   conf.AllowedMethods,
+		AllowedHeaders:   conf.AllowedHeaders,
+		ExposedHeaders:   conf.ExposedHeaders,
+		AllowCredentials: conf.AllowCredentials,
+		MaxAge:           conf.MaxAge,
+	})
+}
+
+func setupRoutes(
+	tokens *auth.TokenManager,
+	apiTokens middleware.APITokenAuthenticator,
+	problems gin.HandlerFunc,
+	cors gin.HandlerFunc,
+	idempotent gin.HandlerFunc,
+	authController *controller.AuthController,
+	apiTokenController *controller.APITokenController,
+	todoController *controller.TodoController,
+	projectController *controller.ProjectController,
+	tagController *controller.TagController,
+	reminderController *controller.ReminderController,
+	calendarController *controller.CalendarController,
+	webhookController *controller.WebhookController,
+	eventController *controller.EventController,
+	liveController *controller.LiveController,
+) *gin.Engine {
 	router := gin.Default()
 	router.ForwardedByClientIP = true
 	router.SetTrustedProxies([]string{"127.0.0.1", "192.168.1.2", "10.0.0.0/8"})
+	router.Use(problems, cors)
+	router.NoRoute(func(ctx *gin.Context) {
+		ctx.Error(apperror.NotFound("Route not found"))
+	})
+
+	authenticated := middleware.Auth(tokens, apiTokens)
+	canRead := middleware.RequireScope(auth.ScopeTodosRead)
+	canWrite := middleware.RequireScope(auth.ScopeTodosWrite)
 
 	api := router.Group("/v1")
 	{
-		todos := api.Group("/todos")
+		authRoutes := api.Group("/auth")
+		{
+			authRoutes.POST("/register", authController.Register)
+			authRoutes.POST("/login", authController.Login)
+			authRoutes.POST("/refresh", authController.Refresh)
+			authRoutes.GET("/me", authenticated, authController.Me)
+		}
+
+		apiTokenRoutes := api.Group("/tokens", authenticated, middleware.RequireScope(auth.ScopeTokensManage))
+		{
+			apiTokenRoutes.GET("", apiTokenController.List)
+			apiTokenRoutes.POST("", apiTokenController.Create)
+			apiTokenRoutes.DELETE("/:id", apiTokenController.Revoke)
+		}
+
+		// Chaves de idempotência só nas rotas de tarefas e projetos: as respostas
+		// de tokens, webhooks e calendário trazem segredos que não devem ser guardados
+		todos := api.Group("/todos", authenticated, idempotent)
+		{
+			todos.GET("", canRead, todoController.GetAll)
+			todos.GET("/:id", canRead, todoController.GetByID)
+			todos.POST("", canWrite, todoController.Create)
+			todos.POST("/bulk", canWrite, todoController.Bulk)
+			todos.GET("/export", canRead, todoController.Export)
+			todos.POST("/import", canWrite, todoController.Import)
+			todos.PUT("/:id", canWrite, todoController.Update)
+			todos.PATCH("/:id", canWrite, todoController.Patch)
+			todos.DELETE("/:id", canWrite, todoController.Delete)
+			todos.PATCH("/:id/complete", canWrite, todoController.Complete)
+			todos.GET("/:id/children", canRead, todoController.Children)
+			todos.GET("/:id/tree", canRead, todoController.Tree)
+			todos.GET("/:id/history", canRead, todoController.History)
+			todos.POST("/:id/revert/:revision", canWrite, todoController.Revert)
+			todos.POST("/:id/restore", canWrite, todoController.Restore)
+			todos.GET("/:id/reminders", canRead, reminderController.List)
+			todos.POST("/:id/reminders", canWrite, reminderController.Create)
+			todos.DELETE("/:id/reminders/:reminderId", canWrite, reminderController.Delete)
+		}
+
+		projects := api.Group("/projects", authenticated, idempotent)
+		{
+			projects.GET("", canRead, projectController.GetAll)
+			projects.GET("/:id", canRead, projectController.GetByID)
+			projects.GET("/:id/todos", canRead, projectController.Todos)
+			projects.POST("", canWrite, projectController.Create)
+			projects.PUT("/:id", canWrite, projectController.Update)
+			projects.DELETE("/:id", canWrite, projectController.Delete)
+		}
+
+		api.GET("/tags", authenticated, canRead, tagController.GetAll)
+		api.GET("/trash", authenticated, canRead, todoController.Trash)
+		api.GET("/events", authenticated, canRead, eventController.Stream)
+		api.GET("/ws", middleware.QueryToken("access_token"), authenticated, canRead, liveController.Connect)
+
+		// O feed é público (link secreto); o gerenciamento do link exige sessão
+		calendar := api.Group("/calendar")
+		{
+			calendar.GET("/feed", authenticated, middleware.RequireScope(auth.ScopeTokensManage), calendarController.Get)
+			calendar.POST("/feed", authenticated, middleware.RequireScope(auth.ScopeTokensManage), calendarController.Rotate)
+			calendar.DELETE("/feed", authenticated, middleware.RequireScope(auth.ScopeTokensManage), calendarController.Disable)
+			calendar.GET("/:token", calendarController.Feed)
+		}
+
+		webhooks := api.Group("/webhooks", authenticated, middleware.RequireScope(auth.ScopeWebhooksManage))
 		{
-			todos.GET("", todoController.GetAll)
-			todos.GET("/:id", todoController.GetByID)
-			todos.POST("", todoController.Create)
-			todos.PUT("/:id", todoController.Update)
-			todos.DELETE("/:id", todoController.Delete)
-			todos.PATCH("/:id/complete", todoController.Complete)
+			webhooks.GET("", webhookController.List)
+			webhooks.POST("", webhookController.Create)
+			webhooks.GET("/:id", webhookController.GetByID)
+			webhooks.PUT("/:id", webhookController.Update)
+			webhooks.DELETE("/:id", webhookController.Delete)
+			webhooks.GET("/:id/deliveries", webhookController.Deliveries)
+			webhooks.GET("/:id/deliveries/:deliveryId", webhookController.Delivery)
+			webhooks.POST("/:id/deliveries/:deliveryId/redeliver", webhookController.Redeliver)
 		}
 	}
 
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/vinibsi/todo-api/internal/config"
+	"github.com/vinibsi/todo-api/pkg/database"
+)
+
+const migrateUsage = `Usage: todo-api [flags] migrate <command>
+
+Commands:
+  up                      apply all pending migrations
+  down [n]                revert the last n applied migrations (default 1)
+  status                  list migrations and when they were applied
+  create [-dir d] <name>  create empty up/down files for a new migration
+`
+
+// defaultMigrationsDir é a pasta das migrações embutidas, relativa à raiz do repositório
+const defaultMigrationsDir = "pkg/database/migrations"
+
+// runMigrate executa o subcomando migrate e retorna o código de saída
+func runMigrate(conf *config.Config, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	if args[0] == "create" {
+		return migrateCreate(args[1:])
+	}
+
+	steps := 1
+	switch args[0] {
+	case "up", "status":
+		if len(args) != 1 {
+			fmt.Fprint(os.Stderr, migrateUsage)
+			return 2
+		}
+	case "down":
+		if len(args) > 2 {
+			fmt.Fprint(os.Stderr, migrateUsage)
+			return 2
+		}
+		if len(args) == 2 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				fmt.Fprintf(os.Stderr, "invalid number of migrations: %q\n", args[1])
+				return 2
+			}
+			steps = n
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown migrate command %q\n\n%s", args[0], migrateUsage)
+		return 2
+	}
+
+	db, err := connectDatabase(conf)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Database connection failed:", err)
+		return 1
+	}
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up()
+		printMigrations(os.Stdout, "Applied", applied)
+		if err == nil && len(applied) == 0 {
+			fmt.Println("No pending migrations")
+		}
+		return exitCode(err)
+	case "down":
+		reverted, err := migrator.Down(steps)
+		printMigrations(os.Stdout, "Reverted", reverted)
+		if err == nil && len(reverted) == 0 {
+			fmt.Println("No applied migrations")
+		}
+		return exitCode(err)
+	default:
+		status, err := migrator.Status()
+		if err != nil {
+			return exitCode(err)
+		}
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "VERSION\tNAME\tAPPLIED AT")
+		for _, migration := range status {
+			appliedAt := "pending"
+			if migration.AppliedAt != nil {
+				appliedAt = migration.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(writer, "%04d\t%s\t%s\n", migration.Version, migration.Name, appliedAt)
+		}
+		writer.Flush()
+		return 0
+	}
+}
+
+// migrateCreate não precisa do banco: só cria os arquivos na pasta
+func migrateCreate(args []string) int {
+	flags := flag.NewFlagSet("migrate create", flag.ContinueOnError)
+	dir := flags.String("dir", defaultMigrationsDir, "migrations directory")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	paths, err := database.CreateMigration(*dir, flags.Arg(0))
+	for _, path := range paths {
+		fmt.Println("Created", path)
+	}
+	return exitCode(err)
+}
+
+func printMigrations(w io.Writer, action string, migrations []database.Migration) {
+	for _, migration := range migrations {
+		fmt.Fprintf(w, "%s %04d_%s\n", action, migration.Version, migration.Name)
+	}
+}
+
+func exitCode(err error) int {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,138 @@
+// Package apperror define os erros de domínio da aplicação. Cada erro tem um
+// tipo (Kind) que decide o status HTTP e o documento de problema (RFC 7807)
+// devolvido ao cliente; erros sem tipo são tratados como internos.
+package apperror
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Kind classifica um erro de domínio
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindValidation
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindPreconditionFailed
+	KindPayloadTooLarge
+	KindUnsupportedMediaType
+	KindUnprocessable
+	KindFailedDependency
+	KindUnavailable
+)
+
+var kinds = map[Kind]struct {
+	status int
+	slug   string
+}{
+	KindInternal:             {http.StatusInternalServerError, "internal-error"},
+	KindValidation:           {http.StatusBadRequest, "validation-error"},
+	KindUnauthorized:         {http.StatusUnauthorized, "unauthorized"},
+	KindForbidden:            {http.StatusForbidden, "forbidden"},
+	KindNotFound:             {http.StatusNotFound, "not-found"},
+	KindConflict:             {http.StatusConflict, "conflict"},
+	KindPreconditionFailed:   {http.StatusPreconditionFailed, "precondition-failed"},
+	KindPayloadTooLarge:      {http.StatusRequestEntityTooLarge, "payload-too-large"},
+	KindUnsupportedMediaType: {http.StatusUnsupportedMediaType, "unsupported-media-type"},
+	KindUnprocessable:        {http.StatusUnprocessableEntity, "unprocessable-entity"},
+	KindFailedDependency:     {http.StatusFailedDependency, "failed-dependency"},
+	KindUnavailable:          {http.StatusServiceUnavailable, "unavailable"},
+}
+
+// Status é o status HTTP do tipo
+func (k Kind) Status() int {
+	return kinds[k].status
+}
+
+// Slug identifica o tipo no campo type do problema (ex.: "not-found")
+func (k Kind) Slug() string {
+	return kinds[k].slug
+}
+
+// Title é o título padrão do tipo (o texto do status HTTP)
+func (k Kind) Title() string {
+	return http.StatusText(k.Status())
+}
+
+// FieldError é a falha de validação de um campo. Field segue os nomes do
+// JSON ou da query (ex.: "tags[2]", "recurrence.timezone")
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// Error é um erro de domínio. Message é segura para ser exibida ao cliente;
+// Err guarda a causa, quando houver
+type Error struct {
+	Kind    Kind
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func New(kind Kind, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+// Wrap classifica err, usando a mensagem dele
+func Wrap(kind Kind, err error) *Error {
+	return &Error{Kind: kind, Message: err.Error(), Err: err}
+}
+
+func NotFound(message string) *Error {
+	return New(KindNotFound, message)
+}
+
+func Validation(message string, fields ...FieldError) *Error {
+	return &Error{Kind: KindValidation, Message: message, Fields: fields}
+}
+
+func Unauthorized(message string) *Error {
+	return New(KindUnauthorized, message)
+}
+
+func Forbidden(message string) *Error {
+	return New(KindForbidden, message)
+}
+
+func Conflict(message string) *Error {
+	return New(KindConflict, message)
+}
+
+func PreconditionFailed(message string) *Error {
+	return New(KindPreconditionFailed, message)
+}
+
+func Unprocessable(message string) *Error {
+	return New(KindUnprocessable, message)
+}
+
+// As devolve o erro de domínio mais externo da cadeia de err
+func As(err error) (*Error, bool) {
+	var domain *Error
+	if errors.As(err, &domain) {
+		return domain, true
+	}
+	return nil, false
+}
+
+// KindOf devolve o tipo de err; erros sem tipo são internos
+func KindOf(err error) Kind {
+	if domain, ok := As(err); ok {
+		return domain.Kind
+	}
+	return KindInternal
+}
@@ -0,0 +1,166 @@
+package apperror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// InvalidInput converte um erro de binding (JSON malformado, tipo errado ou
+// regras do validator) em um erro de validação com os campos que falharam
+func InvalidInput(err error) *Error {
+	var (
+		validation validator.ValidationErrors
+		typeErr    *json.UnmarshalTypeError
+		syntaxErr  *json.SyntaxError
+	)
+
+	switch {
+	case errors.As(err, &validation):
+		fields := make([]FieldError, len(validation))
+		for i, fieldErr := range validation {
+			fields[i] = FieldError{Field: fieldPath(fieldErr), Message: fieldMessage(fieldErr)}
+		}
+		return &Error{Kind: KindValidation, Message: "The request has invalid fields", Fields: fields, Err: err}
+
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return &Error{
+			Kind:    KindValidation,
+			Message: "The request has invalid fields",
+			Fields:  []FieldError{{Field: jsonPath(typeErr.Field), Message: "must be of type " + typeName(typeErr.Type)}},
+			Err:     err,
+		}
+
+	case errors.As(err, &syntaxErr):
+		return &Error{Kind: KindValidation, Message: "Malformed JSON: " + syntaxErr.Error(), Err: err}
+
+	case errors.Is(err, io.EOF):
+		return &Error{Kind: KindValidation, Message: "The request body is empty", Err: err}
+
+	default:
+		return Wrap(KindValidation, err)
+	}
+}
+
+// fieldPath remove o nome da struct do namespace do validator
+// ("CreateTodoRequest.tags[0]" → "tags[0]")
+func fieldPath(fieldErr validator.FieldError) string {
+	namespace := fieldErr.Namespace()
+	if _, path, ok := strings.Cut(namespace, "."); ok {
+		return path
+	}
+	return namespace
+}
+
+// jsonPath escreve o caminho do encoding/json como o do validator
+// ("tags.1" → "tags[1]")
+func jsonPath(field string) string {
+	var path strings.Builder
+	for i, part := range strings.Split(field, ".") {
+		switch {
+		case isIndex(part):
+			path.WriteString("[" + part + "]")
+		case i > 0:
+			path.WriteString("." + part)
+		default:
+			path.WriteString(part)
+		}
+	}
+	return path.String()
+}
+
+func isIndex(part string) bool {
+	if part == "" {
+		return false
+	}
+	for _, r := range part {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func fieldMessage(fieldErr validator.FieldError) string {
+	param := fieldErr.Param()
+	unit := "characters"
+	switch fieldErr.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		unit = "items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		unit = ""
+	}
+	if param == "1" {
+		unit = strings.TrimSuffix(unit, "s")
+	}
+
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "min", "gte":
+		if unit == "" {
+			return "must be at least " + param
+		}
+		return fmt.Sprintf("must have at least %s %s", param, unit)
+	case "max", "lte":
+		if unit == "" {
+			return "must be at most " + param
+		}
+		return fmt.Sprintf("must have at most %s %s", param, unit)
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(param), ", ")
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "datetime":
+		return "must match the format " + param
+	default:
+		return fmt.Sprintf("failed the %q rule", fieldErr.Tag())
+	}
+}
+
+func typeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Struct, reflect.Map:
+		if t.String() == "time.Time" {
+			return "RFC 3339 date-time string"
+		}
+		return "object"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	default:
+		return t.String()
+	}
+}
+
+// FieldName é a função de nomes de campo para o validator: usa o nome do
+// JSON e, na falta dele, o da query (tag form)
+func FieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
@@ -0,0 +1,47 @@
+package auth
+
+import "github.com/gin-gonic/gin"
+
+const principalKey = "auth.principal"
+
+// Principal identifica quem fez a requisição. Sessões abertas com login
+// (JWT) têm todos os escopos; tokens de API só os concedidos na criação.
+type Principal struct {
+	UserID  uint
+	TokenID uint
+	Scopes  []string
+}
+
+// HasScope informa se o principal pode executar operações do escopo
+func (p *Principal) HasScope(scope string) bool {
+	if p.TokenID == 0 {
+		return true
+	}
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// SetPrincipal registra no contexto quem está autenticado na requisição
+func SetPrincipal(ctx *gin.Context, principal *Principal) {
+	ctx.Set(principalKey, principal)
+}
+
+// CurrentPrincipal retorna o principal autenticado ou nil em rotas anônimas
+func CurrentPrincipal(ctx *gin.Context) *Principal {
+	if value, ok := ctx.Get(principalKey); ok {
+		return value.(*Principal)
+	}
+	return nil
+}
+
+// UserID retorna o usuário autenticado ou zero quando a rota é anônima
+func UserID(ctx *gin.Context) uint {
+	if principal := CurrentPrincipal(ctx); principal != nil {
+		return principal.UserID
+	}
+	return 0
+}
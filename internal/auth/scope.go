@@ -0,0 +1,26 @@
+package auth
+
+// APITokenPrefix identifica tokens de API pessoais no header Authorization
+const APITokenPrefix = "tdo_"
+
+const (
+	ScopeTodosRead  = "todos:read"
+	ScopeTodosWrite = "todos:write"
+
+	// ScopeTokensManage e ScopeWebhooksManage só existem em sessões; não podem
+	// ser concedidos a tokens
+	ScopeTokensManage   = "tokens:manage"
+	ScopeWebhooksManage = "webhooks:manage"
+)
+
+// GrantableScopes são os escopos que podem ser atribuídos a tokens de API
+var GrantableScopes = []string{ScopeTodosRead, ScopeTodosWrite}
+
+func IsGrantableScope(scope string) bool {
+	for _, s := range GrantableScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,302 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv indica o arquivo de configuração quando a flag -config não é usada
+const FileEnv = "CONFIG_FILE"
+
+// fileSuffix é o sufixo das variáveis que leem o valor de um arquivo
+// (ex.: JWT_SECRET_FILE=/run/secrets/jwt)
+const fileSuffix = "_FILE"
+
+// Errors reúne todos os problemas encontrados ao carregar a configuração
+type Errors []error
+
+func (e Errors) Error() string {
+	var message strings.Builder
+	message.WriteString("invalid configuration:")
+	for _, err := range e {
+		message.WriteString("\n  - ")
+		message.WriteString(err.Error())
+	}
+	return message.String()
+}
+
+// Load monta a configuração em camadas: os padrões, o arquivo indicado por
+// -config ou CONFIG_FILE (.yaml, .yml ou .toml), as variáveis de ambiente e
+// as flags em args (ex.: -server.port=9000). Toda variável também pode ser
+// lida de um arquivo com o sufixo _FILE, o que serve para os segredos.
+// Retorna os argumentos que sobram após as flags (o subcomando) ou Errors
+// com todos os valores inválidos. Os subcomandos só validam as seções que usam
+func Load(args []string) (*Config, []string, error) {
+	conf := Default()
+	fields := fieldsOf(conf)
+
+	// As flags são guardadas e aplicadas por último, depois do arquivo e do ambiente
+	flagValues := map[string]string{}
+	flags := flag.NewFlagSet("todo-api", flag.ContinueOnError)
+	path := flags.String("config", "", "configuration file (.yaml, .yml or .toml), env "+FileEnv)
+	for _, f := range fields {
+		flags.Var(&flagValue{key: f.key, values: flagValues, isBool: f.value.Kind() == reflect.Bool}, f.key, "env "+f.env)
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	var errs Errors
+
+	if *path == "" {
+		*path = os.Getenv(FileEnv)
+	}
+	if *path != "" {
+		values, err := readFile(*path)
+		if err != nil {
+			return nil, nil, Errors{err}
+		}
+		source := "file " + *path
+		for _, f := range fields {
+			if value, ok := values[f.key]; ok {
+				errs = errs.add(f, source, setFileValue(f.value, value))
+				delete(values, f.key)
+			}
+		}
+		unknown := make([]string, 0, len(values))
+		for key := range values {
+			unknown = append(unknown, key)
+		}
+		sort.Strings(unknown)
+		for _, key := range unknown {
+			errs = append(errs, fmt.Errorf("%s: unknown key (%s)", key, source))
+		}
+	}
+
+	for _, f := range fields {
+		value, source, err := lookupEnv(f.env)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.key, err))
+			continue
+		}
+		if value != "" {
+			errs = errs.add(f, source, setValue(f.value, value))
+		}
+	}
+
+	for _, f := range fields {
+		if value, ok := flagValues[f.key]; ok {
+			errs = errs.add(f, "flag -"+f.key, setValue(f.value, value))
+		}
+	}
+
+	errs = append(errs, conf.validate(sectionsFor(flags.Args()))...)
+	if len(errs) > 0 {
+		return nil, nil, errs
+	}
+	return conf, flags.Args(), nil
+}
+
+// sectionsFor retorna as seções validadas para o subcomando em args (nil
+// valida todas): migrate create só cria arquivos e os demais subcomandos de
+// migrate usam apenas o banco
+func sectionsFor(args []string) []string {
+	if len(args) == 0 || args[0] != "migrate" {
+		return nil
+	}
+	if len(args) > 1 && args[1] == "create" {
+		return []string{}
+	}
+	return []string{"environment", "database", "log"}
+}
+
+func (e Errors) add(f field, source string, err error) Errors {
+	if err == nil {
+		return e
+	}
+	return append(e, fmt.Errorf("%s: %w (%s)", f.key, err, source))
+}
+
+// field é um valor configurável: key é o caminho no arquivo e o nome da flag
+type field struct {
+	key    string
+	env    string
+	secret string
+	value  reflect.Value
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// fieldsOf lista os campos de conf na ordem da struct
+func fieldsOf(conf *Config) []field {
+	return collectFields(reflect.ValueOf(conf).Elem(), "")
+}
+
+func collectFields(value reflect.Value, prefix string) []field {
+	var fields []field
+	for i := 0; i < value.NumField(); i++ {
+		structField := value.Type().Field(i)
+		key := prefix + structField.Tag.Get("yaml")
+		if structField.Type.Kind() == reflect.Struct {
+			fields = append(fields, collectFields(value.Field(i), key+".")...)
+			continue
+		}
+		fields = append(fields, field{
+			key:    key,
+			env:    structField.Tag.Get("env"),
+			secret: structField.Tag.Get("secret"),
+			value:  value.Field(i),
+		})
+	}
+	return fields
+}
+
+// setValue converte o texto de uma variável ou flag para o tipo do campo.
+// Listas são separadas por vírgula
+func setValue(target reflect.Value, raw string) error {
+	switch {
+	case target.Type() == durationType:
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		target.SetInt(int64(duration))
+	case target.Kind() == reflect.String:
+		target.SetString(raw)
+	case target.Kind() == reflect.Int:
+		number, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		target.SetInt(int64(number))
+	case target.Kind() == reflect.Bool:
+		enabled, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		target.SetBool(enabled)
+	case target.Kind() == reflect.Slice:
+		target.Set(reflect.ValueOf(splitList(raw)))
+	default:
+		return fmt.Errorf("unsupported type %s", target.Type())
+	}
+	return nil
+}
+
+// setFileValue aceita listas do próprio formato do arquivo além do texto
+func setFileValue(target reflect.Value, raw any) error {
+	if items, ok := raw.([]any); ok {
+		if target.Kind() != reflect.Slice {
+			return fmt.Errorf("expected a single value, got a list")
+		}
+		values := make([]string, 0, len(items))
+		for _, item := range items {
+			values = append(values, fmt.Sprint(item))
+		}
+		target.Set(reflect.ValueOf(values))
+		return nil
+	}
+	if raw == nil {
+		return setValue(target, "")
+	}
+	return setValue(target, fmt.Sprint(raw))
+}
+
+func splitList(raw string) []string {
+	var values []string
+	for _, value := range strings.Split(raw, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// lookupEnv lê a variável name ou o arquivo indicado em name_FILE, sem
+// a quebra de linha final. Retorna também a origem do valor
+func lookupEnv(name string) (string, string, error) {
+	value := os.Getenv(name)
+	path := os.Getenv(name + fileSuffix)
+	if path == "" {
+		return value, "env " + name, nil
+	}
+	if value != "" {
+		return "", "", fmt.Errorf("%s and %s%s are both set", name, name, fileSuffix)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", fmt.Errorf("%s%s: %w", name, fileSuffix, err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), "env " + name + fileSuffix, nil
+}
+
+// readFile lê o arquivo de configuração como um mapa de chaves com pontos
+// (ex.: server.port), no mesmo formato das flags
+func readFile(path string) (map[string]any, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config file: %w", err)
+	}
+
+	document := map[string]any{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &document)
+	case ".toml":
+		err = toml.Unmarshal(content, &document)
+	default:
+		return nil, fmt.Errorf("config file %s: unsupported format, use .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	values := map[string]any{}
+	flatten("", document, values)
+	return values, nil
+}
+
+func flatten(prefix string, document map[string]any, values map[string]any) {
+	for key, value := range document {
+		if nested, ok := value.(map[string]any); ok {
+			flatten(prefix+key+".", nested, values)
+			continue
+		}
+		values[prefix+key] = value
+	}
+}
+
+// flagValue guarda o texto de uma flag para ser aplicado depois do ambiente
+type flagValue struct {
+	key    string
+	values map[string]string
+	isBool bool
+}
+
+func (v *flagValue) String() string {
+	if v == nil || v.values == nil {
+		return ""
+	}
+	return v.values[v.key]
+}
+
+func (v *flagValue) Set(value string) error {
+	v.values[v.key] = value
+	return nil
+}
+
+// IsBoolFlag permite usar -reminders.enabled sem =true
+func (v *flagValue) IsBoolFlag() bool {
+	return v.isBool
+}
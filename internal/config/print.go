@@ -0,0 +1,46 @@
+package config
+
+import (
+	"io"
+	"net/url"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "[REDACTED]"
+
+// Redacted retorna uma cópia com os segredos ocultos. Nas URLs (secret:"url")
+// apenas a senha é ocultada
+func (c *Config) Redacted() *Config {
+	copied := *c
+	for _, f := range fieldsOf(&copied) {
+		value := f.value.String()
+		if f.secret == "" || value == "" {
+			continue
+		}
+		if f.secret == "url" {
+			f.value.SetString(redactURL(value))
+			continue
+		}
+		f.value.SetString(redacted)
+	}
+	return &copied
+}
+
+func redactURL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return redacted
+	}
+	return parsed.Redacted()
+}
+
+// Print escreve a configuração efetiva em YAML, com os segredos ocultos
+func (c *Config) Print(w io.Writer) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c.Redacted()); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// devJWTSecret é o segredo de exemplo do .env, recusado em produção
+const devJWTSecret = "dev-secret-change-me"
+
+const minProductionSecretLength = 32
+
+var (
+	environments = []string{"development", "test", "staging", "production"}
+	logLevels    = []string{"debug", "info", "warn", "error"}
+)
+
+// Validate confere os valores e retorna Errors com todos os problemas
+func (c *Config) Validate() error {
+	if errs := c.validate(nil); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validate confere as seções listadas (a primeira parte da chave); nil
+// confere todas
+func (c *Config) validate(sections []string) Errors {
+	v := &validator{envs: map[string]string{}, sections: sections}
+	for _, f := range fieldsOf(c) {
+		v.envs[f.key] = f.env
+	}
+
+	v.check(slices.Contains(environments, c.Environment), "environment", "must be one of "+strings.Join(environments, ", "))
+
+	v.check(c.Server.Port >= 1 && c.Server.Port <= 65535, "server.port", "must be between 1 and 65535")
+	v.check(c.Server.ReadTimeout >= 0, "server.read_timeout", "must not be negative")
+	v.check(c.Server.ReadHeaderTimeout >= 0, "server.read_header_timeout", "must not be negative")
+	v.check(c.Server.WriteTimeout >= 0, "server.write_timeout", "must not be negative")
+	v.check(c.Server.IdleTimeout >= 0, "server.idle_timeout", "must not be negative")
+	v.check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout", "must be positive")
+
+	v.check(c.Database.URL != "", "database.url", "is required")
+	v.check(c.Database.MaxOpenConns >= 0, "database.max_open_conns", "must not be negative")
+	v.check(c.Database.MaxIdleConns >= 0, "database.max_idle_conns", "must not be negative")
+	v.check(c.Database.MaxOpenConns == 0 || c.Database.MaxIdleConns <= c.Database.MaxOpenConns,
+		"database.max_idle_conns", "must not exceed database.max_open_conns")
+	v.check(c.Database.ConnMaxLifetime >= 0, "database.conn_max_lifetime", "must not be negative")
+	v.check(c.Database.ConnMaxIdleTime >= 0, "database.conn_max_idle_time", "must not be negative")
+
+	v.check(slices.Contains(logLevels, c.Log.Level), "log.level", "must be one of "+strings.Join(logLevels, ", "))
+
+	v.origins(c.CORS.AllowedOrigins, "cors.allowed_origins")
+	v.check(!(c.CORS.AllowCredentials && slices.Contains(c.CORS.AllowedOrigins, "*")),
+		"cors.allow_credentials", `cannot be used with the "*" origin`)
+	v.check(c.CORS.MaxAge >= 0, "cors.max_age", "must not be negative")
+
+	v.check(c.Auth.JWTSecret != "", "auth.jwt_secret", "is required")
+	if c.Environment == "production" && c.Auth.JWTSecret != "" {
+		v.check(c.Auth.JWTSecret != devJWTSecret, "auth.jwt_secret", "must be changed in production")
+		v.check(len(c.Auth.JWTSecret) >= minProductionSecretLength, "auth.jwt_secret",
+			fmt.Sprintf("must have at least %d characters in production", minProductionSecretLength))
+	}
+	v.check(c.Auth.AccessTokenTTL > 0, "auth.access_token_ttl", "must be positive")
+	v.check(c.Auth.RefreshTokenTTL > c.Auth.AccessTokenTTL, "auth.refresh_token_ttl", "must be longer than auth.access_token_ttl")
+
+	v.check(c.Todos.MaxDepth >= 1, "todos.max_depth", "must be at least 1")
+	v.check(c.Todos.TrashRetentionDays >= 0, "todos.trash_retention_days", "must not be negative")
+	v.check(c.Todos.TrashPurgeInterval > 0, "todos.trash_purge_interval", "must be positive")
+
+	v.check(c.Reminders.Interval > 0, "reminders.interval", "must be positive")
+	v.check(c.Reminders.WebhookURL == "" || isHTTPURL(c.Reminders.WebhookURL), "reminders.webhook_url", "must be an http or https URL")
+
+	v.check(c.Webhooks.Interval > 0, "webhooks.interval", "must be positive")
+	v.check(c.Webhooks.MaxAttempts >= 1, "webhooks.max_attempts", "must be at least 1")
+	v.check(c.Webhooks.Timeout > 0, "webhooks.timeout", "must be positive")
+
+	v.check(c.Events.ReplaySize >= 0, "events.replay_size", "must not be negative")
+	v.check(c.Events.Heartbeat > 0, "events.heartbeat", "must be positive")
+
+	v.check(c.WebSocket.PingInterval > 0, "websocket.ping_interval", "must be positive")
+	v.origins(c.WebSocket.AllowedOrigins, "websocket.allowed_origins")
+
+	v.check(c.Idempotency.TTL > 0, "idempotency.ttl", "must be positive")
+	v.check(c.Idempotency.LockTimeout > 0, "idempotency.lock_timeout", "must be positive")
+	v.check(c.Idempotency.MaxBodyBytes > 0, "idempotency.max_body_bytes", "must be positive")
+
+	return v.errs
+}
+
+// validator acumula os problemas, citando a variável de ambiente de cada chave
+type validator struct {
+	envs     map[string]string
+	sections []string
+	errs     Errors
+}
+
+func (v *validator) check(ok bool, key, message string) {
+	section, _, _ := strings.Cut(key, ".")
+	if v.sections != nil && !slices.Contains(v.sections, section) {
+		return
+	}
+	if !ok {
+		v.errs = append(v.errs, fmt.Errorf("%s (%s): %s", key, v.envs[key], message))
+	}
+}
+
+// origins aceita "*" ou origens como https://app.example.com (sem caminho)
+func (v *validator) origins(origins []string, key string) {
+	for _, origin := range origins {
+		parsed, err := url.Parse(strings.TrimSuffix(origin, "/"))
+		valid := origin == "*" || err == nil && isHTTPURL(origin) && parsed.Path == "" && parsed.RawQuery == ""
+		v.check(valid, key, fmt.Sprintf("invalid origin %q", origin))
+	}
+}
+
+func isHTTPURL(raw string) bool {
+	parsed, err := url.Parse(raw)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/auth"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/service"
+)
+
+type APITokenController struct {
+	service service.APITokenService
+}
+
+func NewAPITokenController(service service.APITokenService) *APITokenController {
+	return &APITokenController{service: service}
+}
+
+func (c *APITokenController) Create(ctx *gin.Context) {
+	var req dto.CreateAPITokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(apperror.InvalidInput(err))
+		return
+	}
+
+	token, err := c.service.Create(auth.UserID(ctx), &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.SuccessResponse{
+		Message: "Token successfully created, store it now as it will not be shown again",
+		Data:    token,
+	})
+}
+
+func (c *APITokenController) List(ctx *gin.Context) {
+	tokens, err := c.service.List(auth.UserID(ctx))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: tokens,
+	})
+}
+
+func (c *APITokenController) Revoke(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.Error(apperror.Validation("ID must be a valid number"))
+		return
+	}
+
+	if err := c.service.Revoke(auth.UserID(ctx), uint(id)); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "Token successfully revoked",
+	})
+}
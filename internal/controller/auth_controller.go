@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/auth"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/service"
+)
+
+type AuthController struct {
+	service service.AuthService
+}
+
+func NewAuthController(service service.AuthService) *AuthController {
+	return &AuthController{service: service}
+}
+
+func (c *AuthController) Register(ctx *gin.Context) {
+	var req dto.RegisterRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(apperror.InvalidInput(err))
+		return
+	}
+
+	user, err := c.service.Register(&req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.SuccessResponse{
+		Message: "User successfully registered",
+		Data:    user,
+	})
+}
+
+func (c *AuthController) Login(ctx *gin.Context) {
+	var req dto.LoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(apperror.InvalidInput(err))
+		return
+	}
+
+	tokens, err := c.service.Login(&req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: tokens,
+	})
+}
+
+func (c *AuthController) Refresh(ctx *gin.Context) {
+	var req dto.RefreshRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(apperror.InvalidInput(err))
+		return
+	}
+
+	tokens, err := c.service.Refresh(&req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: tokens,
+	})
+}
+
+func (c *AuthController) Me(ctx *gin.Context) {
+	user, err := c.service.Me(auth.UserID(ctx))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: user,
+	})
+}
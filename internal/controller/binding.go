@@ -0,0 +1,15 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/vinibsi/todo-api/internal/apperror"
+)
+
+// Os erros de validação usam os nomes dos campos no JSON e na query
+// (ex.: "due_date"), os mesmos que o cliente enviou
+func init() {
+	if engine, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		engine.RegisterTagNameFunc(apperror.FieldName)
+	}
+}
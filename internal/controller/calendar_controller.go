@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/auth"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/ical"
+	"github.com/vinibsi/todo-api/internal/service"
+)
+
+type CalendarController struct {
+	feeds service.CalendarService
+	todos service.TodoService
+
+	// trustProxyHeaders aceita o esquema do X-Forwarded-Proto no link do feed
+	trustProxyHeaders bool
+}
+
+func NewCalendarController(feeds service.CalendarService, todos service.TodoService, trustProxyHeaders bool) *CalendarController {
+	return &CalendarController{feeds: feeds, todos: todos, trustProxyHeaders: trustProxyHeaders}
+}
+
+func (c *CalendarController) Get(ctx *gin.Context) {
+	feed, err := c.feeds.Get(auth.UserID(ctx))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: feed,
+	})
+}
+
+// Rotate cria o link secreto do feed, substituindo o anterior se houver
+func (c *CalendarController) Rotate(ctx *gin.Context) {
+	feed, err := c.feeds.Rotate(auth.UserID(ctx))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	feed.URL = c.feedURL(ctx, feed.Token)
+
+	ctx.JSON(http.StatusCreated, dto.SuccessResponse{
+		Message: "Calendar feed successfully created, store the URL now as it will not be shown again",
+		Data:    feed,
+	})
+}
+
+func (c *CalendarController) Disable(ctx *gin.Context) {
+	if err := c.feeds.Disable(auth.UserID(ctx)); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "Calendar feed successfully disabled",
+	})
+}
+
+// Feed serve o calendário .ics das tarefas com prazo. A rota é pública: o
+// link secreto identifica o usuário, já que aplicativos de calendário não
+// enviam o header Authorization. O ETag permite respostas 304
+func (c *CalendarController) Feed(ctx *gin.Context) {
+	var query dto.CalendarFeedQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(apperror.InvalidInput(err))
+		return
+	}
+
+	userID, err := c.feeds.Authenticate(strings.TrimSuffix(ctx.Param("token"), ".ics"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// O feed é montado em memória para calcular o ETag antes de responder
+	var body bytes.Buffer
+	writer := ical.NewWriter(&body, ical.Options{Name: "Todos", Events: query.Events})
+	hasDue := true
+	err = c.todos.Export(userID, &dto.TodoListQuery{HasDue: &hasDue}, writer.Write)
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	sum := sha256.Sum256(body.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	ctx.Header("ETag", etag)
+	ctx.Header("Cache-Control", "private, no-cache")
+	if etagMatches(ctx.GetHeader("If-None-Match"), etag) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	ctx.Data(http.StatusOK, ical.ContentType, body.Bytes())
+}
+
+// feedURL monta o endereço público do feed a partir da rota de gerenciamento
+// (…/calendar/feed → …/calendar/<token>.ics). O X-Forwarded-Proto só é usado
+// com trustProxyHeaders e apenas com http ou https
+func (c *CalendarController) feedURL(ctx *gin.Context, token string) string {
+	scheme := "http"
+	if ctx.Request.TLS != nil {
+		scheme = "https"
+	}
+	if c.trustProxyHeaders {
+		if proto := strings.ToLower(ctx.GetHeader("X-Forwarded-Proto")); proto == "http" || proto == "https" {
+			scheme = proto
+		}
+	}
+	base := strings.TrimSuffix(ctx.Request.URL.Path, "/feed")
+	return scheme + "://" + ctx.Request.Host + base + "/" + token + ".ics"
+}
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/auth"
+	"github.com/vinibsi/todo-api/internal/event"
+)
+
+// Intervalo padrão entre comentários de keep-alive no stream
+const defaultHeartbeat = 15 * time.Second
+
+type EventController struct {
+	broker    *event.Broker
+	heartbeat time.Duration
+}
+
+func NewEventController(broker *event.Broker, heartbeat time.Duration) *EventController {
+	if heartbeat <= 0 {
+		heartbeat = defaultHeartbeat
+	}
+	return &EventController{broker: broker, heartbeat: heartbeat}
+}
+
+// Stream envia as mudanças nas tarefas do usuário como Server-Sent Events.
+// O cliente retoma de onde parou com o header Last-Event-ID (ou o parâmetro
+// last_event_id). Se os eventos perdidos não estão mais no buffer, o stream
+// começa com um evento "reset" e o cliente deve recarregar as tarefas
+func (c *EventController) Stream(ctx *gin.Context) {
+	lastID, err := lastEventID(ctx)
+	if err != nil {
+		ctx.Error(apperror.Validation("Last-Event-ID must be a valid event id"))
+		return
+	}
+
+	sub, missed, complete := c.broker.Subscribe(auth.UserID(ctx), lastID)
+	defer c.broker.Unsubscribe(sub)
+
+	header := ctx.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	w := ctx.Writer
+	fmt.Fprintf(w, "retry: %d\n\n", 3000)
+	if !complete {
+		fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+	}
+	for _, message := range missed {
+		writeEvent(w, message)
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(c.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case message, ok := <-sub.C:
+			if !ok {
+				// Broker encerrado ou cliente lento: o EventSource reconecta
+				// com Last-Event-ID e recebe o que faltou
+				return
+			}
+			writeEvent(w, message)
+			w.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			w.Flush()
+		}
+	}
+}
+
+func writeEvent(w io.Writer, message event.Message) {
+	data, err := json.Marshal(message.Event)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", message.ID, message.Event.Type, data)
+}
+
+func lastEventID(ctx *gin.Context) (uint64, error) {
+	raw := ctx.GetHeader("Last-Event-ID")
+	if raw == "" {
+		raw = ctx.Query("last_event_id")
+	}
+	if raw == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(raw, 10, 64)
+}
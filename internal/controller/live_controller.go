@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/auth"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/event"
+	"github.com/vinibsi/todo-api/internal/live"
+	"github.com/vinibsi/todo-api/internal/service"
+)
+
+// Limite de tarefas por mensagem de inscrição
+const maxWatchedTodos = 100
+
+// LiveController atende o canal WebSocket e aplica as alterações enviadas
+// pelos clientes com as mesmas validações da API REST
+type LiveController struct {
+	todoService    service.TodoService
+	projectService service.ProjectService
+	server         *live.Server
+}
+
+func NewLiveController(todoService service.TodoService, projectService service.ProjectService, broker *event.Broker, config live.Config, allowedOrigins []string) *LiveController {
+	c := &LiveController{todoService: todoService, projectService: projectService}
+	c.server = live.NewServer(broker, c, config, allowedOrigins)
+	return c
+}
+
+func (c *LiveController) Connect(ctx *gin.Context) {
+	// O handshake responde sozinho em caso de erro
+	c.server.Serve(ctx.Writer, ctx.Request, auth.CurrentPrincipal(ctx))
+}
+
+// Handle processa uma mensagem do cliente e retorna o ack
+func (c *LiveController) Handle(session *live.Session, message live.ClientMessage) live.ServerMessage {
+	userID := session.Principal.UserID
+
+	switch message.Type {
+	case live.TypeSubscribe:
+		if err := c.checkWatch(userID, message); err != nil {
+			return nackError(message.ID, err)
+		}
+		return live.Ack(message.ID, session.Watch(message.ProjectID, message.TodoIDs))
+
+	case live.TypeUnsubscribe:
+		return live.Ack(message.ID, session.Unwatch(message.ProjectID, message.TodoIDs))
+
+	case live.TypeCreate, live.TypeUpdate, live.TypeComplete, live.TypeDelete:
+		if !session.Principal.HasScope(auth.ScopeTodosWrite) {
+			return live.Nack(message.ID, http.StatusForbidden, "Missing required scope: "+auth.ScopeTodosWrite)
+		}
+		if message.Type != live.TypeCreate && message.TodoID == 0 {
+			return live.Nack(message.ID, http.StatusBadRequest, "todo_id is required")
+		}
+		data, err := c.mutate(userID, message)
+		if err != nil {
+			return nackError(message.ID, err)
+		}
+		return live.Ack(message.ID, data)
+
+	default:
+		return live.Nack(message.ID, http.StatusBadRequest, "unknown message type")
+	}
+}
+
+// checkWatch garante que o projeto e as tarefas existem e são do usuário
+func (c *LiveController) checkWatch(userID uint, message live.ClientMessage) error {
+	if message.ProjectID == nil && len(message.TodoIDs) == 0 {
+		return apperror.Validation("project_id or todo_ids is required")
+	}
+	if len(message.TodoIDs) > maxWatchedTodos {
+		return apperror.Validation("too many todo_ids")
+	}
+
+	if message.ProjectID != nil {
+		if _, err := c.projectService.GetByID(userID, *message.ProjectID); err != nil {
+			return err
+		}
+	}
+	for _, id := range message.TodoIDs {
+		if _, err := c.todoService.GetByID(userID, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *LiveController) mutate(userID uint, message live.ClientMessage) (interface{}, error) {
+	switch message.Type {
+	case live.TypeCreate:
+		var req dto.CreateTodoRequest
+		if err := decodeMessageData(message.Data, &req); err != nil {
+			return nil, err
+		}
+		return c.todoService.Create(userID, &req)
+
+	case live.TypeUpdate:
+		var req dto.UpdateTodoRequest
+		if err := decodeMessageData(message.Data, &req); err != nil {
+			return nil, err
+		}
+		return c.todoService.Update(userID, message.TodoID, &req, nil)
+
+	case live.TypeComplete:
+		return c.todoService.Complete(userID, message.TodoID, nil)
+
+	default:
+		return nil, c.todoService.Delete(userID, message.TodoID, nil)
+	}
+}
+
+// decodeMessageData lê o campo data e aplica as validações de binding da API REST
+func decodeMessageData(data json.RawMessage, target interface{}) error {
+	if len(data) == 0 {
+		return apperror.Validation("data is required")
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return apperror.InvalidInput(err)
+	}
+	if err := binding.Validator.ValidateStruct(target); err != nil {
+		return apperror.InvalidInput(err)
+	}
+	return nil
+}
+
+// nackError responde com o status do tipo do erro; o detalhe de erros
+// internos fica só no log
+func nackError(id string, err error) live.ServerMessage {
+	kind := apperror.KindOf(err)
+	if kind == apperror.KindInternal {
+		log.Printf("Live message %s: %v", id, err)
+		return live.Nack(id, kind.Status(), kind.Title())
+	}
+	return live.Nack(id, kind.Status(), err.Error())
+}
@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/dto"
+)
+
+// errInvalidIfMatch indica um If-Match que não é "*" nem o ETag de uma tarefa
+var errInvalidIfMatch = apperror.Validation(`If-Match must be "*" or a single todo ETag`)
+
+// todoETag é o ETag da tarefa, derivado da versão gravada no banco
+func todoETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+func setTodoETag(ctx *gin.Context, todo *dto.TodoResponse) {
+	ctx.Header("ETag", todoETag(todo.Version))
+}
+
+// ifMatchVersion lê a versão esperada do If-Match. Sem o header ou com "*"
+// não há pré-condição (nil). ETags fracos nunca atendem ao If-Match, que usa
+// comparação forte, por isso viram uma versão inexistente (0)
+func ifMatchVersion(ctx *gin.Context) (*int, error) {
+	header := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	weak := strings.HasPrefix(header, "W/")
+	tag := strings.TrimPrefix(header, "W/")
+	if len(tag) < 2 || !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) {
+		return nil, errInvalidIfMatch
+	}
+	version, err := strconv.Atoi(tag[1 : len(tag)-1])
+	if err != nil || version < 1 {
+		return nil, errInvalidIfMatch
+	}
+	if weak {
+		version = 0
+	}
+	return &version, nil
+}
+
+// etagMatches compara o ETag com a lista do If-None-Match, aceitando "*" e
+// validadores fracos (W/)
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/auth"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/service"
+)
+
+type ProjectController struct {
+	service     service.ProjectService
+	todoService service.TodoService
+}
+
+func NewProjectController(service service.ProjectService, todoService service.TodoService) *ProjectController {
+	return &ProjectController{service: service, todoService: todoService}
+}
+
+func (c *ProjectController) Create(ctx *gin.Context) {
+	var req dto.CreateProjectRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(apperror.InvalidInput(err))
+		return
+	}
+
+	project, err := c.service.Create(auth.UserID(ctx), &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.SuccessResponse{
+		Message: "Project successfully created",
+		Data:    project,
+	})
+}
+
+func (c *ProjectController) GetAll(ctx *gin.Context) {
+	includeArchived, _ := strconv.ParseBool(ctx.DefaultQuery("archived", "false"))
+
+	projects, err := c.service.List(auth.UserID(ctx), includeArchived)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: projects,
+	})
+}
+
+func (c *ProjectController) GetByID(ctx *gin.Context) {
+	id, ok := parseProjectID(ctx)
+	if !ok {
+		return
+	}
+
+	project, err := c.service.GetByID(auth.UserID(ctx), id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: project,
+	})
+}
+
+func (c *ProjectController) Update(ctx *gin.Context) {
+	id, ok := parseProjectID(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateProjectRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(apperror.InvalidInput(err))
+		return
+	}
+
+	project, err := c.service.Update(auth.UserID(ctx), id, &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "Project successfully edited",
+		Data:    project,
+	})
+}
+
+func (c *ProjectController) Delete(ctx *gin.Context) {
+	id, ok := parseProjectID(ctx)
+	if !ok {
+		return
+	}
+
+	var query dto.DeleteProjectQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(apperror.InvalidInput(err))
+		return
+	}
+
+	if err := c.todoService.DeleteProject(auth.UserID(ctx), id, query.Todos == "cascade"); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "Project successfully deleted",
+	})
+}
+
+// Todos lista as tarefas do projeto aceitando os mesmos parâmetros de GET /v1/todos
+func (c *ProjectController) Todos(ctx *gin.Context) {
+	id, ok := parseProjectID(ctx)
+	if !ok {
+		return
+	}
+
+	var query dto.TodoListQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(apperror.InvalidInput(err))
+		return
+	}
+
+	userID := auth.UserID(ctx)
+	if _, err := c.service.GetByID(userID, id); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	query.ProjectID = &id
+	query.Inbox = false
+
+	todos, err := c.todoService.GetAll(userID, &query)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: todos,
+	})
+}
+
+func parseProjectID(ctx *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.Error(apperror.Validation("ID must be a valid number"))
+		return 0, false
+	}
+	return uint(id), true
+}
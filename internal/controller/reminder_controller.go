@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/auth"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/service"
+)
+
+type ReminderController struct {
+	service service.ReminderService
+}
+
+func NewReminderController(service service.ReminderService) *ReminderController {
+	return &ReminderController{service: service}
+}
+
+func (c *ReminderController) Create(ctx *gin.Context) {
+	todoID, ok := parseUintParam(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.CreateReminderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(apperror.InvalidInput(err))
+		return
+	}
+
+	reminder, err := c.service.Create(auth.UserID(ctx), todoID, &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.SuccessResponse{
+		Message: "Reminder successfully created",
+		Data:    reminder,
+	})
+}
+
+func (c *ReminderController) List(ctx *gin.Context) {
+	todoID, ok := parseUintParam(ctx, "id")
+	if !ok {
+		return
+	}
+
+	reminders, err := c.service.List(auth.UserID(ctx), todoID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: reminders,
+	})
+}
+
+func (c *ReminderController) Delete(ctx *gin.Context) {
+	todoID, ok := parseUintParam(ctx, "id")
+	if !ok {
+		return
+	}
+	id, ok := parseUintParam(ctx, "reminderId")
+	if !ok {
+		return
+	}
+
+	if err := c.service.Delete(auth.UserID(ctx), todoID, id); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "Reminder successfully deleted",
+	})
+}
+
+func parseUintParam(ctx *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param(name), 10, 32)
+	if err != nil {
+		ctx.Error(apperror.Validation("ID must be a valid number"))
+		return 0, false
+	}
+	return uint(id), true
+}
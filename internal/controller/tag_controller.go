@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vinibsi/todo-api/internal/auth"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/service"
+)
+
+type TagController struct {
+	service service.TagService
+}
+
+func NewTagController(service service.TagService) *TagController {
+	return &TagController{service: service}
+}
+
+func (c *TagController) GetAll(ctx *gin.Context) {
+	tags, err := c.service.List(auth.UserID(ctx))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: tags,
+	})
+}
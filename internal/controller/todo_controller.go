@@ -1,13 +1,23 @@
 package controller
 
 import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
 	"net/http"
 	"strconv"
 
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/auth"
 	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/jsonpatch"
+	"github.com/vinibsi/todo-api/internal/middleware"
 	"github.com/vinibsi/todo-api/internal/service"
+	"github.com/vinibsi/todo-api/internal/transfer"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 type TodoController struct {
@@ -21,24 +31,17 @@ func NewTodoController(service service.TodoService) *TodoController {
 func (c *TodoController) Create(ctx *gin.Context) {
 	var req dto.CreateTodoRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   "Invalid Data",
-			Message: err.Error(),
-			Code:    http.StatusBadRequest,
-		})
+		ctx.Error(apperror.InvalidInput(err))
 		return
 	}
 
-	todo, err := c.service.Create(&req)
+	todo, err := c.service.Create(auth.UserID(ctx), &req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-			Error:   "Internal server error",
-			Message: err.Error(),
-			Code:    http.StatusInternalServerError,
-		})
+		ctx.Error(err)
 		return
 	}
 
+	setTodoETag(ctx, todo)
 	ctx.JSON(http.StatusCreated, dto.SuccessResponse{
 		Message: "Todo successfully created",
 		Data:    todo,
@@ -48,45 +51,32 @@ func (c *TodoController) Create(ctx *gin.Context) {
 func (c *TodoController) GetByID(ctx *gin.Context) {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   "Invalid ID",
-			Message: "ID must be a valid number",
-			Code:    http.StatusBadRequest,
-		})
+		ctx.Error(apperror.Validation("ID must be a valid number"))
 		return
 	}
 
-	todo, err := c.service.GetByID(uint(id))
+	todo, err := c.service.GetByID(auth.UserID(ctx), uint(id))
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "todo not found" {
-			status = http.StatusNotFound
-		}
-
-		ctx.JSON(status, dto.ErrorResponse{
-			Error:   "Failed to get todo",
-			Message: err.Error(),
-			Code:    status,
-		})
+		ctx.Error(err)
 		return
 	}
 
+	setTodoETag(ctx, todo)
 	ctx.JSON(http.StatusOK, dto.SuccessResponse{
 		Data: todo,
 	})
 }
 
 func (c *TodoController) GetAll(ctx *gin.Context) {
-	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("size", "10"))
+	var query dto.TodoListQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(apperror.InvalidInput(err))
+		return
+	}
 
-	todos, err := c.service.GetAll(page, pageSize)
+	todos, err := c.service.GetAll(auth.UserID(ctx), &query)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-			Error:   "Internal server error",
-			Message: err.Error(),
-			Code:    http.StatusInternalServerError,
-		})
+		ctx.Error(err)
 		return
 	}
 
@@ -98,39 +88,85 @@ func (c *TodoController) GetAll(ctx *gin.Context) {
 func (c *TodoController) Update(ctx *gin.Context) {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   "Invalid ID",
-			Message: "ID must be a valid number",
-			Code:    http.StatusBadRequest,
-		})
+		ctx.Error(apperror.Validation("ID must be a valid number"))
+		return
+	}
+
+	ifMatch, err := ifMatchVersion(ctx)
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
 
 	var req dto.UpdateTodoRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   "Invalid Data",
-			Message: err.Error(),
-			Code:    http.StatusBadRequest,
-		})
+		ctx.Error(apperror.InvalidInput(err))
 		return
 	}
 
-	todo, err := c.service.Update(uint(id), &req)
+	todo, err := c.service.Update(auth.UserID(ctx), uint(id), &req, ifMatch)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "todo not found" {
-			status = http.StatusNotFound
-		}
+		ctx.Error(err)
+		return
+	}
+
+	setTodoETag(ctx, todo)
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "Todo successfully edited",
+		Data:    todo,
+	})
+}
+
+// Patch aplica um JSON Merge Patch (RFC 7396) ou um JSON Patch (RFC 6902),
+// conforme o Content-Type, aos campos editáveis da tarefa. Ao contrário do
+// PUT, null ou a remoção do campo limpam o valor (ex.: due_date)
+func (c *TodoController) Patch(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.Error(apperror.Validation("ID must be a valid number"))
+		return
+	}
 
-		ctx.JSON(status, dto.ErrorResponse{
-			Error:   "Update todo failed",
-			Message: err.Error(),
-			Code:    status,
-		})
+	var applyPatch func(doc, patch []byte) ([]byte, error)
+	switch ctx.ContentType() {
+	case jsonpatch.MergePatchContentType:
+		applyPatch = jsonpatch.MergePatch
+	case jsonpatch.JSONPatchContentType:
+		applyPatch = jsonpatch.Apply
+	default:
+		ctx.Header("Accept-Patch", acceptPatch)
+		ctx.Error(apperror.New(apperror.KindUnsupportedMediaType, "Content-Type must be one of "+acceptPatch))
+		return
+	}
+
+	ifMatch, err := ifMatchVersion(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	patch, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		ctx.Error(apperror.InvalidInput(err))
 		return
 	}
 
+	todo, err := c.service.Patch(auth.UserID(ctx), uint(id), func(current *dto.TodoPatchDocument) (*dto.TodoPatchDocument, error) {
+		doc, err := json.Marshal(current)
+		if err != nil {
+			return nil, err
+		}
+		if doc, err = applyPatch(doc, patch); err != nil {
+			return nil, err
+		}
+		return decodePatchedTodo(doc)
+	}, ifMatch)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	setTodoETag(ctx, todo)
 	ctx.JSON(http.StatusOK, dto.SuccessResponse{
 		Message: "Todo successfully edited",
 		Data:    todo,
@@ -140,61 +176,467 @@ func (c *TodoController) Update(ctx *gin.Context) {
 func (c *TodoController) Delete(ctx *gin.Context) {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   "Invalid ID",
-			Message: "ID must be a valid number",
-			Code:    http.StatusBadRequest,
-		})
+		ctx.Error(apperror.Validation("ID must be a valid number"))
 		return
 	}
 
-	if err := c.service.Delete(uint(id)); err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "todo not found" {
-			status = http.StatusNotFound
+	// Por padrão a tarefa vai para a lixeira; permanent=true apaga definitivamente
+	permanent := false
+	if raw := ctx.Query("permanent"); raw != "" {
+		if permanent, err = strconv.ParseBool(raw); err != nil {
+			ctx.Error(apperror.Validation("permanent must be a boolean"))
+			return
 		}
+	}
 
-		ctx.JSON(status, dto.ErrorResponse{
-			Error:   "Delete todo failed",
-			Message: err.Error(),
-			Code:    status,
-		})
+	ifMatch, err := ifMatchVersion(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	message := "Todo successfully deleted"
+	if permanent {
+		message = "Todo permanently deleted"
+		err = c.service.Purge(auth.UserID(ctx), uint(id), ifMatch)
+	} else {
+		err = c.service.Delete(auth.UserID(ctx), uint(id), ifMatch)
+	}
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, dto.SuccessResponse{
-		Message: "Todo successfully deleted",
+		Message: message,
+	})
+}
+
+// Trash lista as tarefas na lixeira
+func (c *TodoController) Trash(ctx *gin.Context) {
+	var query dto.TrashQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(apperror.InvalidInput(err))
+		return
+	}
+
+	trash, err := c.service.Trash(auth.UserID(ctx), &query)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: trash,
+	})
+}
+
+func (c *TodoController) Restore(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.Error(apperror.Validation("ID must be a valid number"))
+		return
+	}
+
+	todo, err := c.service.Restore(auth.UserID(ctx), uint(id))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	setTodoETag(ctx, todo)
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "Todo successfully restored",
+		Data:    todo,
 	})
 }
 
 func (c *TodoController) Complete(ctx *gin.Context) {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   "Invalid ID",
-			Message: "ID must be a valid number",
-			Code:    http.StatusBadRequest,
-		})
+		ctx.Error(apperror.Validation("ID must be a valid number"))
 		return
 	}
 
-	todo, err := c.service.Complete(uint(id))
+	ifMatch, err := ifMatchVersion(ctx)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "todo not found" {
-			status = http.StatusNotFound
-		}
+		ctx.Error(err)
+		return
+	}
 
-		ctx.JSON(status, dto.ErrorResponse{
-			Error:   "Complete todo failed",
-			Message: err.Error(),
-			Code:    status,
-		})
+	todo, err := c.service.Complete(auth.UserID(ctx), uint(id), ifMatch)
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
 
+	setTodoETag(ctx, todo)
 	ctx.JSON(http.StatusOK, dto.SuccessResponse{
 		Message: "Todo successfully done",
 		Data:    todo,
 	})
 }
+
+// Children lista as subtarefas diretas, com os mesmos filtros e paginação de GetAll
+func (c *TodoController) Children(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.Error(apperror.Validation("ID must be a valid number"))
+		return
+	}
+
+	var query dto.TodoListQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(apperror.InvalidInput(err))
+		return
+	}
+
+	userID := auth.UserID(ctx)
+	if _, err := c.service.GetByID(userID, uint(id)); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	parentID := uint(id)
+	query.ParentID = &parentID
+
+	todos, err := c.service.GetAll(userID, &query)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: todos,
+	})
+}
+
+func (c *TodoController) Tree(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.Error(apperror.Validation("ID must be a valid number"))
+		return
+	}
+
+	tree, err := c.service.Tree(auth.UserID(ctx), uint(id))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: tree,
+	})
+}
+
+func (c *TodoController) History(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.Error(apperror.Validation("ID must be a valid number"))
+		return
+	}
+
+	history, err := c.service.History(auth.UserID(ctx), uint(id))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: history,
+	})
+}
+
+func (c *TodoController) Revert(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.Error(apperror.Validation("ID must be a valid number"))
+		return
+	}
+
+	revision, err := strconv.Atoi(ctx.Param("revision"))
+	if err != nil || revision < 1 {
+		ctx.Error(apperror.Validation("Revision must be a positive number"))
+		return
+	}
+
+	todo, err := c.service.Revert(auth.UserID(ctx), uint(id), revision)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	setTodoETag(ctx, todo)
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "Todo successfully reverted",
+		Data:    todo,
+	})
+}
+
+// Bulk executa um lote de operações em uma única transação. O status da
+// resposta é 200 sempre que o lote é processado; o resultado de cada operação
+// vem em results
+func (c *TodoController) Bulk(ctx *gin.Context) {
+	var req dto.BulkRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(apperror.InvalidInput(err))
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = service.BulkAtomic
+	}
+
+	operations := make([]service.BulkOperation, len(req.Operations))
+	for i, op := range req.Operations {
+		operations[i] = decodeBulkOperation(op)
+	}
+
+	result, err := c.service.Bulk(auth.UserID(ctx), req.Mode, operations)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	response := dto.BulkResponse{
+		Mode:      req.Mode,
+		Committed: result.Committed,
+		Results:   make([]dto.BulkItemResponse, len(result.Items)),
+	}
+	for i, item := range result.Items {
+		op := req.Operations[i]
+		response.Results[i] = bulkItemResponse(ctx, i, op, item)
+		if item.Err == nil {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: response,
+	})
+}
+
+// decodeBulkOperation valida os dados de uma operação do lote com as mesmas
+// regras das rotas equivalentes
+func decodeBulkOperation(op dto.BulkOperation) service.BulkOperation {
+	decoded := service.BulkOperation{Op: op.Op, ID: op.ID}
+	if op.Op != service.BulkCreate && op.ID == 0 {
+		decoded.Invalid = apperror.Validation("id is required")
+		return decoded
+	}
+
+	switch op.Op {
+	case service.BulkCreate:
+		decoded.Create = &dto.CreateTodoRequest{}
+		decoded.Invalid = decodeMessageData(op.Data, decoded.Create)
+	case service.BulkUpdate:
+		decoded.Update = &dto.UpdateTodoRequest{}
+		decoded.Invalid = decodeMessageData(op.Data, decoded.Update)
+	case service.BulkMove:
+		var move dto.MoveTodoRequest
+		if decoded.Invalid = decodeMessageData(op.Data, &move); decoded.Invalid != nil {
+			return decoded
+		}
+		if move.ProjectID == nil && move.ParentID == nil {
+			decoded.Invalid = apperror.Validation("project_id or parent_id is required")
+			return decoded
+		}
+		decoded.Update = &dto.UpdateTodoRequest{ProjectID: move.ProjectID, ParentID: move.ParentID}
+	}
+	return decoded
+}
+
+func bulkItemResponse(ctx *gin.Context, index int, op dto.BulkOperation, item service.BulkItemResult) dto.BulkItemResponse {
+	response := dto.BulkItemResponse{Index: index, Op: op.Op, ID: op.ID}
+	if item.Err == nil {
+		response.Status = http.StatusOK
+		if op.Op == service.BulkCreate {
+			response.Status = http.StatusCreated
+		}
+		if item.Todo != nil {
+			response.ID = item.Todo.ID
+			response.Data = item.Todo
+		}
+		return response
+	}
+
+	response.Error = middleware.NewProblem(ctx, item.Err)
+	response.Status = response.Error.Status
+	return response
+}
+
+// maxImportSize limita o tamanho do arquivo enviado para importação
+const maxImportSize = 10 << 20
+
+// Export envia as tarefas que atendem aos filtros no formato pedido (json por
+// padrão), gravando a resposta à medida que as tarefas são lidas do banco
+func (c *TodoController) Export(ctx *gin.Context) {
+	var query dto.ExportQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(apperror.InvalidInput(err))
+		return
+	}
+	if query.Format == "" {
+		query.Format = transfer.FormatJSON
+	}
+
+	writer, err := transfer.NewWriter(query.Format, ctx.Writer)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("Content-Type", transfer.ContentType(query.Format))
+	ctx.Header("Content-Disposition", `attachment; filename="todos.`+query.Format+`"`)
+
+	written := false
+	err = c.service.Export(auth.UserID(ctx), &query.TodoListQuery, func(todo *dto.TodoResponse) error {
+		written = true
+		return writer.Write(todo)
+	})
+	if err == nil {
+		err = writer.Close()
+	}
+	if err == nil {
+		return
+	}
+
+	if written {
+		// A resposta já começou a ser enviada: fecha a conexão para que o
+		// cliente perceba que o arquivo está incompleto
+		log.Printf("Export todos: %v", err)
+		if conn, _, hijackErr := ctx.Writer.Hijack(); hijackErr == nil {
+			conn.Close()
+		}
+		return
+	}
+
+	ctx.Writer.Header().Del("Content-Disposition")
+	ctx.Error(err)
+}
+
+// Import cria tarefas a partir de um arquivo CSV, JSON ou NDJSON enviado no
+// campo file (multipart). Cada linha é validada como em POST /v1/todos e o
+// relatório traz o resultado de cada uma com o número da linha
+func (c *TodoController) Import(ctx *gin.Context) {
+	var query dto.ImportQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(apperror.InvalidInput(err))
+		return
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportSize)
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.Error(apperror.InvalidInput(err))
+		return
+	}
+
+	format := query.Format
+	if format == "" {
+		format = transfer.FormatFromFilename(header.Filename)
+	}
+	if format == "" {
+		ctx.Error(apperror.Validation("format must be csv, json or ndjson"))
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	defer file.Close()
+
+	parsed, err := transfer.ReadAll(format, file)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	rows := make([]service.ImportRow, len(parsed))
+	for i := range parsed {
+		row := &parsed[i]
+		rows[i] = service.ImportRow{Line: row.Line, Request: &row.Todo, Blank: row.Blank}
+		switch {
+		case row.Err != nil:
+			rows[i].Invalid = apperror.Wrap(apperror.KindValidation, row.Err)
+		case !row.Blank:
+			if err := binding.Validator.ValidateStruct(&row.Todo); err != nil {
+				rows[i].Invalid = apperror.InvalidInput(err)
+			}
+		}
+	}
+
+	results, err := c.service.Import(auth.UserID(ctx), rows, service.ImportOptions{
+		DryRun:         query.DryRun,
+		SkipDuplicates: query.SkipDuplicates,
+		DropReferences: query.DropReferences,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	report := dto.ImportReport{
+		DryRun: query.DryRun,
+		Total:  len(results),
+		Rows:   make([]dto.ImportRowResponse, len(results)),
+	}
+	for i, result := range results {
+		row := dto.ImportRowResponse{Line: result.Line}
+		switch {
+		case result.Err != nil:
+			row.Status = "error"
+			row.Error = middleware.NewProblem(ctx, result.Err)
+			report.Errors++
+		case result.Skipped != "":
+			row.Status, row.Reason = "skipped", result.Skipped
+			report.Skipped++
+		default:
+			row.Status = "created"
+			if !query.DryRun {
+				row.ID = result.Todo.ID
+			}
+			report.Created++
+		}
+		report.Rows[i] = row
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "Import finished",
+		Data:    report,
+	})
+}
+
+// acceptPatch lista os formatos aceitos pelo PATCH (header Accept-Patch)
+const acceptPatch = jsonpatch.MergePatchContentType + ", " + jsonpatch.JSONPatchContentType
+
+// decodePatchedTodo lê o documento resultante do patch com as regras de
+// validação do binding; campos desconhecidos (ex.: id, version) são recusados.
+// As falhas são 422: o pedido é um patch válido, mas o resultado não é
+func decodePatchedTodo(data []byte) (*dto.TodoPatchDocument, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var doc dto.TodoPatchDocument
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, invalidPatchedTodo(err)
+	}
+	if err := binding.Validator.ValidateStruct(&doc); err != nil {
+		return nil, invalidPatchedTodo(err)
+	}
+	return &doc, nil
+}
+
+func invalidPatchedTodo(err error) error {
+	invalid := apperror.InvalidInput(err)
+	invalid.Kind = apperror.KindUnprocessable
+	if len(invalid.Fields) > 0 {
+		invalid.Message = "The patched todo has invalid fields"
+	} else {
+		invalid.Message = "The patched todo is invalid: " + err.Error()
+	}
+	return invalid
+}
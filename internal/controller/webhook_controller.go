@@ -0,0 +1,167 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/auth"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/service"
+)
+
+type WebhookController struct {
+	service service.WebhookService
+}
+
+func NewWebhookController(service service.WebhookService) *WebhookController {
+	return &WebhookController{service: service}
+}
+
+func (c *WebhookController) Create(ctx *gin.Context) {
+	var req dto.CreateWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(apperror.InvalidInput(err))
+		return
+	}
+
+	webhook, err := c.service.Create(auth.UserID(ctx), &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.SuccessResponse{
+		Message: "Webhook successfully created",
+		Data:    webhook,
+	})
+}
+
+func (c *WebhookController) List(ctx *gin.Context) {
+	webhooks, err := c.service.List(auth.UserID(ctx))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: webhooks,
+	})
+}
+
+func (c *WebhookController) GetByID(ctx *gin.Context) {
+	id, ok := parseUintParam(ctx, "id")
+	if !ok {
+		return
+	}
+
+	webhook, err := c.service.GetByID(auth.UserID(ctx), id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: webhook,
+	})
+}
+
+func (c *WebhookController) Update(ctx *gin.Context) {
+	id, ok := parseUintParam(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(apperror.InvalidInput(err))
+		return
+	}
+
+	webhook, err := c.service.Update(auth.UserID(ctx), id, &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "Webhook successfully edited",
+		Data:    webhook,
+	})
+}
+
+func (c *WebhookController) Delete(ctx *gin.Context) {
+	id, ok := parseUintParam(ctx, "id")
+	if !ok {
+		return
+	}
+
+	if err := c.service.Delete(auth.UserID(ctx), id); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "Webhook successfully deleted",
+	})
+}
+
+func (c *WebhookController) Deliveries(ctx *gin.Context) {
+	id, ok := parseUintParam(ctx, "id")
+	if !ok {
+		return
+	}
+
+	deliveries, err := c.service.ListDeliveries(auth.UserID(ctx), id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: deliveries,
+	})
+}
+
+func (c *WebhookController) Delivery(ctx *gin.Context) {
+	id, ok := parseUintParam(ctx, "id")
+	if !ok {
+		return
+	}
+	deliveryID, ok := parseUintParam(ctx, "deliveryId")
+	if !ok {
+		return
+	}
+
+	delivery, err := c.service.GetDelivery(auth.UserID(ctx), id, deliveryID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{
+		Data: delivery,
+	})
+}
+
+func (c *WebhookController) Redeliver(ctx *gin.Context) {
+	id, ok := parseUintParam(ctx, "id")
+	if !ok {
+		return
+	}
+	deliveryID, ok := parseUintParam(ctx, "deliveryId")
+	if !ok {
+		return
+	}
+
+	delivery, err := c.service.Redeliver(auth.UserID(ctx), id, deliveryID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, dto.SuccessResponse{
+		Message: "Delivery queued",
+		Data:    delivery,
+	})
+}
@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+type CreateAPITokenRequest struct {
+	Name      string     `json:"name" binding:"required,min=1,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,oneof=todos:read todos:write"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type APITokenResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedAPITokenResponse inclui o token em texto puro, exibido somente na criação
+type CreatedAPITokenResponse struct {
+	APITokenResponse
+	Token string `json:"token"`
+}
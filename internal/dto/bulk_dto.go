@@ -0,0 +1,46 @@
+package dto
+
+import "encoding/json"
+
+// BulkRequest é um lote de operações executado em uma única transação. Mode
+// "atomic" (padrão) desfaz tudo se uma operação falhar; "best_effort"
+// efetiva as operações que deram certo
+type BulkRequest struct {
+	Mode       string          `json:"mode" binding:"omitempty,oneof=atomic best_effort"`
+	Operations []BulkOperation `json:"operations" binding:"required,min=1,max=500,dive"`
+}
+
+// BulkOperation é uma operação do lote. Data segue o corpo de POST /v1/todos
+// (create), de PUT /v1/todos/:id (update) ou MoveTodoRequest (move); ID
+// identifica a tarefa nas demais operações
+type BulkOperation struct {
+	Op   string          `json:"op" binding:"required,oneof=create update complete delete move"`
+	ID   uint            `json:"id"`
+	Data json.RawMessage `json:"data"`
+}
+
+// MoveTodoRequest move a tarefa para outro projeto e/ou outra tarefa pai,
+// com a mesma semântica de project_id e parent_id em UpdateTodoRequest
+type MoveTodoRequest struct {
+	ProjectID *uint `json:"project_id"`
+	ParentID  *uint `json:"parent_id"`
+}
+
+// BulkItemResponse é o resultado de uma operação, na mesma posição do pedido.
+// Status segue os códigos HTTP da operação equivalente; falhas trazem Error
+type BulkItemResponse struct {
+	Index  int           `json:"index"`
+	Op     string        `json:"op"`
+	ID     uint          `json:"id,omitempty"`
+	Status int           `json:"status"`
+	Data   *TodoResponse `json:"data,omitempty"`
+	Error  *Problem      `json:"error,omitempty"`
+}
+
+type BulkResponse struct {
+	Mode      string             `json:"mode"`
+	Committed bool               `json:"committed"`
+	Succeeded int                `json:"succeeded"`
+	Failed    int                `json:"failed"`
+	Results   []BulkItemResponse `json:"results"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+// CalendarFeedQuery: Events inclui no feed um VEVENT de dia inteiro para cada
+// tarefa aberta com prazo à meia-noite
+type CalendarFeedQuery struct {
+	Events bool `form:"events"`
+}
+
+type CalendarFeedResponse struct {
+	Prefix     string     `json:"prefix"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedCalendarFeedResponse inclui o link secreto, exibido somente na criação
+type CreatedCalendarFeedResponse struct {
+	CalendarFeedResponse
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
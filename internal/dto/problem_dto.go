@@ -0,0 +1,21 @@
+package dto
+
+// ProblemContentType é o media type das respostas de erro (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// Problem é o corpo das respostas de erro. Type identifica o tipo do
+// problema, Title o resume e Detail explica esta ocorrência; Instance é o
+// caminho da requisição. Erros de validação trazem os campos em Errors
+type Problem struct {
+	Type     string              `json:"type"`
+	Title    string              `json:"title"`
+	Status   int                 `json:"status"`
+	Detail   string              `json:"detail,omitempty"`
+	Instance string              `json:"instance,omitempty"`
+	Errors   []ProblemFieldError `json:"errors,omitempty"`
+}
+
+type ProblemFieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+type CreateProjectRequest struct {
+	Name  string `json:"name" binding:"required,min=1,max=100"`
+	Color string `json:"color" binding:"omitempty,hexcolor,len=7"`
+}
+
+type UpdateProjectRequest struct {
+	Name     *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Color    *string `json:"color" binding:"omitempty,hexcolor,len=7"`
+	Archived *bool   `json:"archived"`
+}
+
+type ProjectResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	Archived  bool      `json:"archived"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DeleteProjectQuery define o destino das tarefas do projeto removido:
+// "inbox" (padrão) as mantém sem projeto e "cascade" as remove junto
+type DeleteProjectQuery struct {
+	Todos string `form:"todos,default=inbox" binding:"oneof=inbox cascade"`
+}
@@ -0,0 +1,23 @@
+package dto
+
+import "time"
+
+// CreateReminderRequest aceita exatamente um dos campos: RemindAt para um
+// instante fixo ou MinutesBefore para disparar antes do prazo da tarefa
+type CreateReminderRequest struct {
+	RemindAt      *time.Time `json:"remind_at"`
+	MinutesBefore *int       `json:"minutes_before" binding:"omitempty,min=0,max=525600"`
+}
+
+type ReminderResponse struct {
+	ID            uint       `json:"id"`
+	TodoID        uint       `json:"todo_id"`
+	RemindAt      *time.Time `json:"remind_at,omitempty"`
+	MinutesBefore *int       `json:"minutes_before,omitempty"`
+	FireAt        time.Time  `json:"fire_at"`
+	SentAt        *time.Time `json:"sent_at"`
+	FailedAt      *time.Time `json:"failed_at,omitempty"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
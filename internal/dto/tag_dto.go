@@ -0,0 +1,8 @@
+package dto
+
+// TagResponse traz a tag e quantas tarefas do usuário a utilizam
+type TagResponse struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
@@ -3,43 +3,152 @@ package dto
 import "time"
 
 type CreateTodoRequest struct {
-	Title       string     `json:"title" binding:"required,min=1,max=255"`
-	Description string     `json:"description" binding:"max=1000"`
-	Priority    string     `json:"priority" binding:"omitempty,oneof=low medium high"`
-	DueDate     *time.Time `json:"due_date"`
+	Title       string             `json:"title" binding:"required,min=1,max=255"`
+	Description string             `json:"description" binding:"max=1000"`
+	Priority    string             `json:"priority" binding:"omitempty,oneof=low medium high"`
+	DueDate     *time.Time         `json:"due_date"`
+	ProjectID   *uint              `json:"project_id"`
+	ParentID    *uint              `json:"parent_id"`
+	Tags        []string           `json:"tags" binding:"omitempty,max=20,dive,min=1,max=50"`
+	Recurrence  *RecurrenceRequest `json:"recurrence"`
 }
 
+// RecurrenceRequest define a repetição da tarefa com uma regra no estilo
+// RRULE (ex.: "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10"), avaliada no fuso
+// informado (padrão UTC). Exdates lista datas YYYY-MM-DD sem ocorrência e
+// FromCompletion conta o intervalo de FREQ=DAILY a partir da conclusão.
+// Na atualização, uma regra vazia remove a repetição.
+type RecurrenceRequest struct {
+	Rule           string   `json:"rule" binding:"max=255"`
+	Timezone       string   `json:"timezone" binding:"omitempty,max=64"`
+	Exdates        []string `json:"exdates" binding:"omitempty,max=100,dive,datetime=2006-01-02"`
+	FromCompletion bool     `json:"from_completion"`
+}
+
+// UpdateTodoRequest altera somente os campos informados. Um project_id igual
+// a 0 move a tarefa para a caixa de entrada (sem projeto) e um parent_id
+// igual a 0 a torna uma tarefa raiz. Tags substitui todas as tags da tarefa;
+// uma lista vazia remove todas.
 type UpdateTodoRequest struct {
-	Title       *string    `json:"title" binding:"omitempty,min=1,max=255"`
-	Description *string    `json:"description" binding:"omitempty,max=1000"`
-	Priority    *string    `json:"priority" binding:"omitempty,oneof=low medium high"`
-	DueDate     *time.Time `json:"due_date"`
-	Completed   *bool      `json:"completed"`
+	Title       *string            `json:"title" binding:"omitempty,min=1,max=255"`
+	Description *string            `json:"description" binding:"omitempty,max=1000"`
+	Priority    *string            `json:"priority" binding:"omitempty,oneof=low medium high"`
+	DueDate     *time.Time         `json:"due_date"`
+	Completed   *bool              `json:"completed"`
+	ProjectID   *uint              `json:"project_id"`
+	ParentID    *uint              `json:"parent_id"`
+	Tags        []string           `json:"tags" binding:"omitempty,max=20,dive,min=1,max=50"`
+	Recurrence  *RecurrenceRequest `json:"recurrence"`
+
+	// ClearDueDate remove o prazo. Só é usado pelo PATCH, em que due_date
+	// nulo tem significado; no PUT um campo nulo não é alterado
+	ClearDueDate bool `json:"-"`
+}
+
+// TodoPatchDocument é a parte editável da tarefa, o documento ao qual os
+// patches de PATCH /v1/todos/:id são aplicados. Ao contrário do
+// UpdateTodoRequest, campos nulos ou removidos limpam o valor. As regras de
+// validação são as mesmas da criação
+type TodoPatchDocument struct {
+	Title       string             `json:"title" binding:"required,min=1,max=255"`
+	Description string             `json:"description" binding:"max=1000"`
+	Priority    string             `json:"priority" binding:"required,oneof=low medium high"`
+	DueDate     *time.Time         `json:"due_date"`
+	Completed   bool               `json:"completed"`
+	ProjectID   *uint              `json:"project_id"`
+	ParentID    *uint              `json:"parent_id"`
+	Tags        []string           `json:"tags" binding:"omitempty,max=20,dive,min=1,max=50"`
+	Recurrence  *RecurrenceRequest `json:"recurrence"`
+}
+
+// TodoListQuery representa os parâmetros aceitos na listagem de tarefas.
+// Datas seguem o formato RFC 3339 e todos os filtros são combinados com AND.
+// A paginação por cursor é usada quando Cursor é informado ou quando
+// Pagination é "cursor"; caso contrário vale a paginação por page/size.
+// Com Q a listagem vira uma busca textual ordenada por relevância. Tag pode
+// ser repetido; TagMatch "any" (padrão) exige qualquer uma das tags e "all"
+// exige todas.
+type TodoListQuery struct {
+	Page       int    `form:"page,default=1"`
+	Size       int    `form:"size,default=10"`
+	Cursor     string `form:"cursor"`
+	Pagination string `form:"pagination" binding:"omitempty,oneof=offset cursor"`
+	SkipTotal  bool   `form:"skip_total"`
+	Q          string `form:"q" binding:"omitempty,max=200"`
+
+	Completed *bool      `form:"completed"`
+	Priority  string     `form:"priority" binding:"omitempty,oneof=low medium high"`
+	DueBefore *time.Time `form:"due_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	DueAfter  *time.Time `form:"due_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	HasDue    *bool      `form:"has_due"`
+	Overdue   bool       `form:"overdue"`
+	ProjectID *uint      `form:"project_id"`
+	Inbox     bool       `form:"inbox"`
+	ParentID  *uint      `form:"parent_id"`
+	Tags      []string   `form:"tag" binding:"omitempty,max=20,dive,min=1,max=50"`
+	TagMatch  string     `form:"tag_match" binding:"omitempty,oneof=any all"`
+	Sort      string     `form:"sort"`
 }
 
 type TodoResponse struct {
-	ID          uint       `json:"id"`
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	Completed   bool       `json:"completed"`
-	Priority    string     `json:"priority"`
-	DueDate     *time.Time `json:"due_date"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID          uint                `json:"id"`
+	ProjectID   *uint               `json:"project_id"`
+	ParentID    *uint               `json:"parent_id"`
+	Title       string              `json:"title"`
+	Description string              `json:"description"`
+	Completed   bool                `json:"completed"`
+	Priority    string              `json:"priority"`
+	DueDate     *time.Time          `json:"due_date"`
+	Tags        []string            `json:"tags"`
+	Subtasks    *SubtaskProgress    `json:"subtasks,omitempty"`
+	Recurrence  *RecurrenceResponse `json:"recurrence,omitempty"`
+	Version     int                 `json:"version"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+	Search      *SearchHit          `json:"search,omitempty"`
+}
+
+// RecurrenceResponse traz a regra normalizada e quantas ocorrências já foram
+// concluídas
+type RecurrenceResponse struct {
+	Rule                 string     `json:"rule"`
+	Timezone             string     `json:"timezone"`
+	Exdates              []string   `json:"exdates"`
+	FromCompletion       bool       `json:"from_completion"`
+	Start                *time.Time `json:"start"`
+	CompletedOccurrences int        `json:"completed_occurrences"`
+}
+
+// SubtaskProgress conta as subtarefas diretas da tarefa (ex.: 3 de 5 concluídas)
+type SubtaskProgress struct {
+	Total int `json:"total"`
+	Done  int `json:"done"`
+}
+
+// TodoTreeResponse é a tarefa com suas subtarefas aninhadas
+type TodoTreeResponse struct {
+	TodoResponse
+	Children []TodoTreeResponse `json:"children"`
 }
 
+// SearchHit traz a relevância e o trecho destacado (com <mark>) de um
+// resultado da busca textual
+type SearchHit struct {
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+// TodoListResponse é a página retornada na listagem. Total é omitido quando
+// a contagem é dispensada com skip_total; Page e TotalPages só existem na
+// paginação por offset e NextCursor só na paginação por cursor.
 type TodoListResponse struct {
 	Data       []TodoResponse `json:"data"`
-	Total      int64          `json:"total"`
-	Page       int            `json:"page"`
+	Total      *int64         `json:"total,omitempty"`
+	Page       int            `json:"page,omitempty"`
 	PageSize   int            `json:"page_size"`
-	TotalPages int            `json:"total_pages"`
-}
-
-type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
-	Code    int    `json:"code"`
+	TotalPages int            `json:"total_pages,omitempty"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	HasMore    bool           `json:"has_more"`
 }
 
 type SuccessResponse struct {
@@ -0,0 +1,22 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// FieldChange é o valor de um campo antes e depois de uma operação
+type FieldChange struct {
+	From json.RawMessage `json:"from"`
+	To   json.RawMessage `json:"to"`
+}
+
+type TodoHistoryResponse struct {
+	Revision       int                    `json:"revision"`
+	Operation      string                 `json:"operation"`
+	ActorID        uint                   `json:"actor_id"`
+	Changes        map[string]FieldChange `json:"changes"`
+	State          json.RawMessage        `json:"state"`
+	SourceRevision *int                   `json:"source_revision,omitempty"`
+	CreatedAt      time.Time              `json:"created_at"`
+}
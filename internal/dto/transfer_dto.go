@@ -0,0 +1,35 @@
+package dto
+
+// ExportQuery aceita os mesmos filtros da listagem; paginação e ordenação
+// são ignoradas
+type ExportQuery struct {
+	TodoListQuery
+	Format string `form:"format" binding:"omitempty,oneof=csv json ndjson"`
+}
+
+// ImportQuery: Format é deduzido da extensão do arquivo quando omitido
+type ImportQuery struct {
+	Format         string `form:"format" binding:"omitempty,oneof=csv json ndjson"`
+	DryRun         bool   `form:"dry_run"`
+	SkipDuplicates bool   `form:"skip_duplicates"`
+	DropReferences bool   `form:"drop_references"`
+}
+
+// ImportRowResponse é o resultado de uma linha do arquivo: created, skipped
+// (com Reason) ou error
+type ImportRowResponse struct {
+	Line   int      `json:"line"`
+	Status string   `json:"status"`
+	ID     uint     `json:"id,omitempty"`
+	Reason string   `json:"reason,omitempty"`
+	Error  *Problem `json:"error,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun  bool                `json:"dry_run"`
+	Total   int                 `json:"total"`
+	Created int                 `json:"created"`
+	Skipped int                 `json:"skipped"`
+	Errors  int                 `json:"errors"`
+	Rows    []ImportRowResponse `json:"rows"`
+}
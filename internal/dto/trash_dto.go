@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+type TrashQuery struct {
+	Page int `form:"page,default=1"`
+	Size int `form:"size,default=10"`
+}
+
+// TrashItemResponse é uma tarefa na lixeira. PurgeAt indica quando ela será
+// apagada definitivamente e é omitido quando não há prazo de retenção
+type TrashItemResponse struct {
+	TodoResponse
+	DeletedAt time.Time  `json:"deleted_at"`
+	PurgeAt   *time.Time `json:"purge_at,omitempty"`
+}
+
+type TrashListResponse struct {
+	Data       []TrashItemResponse `json:"data"`
+	Total      int64               `json:"total"`
+	Page       int                 `json:"page"`
+	PageSize   int                 `json:"page_size"`
+	TotalPages int                 `json:"total_pages"`
+	HasMore    bool                `json:"has_more"`
+}
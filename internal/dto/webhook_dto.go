@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" binding:"required,url,max=2048"`
+	Secret string   `json:"secret" binding:"omitempty,min=16,max=100"`
+	Events []string `json:"events" binding:"required,min=1,dive,oneof=todo.created todo.updated todo.completed todo.deleted todo.restored"`
+	Active *bool    `json:"active"`
+}
+
+type UpdateWebhookRequest struct {
+	URL    *string  `json:"url" binding:"omitempty,url,max=2048"`
+	Secret *string  `json:"secret" binding:"omitempty,min=16,max=100"`
+	Events []string `json:"events" binding:"omitempty,min=1,dive,oneof=todo.created todo.updated todo.completed todo.deleted todo.restored"`
+	Active *bool    `json:"active"`
+}
+
+type WebhookResponse struct {
+	ID        uint      `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CreatedWebhookResponse inclui o segredo de assinatura, exibido somente na criação
+type CreatedWebhookResponse struct {
+	WebhookResponse
+	Secret string `json:"secret"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             uint                     `json:"id"`
+	WebhookID      uint                     `json:"webhook_id"`
+	Event          string                   `json:"event"`
+	Status         string                   `json:"status"`
+	Attempts       int                      `json:"attempts"`
+	NextAttemptAt  *time.Time               `json:"next_attempt_at"`
+	LastStatusCode int                      `json:"last_status_code"`
+	LastError      string                   `json:"last_error"`
+	DeliveredAt    *time.Time               `json:"delivered_at"`
+	CreatedAt      time.Time                `json:"created_at"`
+	Payload        json.RawMessage          `json:"payload,omitempty"`
+	History        []WebhookAttemptResponse `json:"history,omitempty"`
+}
+
+type WebhookAttemptResponse struct {
+	ID         uint      `json:"id"`
+	StatusCode int       `json:"status_code"`
+	Error      string    `json:"error"`
+	DurationMs int64     `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
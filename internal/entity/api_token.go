@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APIToken é um token de acesso pessoal de longa duração. Apenas o hash
+// SHA-256 é armazenado; o valor original é exibido uma única vez.
+type APIToken struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	UserID     uint           `gorm:"not null;index" json:"user_id"`
+	Name       string         `gorm:"not null;size:100" json:"name"`
+	Prefix     string         `gorm:"not null;size:16" json:"prefix"`
+	TokenHash  string         `gorm:"not null;size:64;uniqueIndex" json:"-"`
+	Scopes     string         `gorm:"not null;size:255" json:"scopes"`
+	ExpiresAt  *time.Time     `json:"expires_at"`
+	LastUsedAt *time.Time     `json:"last_used_at"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+}
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// CalendarFeed é o link secreto do feed iCalendar de um usuário. Como nos
+// tokens de API, apenas o hash SHA-256 é armazenado; rotacionar o link
+// substitui o registro
+type CalendarFeed struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"not null;uniqueIndex" json:"user_id"`
+	Prefix     string     `gorm:"not null;size:16" json:"prefix"`
+	TokenHash  string     `gorm:"not null;size:64;uniqueIndex" json:"-"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
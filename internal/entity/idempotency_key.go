@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// IdempotencyKey guarda a resposta de uma requisição enviada com o header
+// Idempotency-Key, para ser repetida nas novas tentativas do cliente. Enquanto
+// a requisição original não termina (Completed falso) o registro funciona
+// como trava: LockedAt marca quando ela começou
+type IdempotencyKey struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	UserID      uint      `gorm:"not null;uniqueIndex:idx_idempotency_keys_user_key" json:"user_id"`
+	Key         string    `gorm:"column:idempotency_key;not null;size:255;uniqueIndex:idx_idempotency_keys_user_key" json:"key"`
+	RequestHash string    `gorm:"not null;size:64" json:"-"`
+	Completed   bool      `gorm:"not null;default:false" json:"completed"`
+	StatusCode  int       `json:"status_code"`
+	Headers     string    `gorm:"type:text" json:"-"`
+	Body        string    `gorm:"type:text" json:"-"`
+	LockedAt    time.Time `gorm:"not null" json:"locked_at"`
+	ExpiresAt   time.Time `gorm:"not null;index" json:"expires_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
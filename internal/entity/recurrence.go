@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// Recurrence é a regra de repetição de uma tarefa, gravada nas colunas
+// recurrence_* da própria tarefa. Start é o início da série (DTSTART) e
+// Completed conta as ocorrências já concluídas, usado com COUNT
+type Recurrence struct {
+	Rule           string `gorm:"size:255"`
+	Timezone       string `gorm:"size:64"`
+	Exdates        string `gorm:"type:text"` // datas YYYY-MM-DD separadas por vírgula
+	FromCompletion bool   `gorm:"default:false"`
+	Start          *time.Time
+	Completed      int `gorm:"default:0"`
+}
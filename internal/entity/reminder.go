@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// Reminder é um lembrete de uma tarefa: absoluto (RemindAt) ou relativo ao
+// prazo (MinutesBefore). FireAt guarda o instante calculado de disparo e é
+// recalculado quando o prazo da tarefa muda. ClaimedUntil reserva o lembrete
+// para uma instância do agendador enquanto ele é enviado
+type Reminder struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	UserID        uint       `gorm:"not null;index" json:"user_id"`
+	TodoID        uint       `gorm:"not null;index" json:"todo_id"`
+	Todo          *Todo      `json:"-"`
+	RemindAt      *time.Time `json:"remind_at"`
+	MinutesBefore *int       `json:"minutes_before"`
+	FireAt        time.Time  `gorm:"not null;index" json:"fire_at"`
+	SentAt        *time.Time `json:"sent_at"`
+	FailedAt      *time.Time `json:"failed_at"`
+	ClaimedUntil  *time.Time `json:"-"`
+	Attempts      int        `gorm:"default:0" json:"attempts"`
+	LastError     string     `gorm:"size:500" json:"last_error"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
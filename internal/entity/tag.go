@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// Tag é um rótulo livre do usuário. O nome é armazenado normalizado e é
+// único por dono
+type Tag struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_tags_user_name" json:"user_id"`
+	Name      string    `gorm:"not null;size:50;uniqueIndex:idx_tags_user_name" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -8,12 +8,22 @@ import (
 
 type Todo struct {
 	ID          uint           `gorm:"primaryKey" json:"id"`
+	UserID      uint           `gorm:"not null;index" json:"user_id"`
+	ProjectID   *uint          `gorm:"index" json:"project_id"`
+	ParentID    *uint          `gorm:"index" json:"parent_id"`
 	Title       string         `gorm:"not null;size:255" json:"title"`
 	Description string         `gorm:"type:text" json:"description"`
 	Completed   bool           `gorm:"default:false" json:"completed"`
 	Priority    string         `gorm:"default:medium;size:20" json:"priority"`
 	DueDate     *time.Time     `json:"due_date"`
+	Tags        []Tag          `gorm:"many2many:todo_tags" json:"tags,omitempty"`
+	Recurrence  Recurrence     `gorm:"embedded;embeddedPrefix:recurrence_" json:"-"`
+	Version     int            `gorm:"not null;default:1" json:"version"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Contadores das subtarefas diretas, preenchidos pelo repositório
+	SubtasksTotal int `gorm:"-:all" json:"-"`
+	SubtasksDone  int `gorm:"-:all" json:"-"`
 }
@@ -0,0 +1,32 @@
+package entity
+
+import "time"
+
+// Operações registradas no histórico das tarefas
+const (
+	HistoryCreate   = "create"
+	HistoryUpdate   = "update"
+	HistoryComplete = "complete"
+	HistoryDelete   = "delete"
+	HistoryRevert   = "revert"
+	HistoryRestore  = "restore"
+)
+
+// TodoHistory é uma revisão de uma tarefa. Revision cresce a cada mudança da
+// tarefa e é independente de Todo.Version, o contador de concorrência (que
+// também muda em gravações sem diferença). Snapshot guarda o estado após a
+// operação (ou o último estado, na remoção) e Changes o diff campo a campo em
+// relação à revisão anterior, ambos em JSON. SourceRevision indica a revisão
+// restaurada por um revert
+type TodoHistory struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	TodoID         uint      `gorm:"not null;uniqueIndex:idx_todo_histories_revision" json:"todo_id"`
+	Revision       int       `gorm:"not null;uniqueIndex:idx_todo_histories_revision" json:"revision"`
+	UserID         uint      `gorm:"not null;index" json:"user_id"`
+	ActorID        uint      `gorm:"not null" json:"actor_id"`
+	Operation      string    `gorm:"not null;size:20" json:"operation"`
+	Changes        string    `gorm:"type:text" json:"changes"`
+	Snapshot       string    `gorm:"type:text;not null" json:"snapshot"`
+	SourceRevision *int      `json:"source_revision"`
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -0,0 +1,55 @@
+package entity
+
+import "time"
+
+// Webhook é uma inscrição do usuário para receber eventos de tarefas via
+// POST. Events guarda os tipos inscritos separados por vírgula
+type Webhook struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"`
+	URL       string    `gorm:"not null;size:2048" json:"url"`
+	Secret    string    `gorm:"not null;size:100" json:"-"`
+	Events    string    `gorm:"not null;size:255" json:"events"`
+	Active    bool      `gorm:"not null" json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Situações de uma entrega de webhook
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// WebhookDelivery é um evento a ser entregue a um webhook. Payload guarda o
+// corpo exato enviado, para que a entrega possa ser repetida sem mudanças.
+// NextAttemptAt indica quando o worker deve tentar (de novo) e ClaimedUntil
+// reserva a entrega para uma instância do worker durante o envio
+type WebhookDelivery struct {
+	ID             uint             `gorm:"primaryKey" json:"id"`
+	WebhookID      uint             `gorm:"not null;index" json:"webhook_id"`
+	Webhook        *Webhook         `json:"-"`
+	Event          string           `gorm:"not null;size:50" json:"event"`
+	Payload        string           `gorm:"type:text;not null" json:"payload"`
+	Status         string           `gorm:"not null;size:20;index;default:pending" json:"status"`
+	Attempts       int              `gorm:"default:0" json:"attempts"`
+	NextAttemptAt  *time.Time       `gorm:"index" json:"next_attempt_at"`
+	ClaimedUntil   *time.Time       `json:"-"`
+	LastStatusCode int              `json:"last_status_code"`
+	LastError      string           `gorm:"size:500" json:"last_error"`
+	DeliveredAt    *time.Time       `json:"delivered_at"`
+	AttemptLog     []WebhookAttempt `gorm:"foreignKey:DeliveryID;constraint:OnDelete:CASCADE" json:"-"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+}
+
+// WebhookAttempt registra uma tentativa de envio de uma entrega
+type WebhookAttempt struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	DeliveryID uint      `gorm:"not null;index" json:"delivery_id"`
+	StatusCode int       `json:"status_code"`
+	Error      string    `gorm:"size:500" json:"error"`
+	DurationMs int64     `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
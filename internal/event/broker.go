@@ -0,0 +1,166 @@
+package event
+
+import (
+	"sync"
+)
+
+// Message é um evento numerado pelo broker. IDs são crescentes e só valem
+// enquanto o processo estiver rodando
+type Message struct {
+	ID    uint64
+	Event Event
+}
+
+// Subscription recebe os eventos de um usuário. C é fechado quando o broker
+// encerra ou quando o consumidor não acompanha o ritmo dos eventos (o buffer
+// enche); nesse caso Dropped retorna true e o cliente deve reconectar
+type Subscription struct {
+	C <-chan Message
+
+	ch      chan Message
+	userID  uint
+	dropped bool
+	closed  bool
+}
+
+// Dropped informa se a inscrição foi encerrada por lentidão do consumidor.
+// Só deve ser consultado depois que C foi fechado
+func (s *Subscription) Dropped() bool {
+	return s.dropped
+}
+
+const (
+	defaultReplaySize       = 1000
+	defaultSubscriberBuffer = 64
+)
+
+// Broker distribui os eventos publicados aos inscritos do mesmo usuário e
+// guarda os últimos eventos em um buffer circular para que clientes
+// reconectados recebam o que perderam
+type Broker struct {
+	mu          sync.Mutex
+	seq         uint64
+	replay      []Message
+	next        int // posição de escrita no buffer circular
+	subscribers map[*Subscription]struct{}
+	bufferSize  int
+	closed      bool
+}
+
+// NewBroker cria um broker que guarda até replaySize eventos para replay e
+// permite até subscriberBuffer eventos pendentes por inscrito. Valores
+// zerados usam os padrões
+func NewBroker(replaySize, subscriberBuffer int) *Broker {
+	if replaySize <= 0 {
+		replaySize = defaultReplaySize
+	}
+	if subscriberBuffer <= 0 {
+		subscriberBuffer = defaultSubscriberBuffer
+	}
+	return &Broker{
+		replay:      make([]Message, 0, replaySize),
+		subscribers: make(map[*Subscription]struct{}),
+		bufferSize:  subscriberBuffer,
+	}
+}
+
+func (b *Broker) Publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	b.seq++
+	message := Message{ID: b.seq, Event: event}
+	if len(b.replay) < cap(b.replay) {
+		b.replay = append(b.replay, message)
+	} else {
+		b.replay[b.next] = message
+		b.next = (b.next + 1) % len(b.replay)
+	}
+
+	for sub := range b.subscribers {
+		if sub.userID != event.UserID {
+			continue
+		}
+		select {
+		case sub.ch <- message:
+		default:
+			// Consumidor lento: é desconectado em vez de atrasar os demais
+			sub.dropped = true
+			b.remove(sub)
+		}
+	}
+}
+
+// Subscribe inscreve o usuário. Com lastID > 0 retorna também os eventos do
+// usuário publicados depois dele; complete é false quando parte desses eventos
+// já saiu do buffer (ou lastID não pertence a este processo) e o cliente
+// precisa recarregar o estado
+func (b *Broker) Subscribe(userID uint, lastID uint64) (sub *Subscription, missed []Message, complete bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan Message, b.bufferSize)
+	sub = &Subscription{C: ch, ch: ch, userID: userID}
+	if b.closed {
+		sub.closed = true
+		close(ch)
+		return sub, nil, true
+	}
+	b.subscribers[sub] = struct{}{}
+
+	if lastID == 0 {
+		return sub, nil, true
+	}
+	if lastID > b.seq {
+		return sub, nil, false
+	}
+
+	complete = len(b.replay) == 0 || lastID+1 >= b.oldest()
+	for i := 0; i < len(b.replay); i++ {
+		message := b.replay[(b.next+i)%len(b.replay)]
+		if message.ID > lastID && message.Event.UserID == userID {
+			missed = append(missed, message)
+		}
+	}
+	return sub, missed, complete
+}
+
+// Unsubscribe encerra a inscrição; pode ser chamado mais de uma vez
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.remove(sub)
+}
+
+// Close encerra todas as inscrições e ignora os próximos eventos. É usado no
+// desligamento do servidor para liberar as conexões abertas
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subscribers {
+		b.remove(sub)
+	}
+}
+
+func (b *Broker) remove(sub *Subscription) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	delete(b.subscribers, sub)
+	close(sub.ch)
+}
+
+// oldest retorna o ID do evento mais antigo ainda no buffer
+func (b *Broker) oldest() uint64 {
+	if len(b.replay) < cap(b.replay) {
+		return b.replay[0].ID
+	}
+	return b.replay[b.next].ID
+}
@@ -0,0 +1,76 @@
+// Package event define os eventos do ciclo de vida das tarefas publicados
+// pela camada de serviço e consumidos por webhooks e streams.
+package event
+
+import (
+	"time"
+
+	"github.com/vinibsi/todo-api/internal/dto"
+)
+
+// Tipos de evento de tarefas
+const (
+	TodoCreated   = "todo.created"
+	TodoUpdated   = "todo.updated"
+	TodoCompleted = "todo.completed"
+	TodoDeleted   = "todo.deleted"
+	TodoRestored  = "todo.restored"
+)
+
+// Types lista todos os tipos de evento na ordem em que são documentados
+var Types = []string{TodoCreated, TodoUpdated, TodoCompleted, TodoDeleted, TodoRestored}
+
+func IsValidType(eventType string) bool {
+	for _, t := range Types {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Event é uma mudança em uma tarefa. Todo traz o estado após a mudança (ou o
+// último estado, no caso de todo.deleted)
+type Event struct {
+	Type       string            `json:"type"`
+	UserID     uint              `json:"-"`
+	TodoID     uint              `json:"todo_id"`
+	Todo       *dto.TodoResponse `json:"todo"`
+	OccurredAt time.Time         `json:"occurred_at"`
+}
+
+// Publisher recebe os eventos publicados. Publish não deve bloquear por muito
+// tempo, pois é chamado dentro das requisições
+type Publisher interface {
+	Publish(event Event)
+}
+
+// Publishers repassa cada evento para todos os publishers da lista
+type Publishers []Publisher
+
+func (p Publishers) Publish(event Event) {
+	for _, publisher := range p {
+		publisher.Publish(event)
+	}
+}
+
+// Discard ignora todos os eventos
+var Discard Publisher = Publishers(nil)
+
+// Buffer guarda os eventos para publicá-los depois, por exemplo só após o
+// commit de uma transação
+type Buffer struct {
+	events []Event
+}
+
+func (b *Buffer) Publish(event Event) {
+	b.events = append(b.events, event)
+}
+
+// Flush repassa os eventos guardados para publisher, na ordem, e esvazia o buffer
+func (b *Buffer) Flush(publisher Publisher) {
+	for _, event := range b.events {
+		publisher.Publish(event)
+	}
+	b.events = nil
+}
@@ -0,0 +1,206 @@
+// Package ical gera o feed iCalendar (RFC 5545) das tarefas com prazo, para
+// assinatura em aplicativos de calendário.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	_ "time/tzdata"
+	"unicode/utf8"
+
+	"github.com/vinibsi/todo-api/internal/dto"
+)
+
+// ContentType é o tipo MIME do feed
+const ContentType = "text/calendar; charset=utf-8"
+
+// ProdID identifica o gerador do feed
+const ProdID = "-//todo-api//Todos//EN"
+
+// Tamanho máximo de uma linha, em octetos, antes da quebra (seção 3.1)
+const maxLineOctets = 75
+
+const (
+	dateTimeLayout = "20060102T150405Z"
+	dateLayout     = "20060102"
+)
+
+// priorities converte a prioridade da tarefa na escala 1 (alta) a 9 (baixa)
+var priorities = map[string]int{"high": 1, "medium": 5, "low": 9}
+
+// Options: Name é o nome exibido do calendário, Domain o sufixo dos UIDs e
+// Events inclui um VEVENT para cada tarefa aberta com prazo de dia inteiro
+type Options struct {
+	Name   string
+	Domain string
+	Events bool
+}
+
+// Writer grava as tarefas uma a uma como VTODO. Nada é escrito antes da
+// primeira chamada de Write ou Close; Close completa o calendário
+type Writer struct {
+	out     *bufio.Writer
+	opts    Options
+	started bool
+}
+
+func NewWriter(w io.Writer, opts Options) *Writer {
+	if opts.Domain == "" {
+		opts.Domain = "todo-api"
+	}
+	return &Writer{out: bufio.NewWriter(w), opts: opts}
+}
+
+func (w *Writer) start() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", ProdID)
+	w.line("CALSCALE", "GREGORIAN")
+	if w.opts.Name != "" {
+		w.line("X-WR-CALNAME", escapeText(w.opts.Name))
+	}
+	return w.flushErr()
+}
+
+// Write grava a tarefa; tarefas sem prazo são ignoradas. DTSTAMP usa a data
+// da última alteração para que o feed só mude quando as tarefas mudarem
+func (w *Writer) Write(todo *dto.TodoResponse) error {
+	if todo.DueDate == nil {
+		return nil
+	}
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	day, allDay := allDayDate(todo)
+
+	w.line("BEGIN", "VTODO")
+	w.line("UID", w.uid(todo.ID, ""))
+	w.line("DTSTAMP", formatDateTime(todo.UpdatedAt))
+	w.line("CREATED", formatDateTime(todo.CreatedAt))
+	w.line("LAST-MODIFIED", formatDateTime(todo.UpdatedAt))
+	w.line("SUMMARY", escapeText(todo.Title))
+	if todo.Description != "" {
+		w.line("DESCRIPTION", escapeText(todo.Description))
+	}
+	if allDay {
+		w.line("DUE;VALUE=DATE", day.Format(dateLayout))
+	} else {
+		w.line("DUE", formatDateTime(*todo.DueDate))
+	}
+	if priority, ok := priorities[todo.Priority]; ok {
+		w.line("PRIORITY", fmt.Sprint(priority))
+	}
+	if todo.Completed {
+		// A API não guarda o momento da conclusão; a última alteração é a
+		// melhor aproximação
+		w.line("STATUS", "COMPLETED")
+		w.line("COMPLETED", formatDateTime(todo.UpdatedAt))
+		w.line("PERCENT-COMPLETE", "100")
+	} else {
+		w.line("STATUS", "NEEDS-ACTION")
+	}
+	if len(todo.Tags) > 0 {
+		w.line("CATEGORIES", categories(todo.Tags))
+	}
+	w.line("END", "VTODO")
+
+	if w.opts.Events && allDay && !todo.Completed {
+		w.line("BEGIN", "VEVENT")
+		w.line("UID", w.uid(todo.ID, "-due"))
+		w.line("DTSTAMP", formatDateTime(todo.UpdatedAt))
+		w.line("DTSTART;VALUE=DATE", day.Format(dateLayout))
+		w.line("DTEND;VALUE=DATE", day.AddDate(0, 0, 1).Format(dateLayout))
+		w.line("SUMMARY", escapeText(todo.Title))
+		if todo.Description != "" {
+			w.line("DESCRIPTION", escapeText(todo.Description))
+		}
+		w.line("TRANSP", "TRANSPARENT")
+		w.line("END", "VEVENT")
+	}
+	return w.flushErr()
+}
+
+func (w *Writer) Close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	w.line("END", "VCALENDAR")
+	return w.out.Flush()
+}
+
+func (w *Writer) uid(id uint, suffix string) string {
+	return fmt.Sprintf("todo-%d%s@%s", id, suffix, w.opts.Domain)
+}
+
+// line grava uma propriedade terminada em CRLF, quebrando linhas longas em
+// no máximo 75 octetos sem separar caracteres UTF-8. Erros ficam no buffer e
+// são devolvidos por flushErr
+func (w *Writer) line(name, value string) {
+	content := name + ":" + value
+	limit := maxLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		w.out.WriteString(content[:cut])
+		w.out.WriteString("\r\n ")
+		content = content[cut:]
+		// O espaço da continuação conta no tamanho da linha
+		limit = maxLineOctets - 1
+	}
+	w.out.WriteString(content)
+	w.out.WriteString("\r\n")
+}
+
+// flushErr devolve o erro de escrita pendente no buffer, sem forçar a escrita
+func (w *Writer) flushErr() error {
+	_, err := w.out.Write(nil)
+	return err
+}
+
+// allDayDate indica se o prazo cai à meia-noite, no fuso da repetição ou em
+// UTC, e devolve o dia correspondente
+func allDayDate(todo *dto.TodoResponse) (time.Time, bool) {
+	loc := time.UTC
+	if rec := todo.Recurrence; rec != nil && rec.Timezone != "" {
+		if tz, err := time.LoadLocation(rec.Timezone); err == nil {
+			loc = tz
+		}
+	}
+
+	due := todo.DueDate.In(loc)
+	if due.Hour() != 0 || due.Minute() != 0 || due.Second() != 0 || due.Nanosecond() != 0 {
+		return time.Time{}, false
+	}
+	return due, true
+}
+
+func formatDateTime(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout)
+}
+
+// escapeText aplica o escape de valores TEXT (seção 3.3.11)
+func escapeText(value string) string {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	return textEscaper.Replace(value)
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`, "\r", "")
+
+func categories(tags []string) string {
+	escaped := make([]string, len(tags))
+	for i, tag := range tags {
+		escaped[i] = escapeText(tag)
+	}
+	return strings.Join(escaped, ",")
+}
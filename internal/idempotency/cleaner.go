@@ -0,0 +1,104 @@
+// Package idempotency apaga as chaves de idempotência cujo prazo de repetição
+// já terminou.
+package idempotency
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/vinibsi/todo-api/internal/repository"
+)
+
+// Config ajusta a limpeza das chaves. Valores zerados usam os padrões
+type Config struct {
+	Interval  time.Duration // intervalo entre as limpezas
+	BatchSize int           // chaves apagadas por consulta
+	Now       func() time.Time
+}
+
+const (
+	defaultInterval  = time.Hour
+	defaultBatchSize = 500
+)
+
+// Cleaner apaga periodicamente as chaves vencidas. As chaves vencidas já são
+// ignoradas pelo middleware, a limpeza só evita que a tabela cresça
+type Cleaner struct {
+	repo   repository.IdempotencyRepository
+	config Config
+	logger *log.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+func NewCleaner(repo repository.IdempotencyRepository, config Config) *Cleaner {
+	if config.Interval <= 0 {
+		config.Interval = defaultInterval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultBatchSize
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	return &Cleaner{repo: repo, config: config, logger: log.Default()}
+}
+
+// Start inicia a goroutine da limpeza, que termina com Stop ou quando ctx é
+// cancelado
+func (c *Cleaner) Start(ctx context.Context) {
+	ctx, c.cancel = context.WithCancel(ctx)
+	c.done = make(chan struct{})
+
+	go func() {
+		defer close(c.done)
+
+		ticker := time.NewTicker(c.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			if deleted, err := c.RunOnce(ctx); err != nil && ctx.Err() == nil {
+				c.logger.Printf("Idempotency cleaner: %v", err)
+			} else if deleted > 0 {
+				c.logger.Printf("Idempotency cleaner: %d expired keys deleted", deleted)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop interrompe a limpeza e espera o lote em andamento terminar
+func (c *Cleaner) Stop() {
+	c.once.Do(func() {
+		if c.cancel == nil {
+			return
+		}
+		c.cancel()
+		<-c.done
+	})
+}
+
+// RunOnce apaga em lotes as chaves vencidas, retornando quantas foram apagadas
+func (c *Cleaner) RunOnce(ctx context.Context) (int, error) {
+	now := c.config.Now()
+
+	total := 0
+	for ctx.Err() == nil {
+		deleted, err := c.repo.DeleteExpired(now, c.config.BatchSize)
+		total += deleted
+		if err != nil || deleted == 0 {
+			return total, err
+		}
+	}
+	return total, ctx.Err()
+}
@@ -0,0 +1,91 @@
+// Package live implementa o canal WebSocket de atualizações em tempo real:
+// o cliente se inscreve em projetos ou tarefas, recebe os eventos de mudança
+// e envia alterações que passam pelas mesmas validações da API REST.
+//
+// Todas as mensagens são objetos JSON em frames de texto.
+//
+// Cliente → servidor (id é opcional e volta no ack correspondente):
+//
+//	{"id": "1", "type": "subscribe", "project_id": 3}
+//	{"id": "2", "type": "subscribe", "todo_ids": [10, 11]}
+//	{"id": "3", "type": "unsubscribe", "project_id": 3}
+//	{"id": "4", "type": "create", "data": {"title": "Nova tarefa", "project_id": 3}}
+//	{"id": "5", "type": "update", "todo_id": 10, "data": {"priority": "high"}}
+//	{"id": "6", "type": "complete", "todo_id": 10}
+//	{"id": "7", "type": "delete", "todo_id": 10}
+//	{"id": "8", "type": "ping"}
+//
+// Servidor → cliente:
+//
+//	{"type": "ack", "id": "4", "ok": true, "data": {...tarefa...}}
+//	{"type": "ack", "id": "5", "ok": false, "error": {"code": 404, "message": "todo not found"}}
+//	{"type": "event", "event_id": 42, "data": {"type": "todo.updated", "todo_id": 10, "todo": {...}, "occurred_at": "..."}}
+//	{"type": "pong", "id": "8"}
+//
+// Os códigos de erro seguem os status HTTP da API REST. O evento gerado por
+// uma alteração pode chegar antes do ack dela.
+package live
+
+import (
+	"encoding/json"
+
+	"github.com/vinibsi/todo-api/internal/event"
+)
+
+// Tipos de mensagem enviados pelo cliente
+const (
+	TypeSubscribe   = "subscribe"
+	TypeUnsubscribe = "unsubscribe"
+	TypeCreate      = "create"
+	TypeUpdate      = "update"
+	TypeComplete    = "complete"
+	TypeDelete      = "delete"
+	TypePing        = "ping"
+)
+
+// Tipos de mensagem enviados pelo servidor
+const (
+	TypeAck   = "ack"
+	TypeEvent = "event"
+	TypePong  = "pong"
+)
+
+type ClientMessage struct {
+	ID        string          `json:"id,omitempty"`
+	Type      string          `json:"type"`
+	ProjectID *uint           `json:"project_id,omitempty"`
+	TodoIDs   []uint          `json:"todo_ids,omitempty"`
+	TodoID    uint            `json:"todo_id,omitempty"`
+	Data      json.RawMessage `json:"data,omitempty"`
+}
+
+type ServerMessage struct {
+	Type    string      `json:"type"`
+	ID      string      `json:"id,omitempty"`
+	EventID uint64      `json:"event_id,omitempty"`
+	OK      *bool       `json:"ok,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+	Error   *Error      `json:"error,omitempty"`
+}
+
+// Error descreve a falha de uma mensagem do cliente; Code segue os status HTTP
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Ack confirma uma mensagem do cliente
+func Ack(id string, data interface{}) ServerMessage {
+	ok := true
+	return ServerMessage{Type: TypeAck, ID: id, OK: &ok, Data: data}
+}
+
+// Nack informa que a mensagem do cliente não pôde ser processada
+func Nack(id string, code int, message string) ServerMessage {
+	ok := false
+	return ServerMessage{Type: TypeAck, ID: id, OK: &ok, Error: &Error{Code: code, Message: message}}
+}
+
+func eventMessage(message event.Message) ServerMessage {
+	return ServerMessage{Type: TypeEvent, EventID: message.ID, Data: message.Event}
+}
@@ -0,0 +1,61 @@
+package live
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/websocket"
+	"github.com/vinibsi/todo-api/internal/auth"
+	"github.com/vinibsi/todo-api/internal/event"
+)
+
+// Server aceita as conexões WebSocket e as liga ao broker de eventos
+type Server struct {
+	broker   *event.Broker
+	handler  Handler
+	config   Config
+	upgrader websocket.Upgrader
+}
+
+// NewServer cria o servidor. allowedOrigins lista as origens aceitas no
+// handshake ("*" aceita qualquer uma); vazio aceita apenas a própria origem
+func NewServer(broker *event.Broker, handler Handler, config Config, allowedOrigins []string) *Server {
+	server := &Server{broker: broker, handler: handler, config: config.withDefaults()}
+	if len(allowedOrigins) > 0 {
+		server.upgrader.CheckOrigin = checkOrigin(allowedOrigins)
+	}
+	return server
+}
+
+// Serve faz o handshake e atende a conexão até ela ser encerrada. Em caso de
+// falha no handshake a resposta de erro já foi escrita
+func (srv *Server) Serve(w http.ResponseWriter, r *http.Request, principal *auth.Principal) error {
+	conn, err := srv.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return err
+	}
+
+	newSession(conn, principal, srv.config).run(srv.broker, srv.handler)
+	return nil
+}
+
+func checkOrigin(allowed []string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			// Clientes que não são navegadores não enviam Origin
+			return true
+		}
+		parsed, err := url.Parse(origin)
+		if err != nil {
+			return false
+		}
+		for _, candidate := range allowed {
+			if candidate == "*" || strings.EqualFold(strings.TrimSuffix(candidate, "/"), parsed.Scheme+"://"+parsed.Host) {
+				return true
+			}
+		}
+		return false
+	}
+}
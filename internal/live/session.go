@@ -0,0 +1,253 @@
+package live
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vinibsi/todo-api/internal/auth"
+	"github.com/vinibsi/todo-api/internal/event"
+)
+
+// Config ajusta as conexões. Valores zerados usam os padrões
+type Config struct {
+	PingInterval   time.Duration // intervalo entre pings do servidor
+	PongWait       time.Duration // tempo sem resposta até a conexão ser encerrada
+	WriteWait      time.Duration // limite para escrever uma mensagem
+	SendBuffer     int           // mensagens pendentes antes de o cliente ser desconectado
+	MaxMessageSize int64         // tamanho máximo de uma mensagem do cliente
+}
+
+const (
+	defaultPingInterval   = 30 * time.Second
+	defaultPongWait       = 60 * time.Second
+	defaultWriteWait      = 10 * time.Second
+	defaultSendBuffer     = 64
+	defaultMaxMessageSize = 64 * 1024
+)
+
+func (c Config) withDefaults() Config {
+	if c.PingInterval <= 0 {
+		c.PingInterval = defaultPingInterval
+	}
+	if c.PongWait <= c.PingInterval {
+		c.PongWait = 2 * c.PingInterval
+	}
+	if c.WriteWait <= 0 {
+		c.WriteWait = defaultWriteWait
+	}
+	if c.SendBuffer <= 0 {
+		c.SendBuffer = defaultSendBuffer
+	}
+	if c.MaxMessageSize <= 0 {
+		c.MaxMessageSize = defaultMaxMessageSize
+	}
+	return c
+}
+
+// Handler processa as mensagens do cliente (exceto ping) e retorna o ack
+type Handler interface {
+	Handle(session *Session, message ClientMessage) ServerMessage
+}
+
+// Session é uma conexão WebSocket de um usuário. Os eventos do broker passam
+// pelo filtro de inscrições e são enviados junto com os acks por uma única
+// goroutine de escrita
+type Session struct {
+	Principal *auth.Principal
+
+	conn   *websocket.Conn
+	config Config
+	send   chan ServerMessage
+	done   chan struct{}
+	once   sync.Once
+
+	mu       sync.Mutex
+	projects map[uint]bool
+	todos    map[uint]bool
+}
+
+// Subscriptions lista as inscrições atuais da sessão
+type Subscriptions struct {
+	ProjectIDs []uint `json:"project_ids"`
+	TodoIDs    []uint `json:"todo_ids"`
+}
+
+func newSession(conn *websocket.Conn, principal *auth.Principal, config Config) *Session {
+	return &Session{
+		Principal: principal,
+		conn:      conn,
+		config:    config,
+		send:      make(chan ServerMessage, config.SendBuffer),
+		done:      make(chan struct{}),
+		projects:  make(map[uint]bool),
+		todos:     make(map[uint]bool),
+	}
+}
+
+// Watch inscreve a sessão no projeto e nas tarefas informados
+func (s *Session) Watch(projectID *uint, todoIDs []uint) Subscriptions {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if projectID != nil {
+		s.projects[*projectID] = true
+	}
+	for _, id := range todoIDs {
+		s.todos[id] = true
+	}
+	return s.subscriptions()
+}
+
+// Unwatch remove as inscrições informadas
+func (s *Session) Unwatch(projectID *uint, todoIDs []uint) Subscriptions {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if projectID != nil {
+		delete(s.projects, *projectID)
+	}
+	for _, id := range todoIDs {
+		delete(s.todos, id)
+	}
+	return s.subscriptions()
+}
+
+func (s *Session) subscriptions() Subscriptions {
+	return Subscriptions{ProjectIDs: sortedKeys(s.projects), TodoIDs: sortedKeys(s.todos)}
+}
+
+func (s *Session) matches(e event.Event) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.todos[e.TodoID] {
+		return true
+	}
+	return e.Todo != nil && e.Todo.ProjectID != nil && s.projects[*e.Todo.ProjectID]
+}
+
+// enqueue agenda a mensagem para envio. Com o buffer cheio o cliente não está
+// acompanhando e é desconectado, para não reter memória nem atrasar o broker
+func (s *Session) enqueue(message ServerMessage) {
+	select {
+	case <-s.done:
+	case s.send <- message:
+	default:
+		s.close(websocket.CloseTryAgainLater, "slow consumer")
+	}
+}
+
+// close envia o frame de encerramento e fecha a conexão; só a primeira chamada
+// tem efeito. CloseAbnormalClosure indica uma conexão já quebrada e fecha sem
+// enviar frame (o código é reservado pelo protocolo)
+func (s *Session) close(code int, reason string) {
+	s.once.Do(func() {
+		if code != websocket.CloseAbnormalClosure {
+			deadline := time.Now().Add(s.config.WriteWait)
+			s.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), deadline)
+		}
+		s.conn.Close()
+		close(s.done)
+	})
+}
+
+// run atende a conexão até ela ser encerrada
+func (s *Session) run(broker *event.Broker, handler Handler) {
+	sub, _, _ := broker.Subscribe(s.Principal.UserID, 0)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		s.writeLoop()
+	}()
+	go func() {
+		defer wg.Done()
+		s.eventLoop(sub)
+	}()
+
+	s.readLoop(handler)
+
+	s.close(websocket.CloseNormalClosure, "")
+	broker.Unsubscribe(sub)
+	wg.Wait()
+}
+
+func (s *Session) readLoop(handler Handler) {
+	s.conn.SetReadLimit(s.config.MaxMessageSize)
+	s.conn.SetReadDeadline(time.Now().Add(s.config.PongWait))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(s.config.PongWait))
+	})
+
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var message ClientMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			s.enqueue(Nack("", http.StatusBadRequest, "message must be a JSON object"))
+			continue
+		}
+
+		if message.Type == TypePing {
+			s.enqueue(ServerMessage{Type: TypePong, ID: message.ID})
+			continue
+		}
+		s.enqueue(handler.Handle(s, message))
+	}
+}
+
+func (s *Session) eventLoop(sub *event.Subscription) {
+	for message := range sub.C {
+		if s.matches(message.Event) {
+			s.enqueue(eventMessage(message))
+		}
+	}
+
+	// O broker descartou a inscrição (consumidor lento) ou foi encerrado
+	if sub.Dropped() {
+		s.close(websocket.CloseTryAgainLater, "slow consumer")
+	} else {
+		s.close(websocket.CloseGoingAway, "server shutting down")
+	}
+}
+
+func (s *Session) writeLoop() {
+	ticker := time.NewTicker(s.config.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case message := <-s.send:
+			s.conn.SetWriteDeadline(time.Now().Add(s.config.WriteWait))
+			if err := s.conn.WriteJSON(message); err != nil {
+				s.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-ticker.C:
+			deadline := time.Now().Add(s.config.WriteWait)
+			if err := s.conn.WriteControl(websocket.PingMessage, nil, deadline); err != nil {
+				s.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		}
+	}
+}
+
+func sortedKeys(set map[uint]bool) []uint {
+	keys := make([]uint, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
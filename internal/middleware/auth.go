@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/auth"
+)
+
+// APITokenAuthenticator valida tokens de API pessoais
+type APITokenAuthenticator interface {
+	Authenticate(rawToken string) (*auth.Principal, error)
+}
+
+// Auth exige um access token (JWT) ou um token de API válido no header
+// Authorization e injeta o principal no contexto. Tokens de API são
+// reconhecidos pelo prefixo auth.APITokenPrefix.
+func Auth(tokens *auth.TokenManager, apiTokens APITokenAuthenticator) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, ok := bearerToken(ctx)
+		if !ok {
+			abortUnauthorized(ctx, errMissingToken)
+			return
+		}
+
+		if apiTokens != nil && strings.HasPrefix(token, auth.APITokenPrefix) {
+			principal, err := apiTokens.Authenticate(token)
+			if err != nil {
+				abortUnauthorized(ctx, err)
+				return
+			}
+
+			auth.SetPrincipal(ctx, principal)
+			ctx.Next()
+			return
+		}
+
+		userID, err := tokens.Parse(token, auth.AccessToken)
+		if err != nil {
+			abortUnauthorized(ctx, err)
+			return
+		}
+
+		auth.SetPrincipal(ctx, &auth.Principal{UserID: userID})
+		ctx.Next()
+	}
+}
+
+// RequireScope bloqueia a rota para principais sem o escopo informado
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		principal := auth.CurrentPrincipal(ctx)
+		if principal == nil || !principal.HasScope(scope) {
+			ctx.Error(apperror.Forbidden("Missing required scope: " + scope))
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// QueryToken aceita o token no parâmetro de query informado quando não há
+// header Authorization. Navegadores não permitem headers no handshake do
+// WebSocket, por isso só deve ser usado nessa rota (tokens em URLs vão parar em logs)
+func QueryToken(param string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if token := ctx.Query(param); token != "" && ctx.GetHeader("Authorization") == "" {
+			ctx.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+		ctx.Next()
+	}
+}
+
+func bearerToken(ctx *gin.Context) (string, bool) {
+	header := ctx.GetHeader("Authorization")
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+var errMissingToken = apperror.Unauthorized("Missing bearer token")
+
+// abortUnauthorized encerra a requisição com o erro da autenticação; falhas
+// que não são de credencial (ex.: banco indisponível) viram erros internos
+func abortUnauthorized(ctx *gin.Context, err error) {
+	if apperror.KindOf(err) == apperror.KindUnauthorized {
+		ctx.Header("WWW-Authenticate", `Bearer realm="todo-api"`)
+	}
+	ctx.Error(err)
+	ctx.Abort()
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSConfig lista o que os navegadores de outras origens podem usar
+type CORSConfig struct {
+	AllowedOrigins   []string // "*" aceita qualquer origem
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration // cache do preflight no navegador
+}
+
+// CORS responde aos preflights (OPTIONS) e adiciona os headers de CORS às
+// requisições de origens permitidas. Origens não permitidas seguem sem os
+// headers e o navegador bloqueia a resposta. Sem origens o middleware não
+// faz nada
+func CORS(config CORSConfig) gin.HandlerFunc {
+	anyOrigin := slices.Contains(config.AllowedOrigins, "*")
+	allowed := make(map[string]bool, len(config.AllowedOrigins))
+	for _, origin := range config.AllowedOrigins {
+		allowed[strings.ToLower(strings.TrimSuffix(origin, "/"))] = true
+	}
+	methods := strings.Join(config.AllowedMethods, ", ")
+	headers := strings.Join(config.AllowedHeaders, ", ")
+	exposed := strings.Join(config.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(config.MaxAge.Seconds()))
+
+	return func(ctx *gin.Context) {
+		origin := ctx.GetHeader("Origin")
+		if origin == "" || len(allowed) == 0 {
+			ctx.Next()
+			return
+		}
+		ctx.Writer.Header().Add("Vary", "Origin")
+		if !anyOrigin && !allowed[strings.ToLower(origin)] {
+			ctx.Next()
+			return
+		}
+
+		// Com credenciais o navegador não aceita "*" e exige a própria origem
+		if anyOrigin && !config.AllowCredentials {
+			ctx.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			ctx.Header("Access-Control-Allow-Origin", origin)
+		}
+		if config.AllowCredentials {
+			ctx.Header("Access-Control-Allow-Credentials", "true")
+		}
+
+		if ctx.Request.Method == http.MethodOptions && ctx.GetHeader("Access-Control-Request-Method") != "" {
+			ctx.Header("Access-Control-Allow-Methods", methods)
+			ctx.Header("Access-Control-Allow-Headers", headers)
+			if config.MaxAge > 0 {
+				ctx.Header("Access-Control-Max-Age", maxAge)
+			}
+			ctx.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		if exposed != "" {
+			ctx.Header("Access-Control-Expose-Headers", exposed)
+		}
+		ctx.Next()
+	}
+}
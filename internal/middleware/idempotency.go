@@ -0,0 +1,226 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/auth"
+	"github.com/vinibsi/todo-api/internal/entity"
+)
+
+const (
+	// IdempotencyKeyHeader identifica as tentativas de uma mesma requisição
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marca as respostas repetidas do armazenamento
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// replayedHeaders são os headers da resposta original guardados para a repetição
+var replayedHeaders = []string{"Content-Type", "ETag", "Location"}
+
+// IdempotencyStore guarda as chaves e as respostas (ver
+// repository.IdempotencyRepository)
+type IdempotencyStore interface {
+	Acquire(record *entity.IdempotencyKey, staleBefore time.Time) (*entity.IdempotencyKey, bool, error)
+	Complete(id uint, statusCode int, headers, body string) error
+	Release(id uint) error
+}
+
+// IdempotencyConfig ajusta o middleware. Valores zerados usam os padrões
+type IdempotencyConfig struct {
+	TTL          time.Duration // tempo em que a resposta pode ser repetida
+	LockTimeout  time.Duration // após esse tempo a trava de uma requisição sem resposta pode ser assumida
+	MaxBodyBytes int64         // maior corpo lido para calcular o hash; acima dele a resposta é 413
+	Now          func() time.Time
+}
+
+const (
+	defaultIdempotencyTTL          = 24 * time.Hour
+	defaultIdempotencyLockTimeout  = time.Minute
+	defaultIdempotencyMaxBodyBytes = 10 << 20
+)
+
+// Idempotency repete a resposta das requisições POST, PUT, PATCH e DELETE
+// reenviadas com o mesmo header Idempotency-Key. As chaves são por usuário,
+// por isso o middleware deve vir depois de Auth. A mesma chave com outro
+// método, caminho ou corpo recebe 422 e, enquanto a primeira requisição não
+// termina, as repetições recebem 409. Respostas 5xx não são guardadas, para
+// que o cliente possa tentar de novo, nem 401 e 403, que dependem da
+// credencial e não da requisição (o escopo é verificado depois, na rota)
+func Idempotency(store IdempotencyStore, config IdempotencyConfig) gin.HandlerFunc {
+	if config.TTL <= 0 {
+		config.TTL = defaultIdempotencyTTL
+	}
+	if config.LockTimeout <= 0 {
+		config.LockTimeout = defaultIdempotencyLockTimeout
+	}
+	if config.MaxBodyBytes <= 0 {
+		config.MaxBodyBytes = defaultIdempotencyMaxBodyBytes
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(IdempotencyKeyHeader)
+		if key == "" || !isMutation(ctx.Request.Method) {
+			ctx.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			abortIdempotency(ctx, apperror.Validation("Idempotency-Key must have at most 255 characters"))
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, config.MaxBodyBytes))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				abortIdempotency(ctx, apperror.New(apperror.KindPayloadTooLarge,
+					fmt.Sprintf("Request body must have at most %d bytes", tooLarge.Limit)))
+				return
+			}
+			abortIdempotency(ctx, apperror.Wrap(apperror.KindValidation, err))
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		now := config.Now()
+		hash := requestHash(ctx.Request.Method, ctx.Request.URL.RequestURI(), body)
+		record, acquired, err := store.Acquire(&entity.IdempotencyKey{
+			UserID:      auth.UserID(ctx),
+			Key:         key,
+			RequestHash: hash,
+			LockedAt:    now,
+			ExpiresAt:   now.Add(config.TTL),
+		}, now.Add(-config.LockTimeout))
+		if err != nil {
+			abortIdempotency(ctx, err)
+			return
+		}
+
+		if !acquired {
+			switch {
+			case record.RequestHash != hash:
+				abortIdempotency(ctx, errIdempotencyKeyReused)
+			case !record.Completed:
+				ctx.Header("Retry-After", "1")
+				abortIdempotency(ctx, errIdempotencyInProgress)
+			default:
+				replay(ctx, record)
+			}
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: ctx.Writer}
+		ctx.Writer = recorder
+
+		// Sem resposta guardada (erro, 5xx ou panic) a chave é liberada
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			if err := store.Release(record.ID); err != nil {
+				log.Printf("Idempotency: release key %d: %v", record.ID, err)
+			}
+		}()
+
+		ctx.Next()
+		renderProblem(ctx)
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError || status == http.StatusUnauthorized || status == http.StatusForbidden {
+			return
+		}
+
+		headers := make(map[string]string)
+		for _, name := range replayedHeaders {
+			if value := recorder.Header().Get(name); value != "" {
+				headers[name] = value
+			}
+		}
+		encoded, err := json.Marshal(headers)
+		if err != nil {
+			return
+		}
+		if err := store.Complete(record.ID, status, string(encoded), recorder.body.String()); err != nil {
+			log.Printf("Idempotency: store response for key %d: %v", record.ID, err)
+			return
+		}
+		completed = true
+	}
+}
+
+func isMutation(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// requestHash identifica a requisição pelo método, caminho com query e corpo
+func requestHash(method, uri string, body []byte) string {
+	digest := sha256.New()
+	digest.Write([]byte(method + " " + uri + "\n"))
+	digest.Write(body)
+	return hex.EncodeToString(digest.Sum(nil))
+}
+
+func replay(ctx *gin.Context, record *entity.IdempotencyKey) {
+	headers := map[string]string{}
+	if record.Headers != "" {
+		if err := json.Unmarshal([]byte(record.Headers), &headers); err != nil {
+			abortIdempotency(ctx, err)
+			return
+		}
+	}
+	for name, value := range headers {
+		ctx.Header(name, value)
+	}
+	ctx.Header(IdempotentReplayedHeader, "true")
+
+	ctx.Status(record.StatusCode)
+	ctx.Writer.WriteString(record.Body)
+	ctx.Abort()
+}
+
+var (
+	errIdempotencyKeyReused  = apperror.Unprocessable("Idempotency-Key was already used with a different request")
+	errIdempotencyInProgress = apperror.Conflict("A request with this Idempotency-Key is still being processed")
+)
+
+func abortIdempotency(ctx *gin.Context, err error) {
+	ctx.Error(err)
+	ctx.Abort()
+}
+
+// responseRecorder copia o corpo da resposta enquanto ela é enviada
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/dto"
+)
+
+// problemTypePrefix antecede o slug do tipo no campo type (ex.: /problems/not-found)
+const problemTypePrefix = "/problems/"
+
+const developmentKey = "problems.development"
+
+const internalErrorDetail = "An unexpected error occurred"
+
+// Problems responde com application/problem+json (RFC 7807) quando o handler
+// registra um erro com ctx.Error e não escreve uma resposta. O status vem do
+// tipo do erro de domínio (apperror); erros sem tipo viram 500 e, fora de
+// development, o detalhe é trocado por uma mensagem genérica e só vai para
+// o log. Deve ser o primeiro middleware, para tratar também os erros dos
+// demais (Auth, RequireScope)
+func Problems(development bool) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Set(developmentKey, development)
+		ctx.Next()
+		renderProblem(ctx)
+	}
+}
+
+// renderProblem escreve o problema do último erro registrado, se a resposta
+// ainda não foi escrita. Middlewares que precisam da resposta final (como
+// Idempotency) o chamam antes de Problems
+func renderProblem(ctx *gin.Context) {
+	if len(ctx.Errors) == 0 || ctx.Writer.Written() {
+		return
+	}
+
+	problem := NewProblem(ctx, ctx.Errors.Last().Err)
+	problem.Instance = ctx.Request.URL.Path
+	body, err := json.Marshal(problem)
+	if err != nil {
+		log.Printf("Problems: %v", err)
+		return
+	}
+	// Substitui o Content-Type definido pelo handler (ex.: o do export)
+	ctx.Header("Content-Type", dto.ProblemContentType)
+	ctx.Data(problem.Status, dto.ProblemContentType, body)
+}
+
+// NewProblem monta o documento de problema de err, com as mesmas regras do
+// middleware Problems. Serve para erros embutidos em respostas de sucesso,
+// como os itens de um lote
+func NewProblem(ctx *gin.Context, err error) *dto.Problem {
+	kind := apperror.KindOf(err)
+	problem := &dto.Problem{
+		Type:   problemTypePrefix + kind.Slug(),
+		Title:  kind.Title(),
+		Status: kind.Status(),
+		Detail: err.Error(),
+	}
+
+	if domain, ok := apperror.As(err); ok {
+		for _, field := range domain.Fields {
+			problem.Errors = append(problem.Errors, dto.ProblemFieldError{Field: field.Field, Message: field.Message})
+		}
+	}
+
+	if kind == apperror.KindInternal {
+		log.Printf("%s %s: %v", ctx.Request.Method, ctx.Request.URL.Path, err)
+		if !ctx.GetBool(developmentKey) {
+			problem.Detail = internalErrorDetail
+		}
+	}
+	return problem
+}
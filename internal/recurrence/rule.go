@@ -0,0 +1,167 @@
+// Package recurrence implementa o subconjunto de regras RRULE (RFC 5545)
+// usado pelas tarefas recorrentes.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequências suportadas
+const (
+	Daily   = "DAILY"
+	Weekly  = "WEEKLY"
+	Monthly = "MONTHLY"
+)
+
+// ErrInvalidRule indica uma regra fora do subconjunto suportado
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+// Rule é uma regra no formato "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10".
+// Partes aceitas: FREQ (DAILY, WEEKLY, MONTHLY), INTERVAL, BYDAY (somente
+// WEEKLY), BYMONTHDAY (somente MONTHLY, de 1 a 31), UNTIL e COUNT
+type Rule struct {
+	Freq       string
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay int
+	Until      *time.Time
+	Count      int
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// Parse lê a regra, aceitando também o prefixo "RRULE:"
+func Parse(raw string) (*Rule, error) {
+	raw = strings.TrimPrefix(strings.TrimSpace(raw), "RRULE:")
+	if raw == "" {
+		return nil, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+
+	rule := &Rule{Interval: 1}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(raw, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		key = strings.ToUpper(strings.TrimSpace(key))
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("%w: duplicate %s", ErrInvalidRule, key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			if value != Daily && value != Weekly && value != Monthly {
+				return nil, fmt.Errorf("%w: unsupported FREQ %q", ErrInvalidRule, value)
+			}
+			rule.Freq = value
+		case "INTERVAL":
+			rule.Interval, err = positive(value, 1000)
+		case "COUNT":
+			rule.Count, err = positive(value, 10000)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = positive(value, 31)
+		case "BYDAY":
+			rule.ByDay, err = parseWeekdays(value)
+		case "UNTIL":
+			var until time.Time
+			until, err = parseUntil(value)
+			rule.Until = &until
+		default:
+			return nil, fmt.Errorf("%w: unsupported part %s", ErrInvalidRule, key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidRule, key, err)
+		}
+	}
+
+	switch {
+	case rule.Freq == "":
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	case len(rule.ByDay) > 0 && rule.Freq != Weekly:
+		return nil, fmt.Errorf("%w: BYDAY requires FREQ=WEEKLY", ErrInvalidRule)
+	case rule.ByMonthDay > 0 && rule.Freq != Monthly:
+		return nil, fmt.Errorf("%w: BYMONTHDAY requires FREQ=MONTHLY", ErrInvalidRule)
+	case rule.Until != nil && rule.Count > 0:
+		return nil, fmt.Errorf("%w: UNTIL and COUNT are mutually exclusive", ErrInvalidRule)
+	}
+
+	return rule, nil
+}
+
+// String devolve a regra na forma canônica
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = strings.ToUpper(day.String()[:2])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.ByMonthDay > 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.ByMonthDay))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	return strings.Join(parts, ";")
+}
+
+func positive(value string, limit int) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || n > limit {
+		return 0, fmt.Errorf("must be between 1 and %d", limit)
+	}
+	return n, nil
+}
+
+// parseWeekdays lê a lista de dias, devolvida sem repetições e em ordem
+// a partir de segunda-feira (o início de semana padrão da RFC 5545)
+func parseWeekdays(value string) ([]time.Weekday, error) {
+	seen := make(map[time.Weekday]bool)
+	var days []time.Weekday
+	for _, code := range strings.Split(value, ",") {
+		day, ok := weekdays[strings.TrimSpace(code)]
+		if !ok {
+			return nil, fmt.Errorf("unknown weekday %q", code)
+		}
+		if !seen[day] {
+			seen[day] = true
+			days = append(days, day)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return (days[i]+6)%7 < (days[j]+6)%7
+	})
+	return days, nil
+}
+
+// parseUntil aceita data (inclusiva até o fim do dia, em UTC) ou data e hora em UTC
+func parseUntil(value string) (time.Time, error) {
+	if until, err := time.Parse("20060102T150405Z", value); err == nil {
+		return until, nil
+	}
+	day, err := time.Parse("20060102", value)
+	if err != nil {
+		return time.Time{}, errors.New("expected YYYYMMDD or YYYYMMDDTHHMMSSZ")
+	}
+	return day.Add(24*time.Hour - time.Second), nil
+}
@@ -0,0 +1,158 @@
+package recurrence
+
+import (
+	"fmt"
+	"time"
+
+	// Garante o banco de fusos horários mesmo em imagens sem /usr/share/zoneinfo
+	_ "time/tzdata"
+)
+
+// DateLayout é o formato das datas de exceção (EXDATE)
+const DateLayout = "2006-01-02"
+
+// maxCandidates limita a busca por uma ocorrência válida. Regras que nunca
+// produzem datas (ex.: dia 31 a cada 12 meses a partir de fevereiro) terminam
+const maxCandidates = 50000
+
+// Schedule combina a regra com o fuso horário em que ela é avaliada e as
+// datas excluídas. As ocorrências mantêm o horário local do início da
+// série, inclusive nas mudanças de horário de verão.
+// Com FromCompletion a próxima ocorrência é contada a partir da conclusão
+// (ex.: "a cada 3 dias depois de concluída"); só vale para FREQ=DAILY
+type Schedule struct {
+	Rule           *Rule
+	Location       *time.Location
+	Exdates        map[string]bool
+	FromCompletion bool
+}
+
+// NewSchedule valida a regra, o fuso (vazio é UTC) e as datas de exceção
+func NewSchedule(rule, timezone string, exdates []string, fromCompletion bool) (*Schedule, error) {
+	parsed, err := Parse(rule)
+	if err != nil {
+		return nil, err
+	}
+	if fromCompletion && parsed.Freq != Daily {
+		return nil, fmt.Errorf("%w: recurrence from completion requires FREQ=DAILY", ErrInvalidRule)
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown timezone %q", ErrInvalidRule, timezone)
+	}
+
+	excluded := make(map[string]bool, len(exdates))
+	for _, date := range exdates {
+		if _, err := time.Parse(DateLayout, date); err != nil {
+			return nil, fmt.Errorf("%w: exdate %q must use YYYY-MM-DD", ErrInvalidRule, date)
+		}
+		excluded[date] = true
+	}
+
+	return &Schedule{Rule: parsed, Location: location, Exdates: excluded, FromCompletion: fromCompletion}, nil
+}
+
+// Next retorna a primeira ocorrência estritamente posterior a after. start
+// é o início da série (DTSTART): ancora o intervalo e define o horário local,
+// de modo que um horário inexistente num dia de mudança não desloca os seguintes.
+// O segundo valor é false quando a série terminou (UNTIL) ou não há mais datas
+func (s *Schedule) Next(start, after time.Time) (time.Time, bool) {
+	start, after = start.In(s.Location), after.In(s.Location)
+
+	next := s.candidates(start, after)
+	for i := 0; i < maxCandidates; i++ {
+		candidate, ok := next()
+		if !ok {
+			continue
+		}
+		if s.Rule.Until != nil && candidate.After(*s.Rule.Until) {
+			return time.Time{}, false
+		}
+		if !candidate.After(after) || s.Exdates[candidate.Format(DateLayout)] {
+			continue
+		}
+		return candidate, true
+	}
+	return time.Time{}, false
+}
+
+// candidates devolve um gerador das datas da regra em ordem crescente. Cada
+// chamada avança um passo; false indica um passo sem ocorrência
+func (s *Schedule) candidates(from, after time.Time) func() (time.Time, bool) {
+	rule := s.Rule
+	at := func(year int, month time.Month, day int) time.Time {
+		return s.localTime(year, month, day, from)
+	}
+
+	switch {
+	case s.FromCompletion:
+		k := 0
+		return func() (time.Time, bool) {
+			k++
+			return at(after.Year(), after.Month(), after.Day()+k*rule.Interval), true
+		}
+
+	case rule.Freq == Weekly:
+		days := rule.ByDay
+		if len(days) == 0 {
+			days = []time.Weekday{from.Weekday()}
+		}
+		wanted := make(map[time.Weekday]bool, len(days))
+		for _, day := range days {
+			wanted[day] = true
+		}
+
+		// Semanas começam na segunda-feira; só as semanas múltiplas do intervalo valem
+		offset := (int(from.Weekday()) + 6) % 7
+		d := 0
+		return func() (time.Time, bool) {
+			d++
+			candidate := at(from.Year(), from.Month(), from.Day()+d)
+			return candidate, ((offset+d)/7)%rule.Interval == 0 && wanted[candidate.Weekday()]
+		}
+
+	case rule.Freq == Monthly:
+		day := rule.ByMonthDay
+		if day == 0 {
+			day = from.Day()
+		}
+
+		// Começa no próprio mês, pois BYMONTHDAY pode cair depois de from.
+		// Meses sem o dia pedido são ignorados, como define a RFC 5545
+		k := -1
+		return func() (time.Time, bool) {
+			k++
+			first := time.Date(from.Year(), from.Month()+time.Month(k*rule.Interval), 1, 0, 0, 0, 0, s.Location)
+			if day > daysIn(first.Year(), first.Month()) {
+				return time.Time{}, false
+			}
+			return at(first.Year(), first.Month(), day), true
+		}
+
+	default:
+		k := 0
+		return func() (time.Time, bool) {
+			k++
+			return at(from.Year(), from.Month(), from.Day()+k*rule.Interval), true
+		}
+	}
+}
+
+// localTime monta a data com o horário de clock. Um horário que não existe
+// no dia (salto do horário de verão) é lido com o deslocamento anterior ao
+// salto, como define a RFC 5545: 02:30 vira 03:30
+func (s *Schedule) localTime(year int, month time.Month, day int, clock time.Time) time.Time {
+	t := time.Date(year, month, day, clock.Hour(), clock.Minute(), clock.Second(), clock.Nanosecond(), s.Location)
+	if t.Hour() == clock.Hour() && t.Minute() == clock.Minute() {
+		return t
+	}
+
+	_, offset := time.Date(year, month, day-1, clock.Hour(), clock.Minute(), 0, 0, s.Location).Zone()
+	wall := time.Date(year, month, day, clock.Hour(), clock.Minute(), clock.Second(), clock.Nanosecond(), time.UTC)
+	return wall.Add(-time.Duration(offset) * time.Second).In(s.Location)
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
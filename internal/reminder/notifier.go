@@ -0,0 +1,78 @@
+package reminder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Notification é o conteúdo entregue ao Notifier quando um lembrete vence
+type Notification struct {
+	ReminderID uint       `json:"reminder_id"`
+	UserID     uint       `json:"user_id"`
+	TodoID     uint       `json:"todo_id"`
+	Title      string     `json:"title"`
+	DueDate    *time.Time `json:"due_date"`
+	FireAt     time.Time  `json:"fire_at"`
+}
+
+// Notifier entrega os lembretes. Um erro faz o envio ser tentado novamente
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
+
+// LogNotifier apenas registra os lembretes no log
+type LogNotifier struct {
+	Logger *log.Logger
+}
+
+func NewLogNotifier(logger *log.Logger) *LogNotifier {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &LogNotifier{Logger: logger}
+}
+
+func (n *LogNotifier) Notify(_ context.Context, notification Notification) error {
+	n.Logger.Printf("Reminder %d: todo %d (%q) for user %d", notification.ReminderID, notification.TodoID, notification.Title, notification.UserID)
+	return nil
+}
+
+// WebhookNotifier envia o lembrete como JSON via POST para uma URL fixa.
+// Respostas fora da faixa 2xx são tratadas como falha
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{URL: url, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, notification Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+// Package reminder envia os lembretes vencidos das tarefas em segundo plano.
+package reminder
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/repository"
+)
+
+// Config ajusta o agendador. Valores zerados usam os padrões
+type Config struct {
+	Interval    time.Duration // intervalo entre as verificações
+	BatchSize   int           // lembretes reservados por verificação
+	Lease       time.Duration // tempo de reserva de um lembrete em envio
+	MaxAttempts int           // tentativas antes de desistir do lembrete
+	Now         func() time.Time
+}
+
+const (
+	defaultInterval    = 30 * time.Second
+	defaultBatchSize   = 100
+	defaultLease       = 2 * time.Minute
+	defaultMaxAttempts = 5
+)
+
+// Scheduler verifica periodicamente os lembretes vencidos e os entrega ao
+// Notifier. Pode rodar em várias instâncias ao mesmo tempo, pois cada
+// lembrete é reservado no banco antes do envio. A reserva é renovada antes de
+// cada entrega, então Lease precisa cobrir só um envio, e não o lote inteiro
+type Scheduler struct {
+	repo     repository.ReminderRepository
+	notifier Notifier
+	config   Config
+	logger   *log.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+func NewScheduler(repo repository.ReminderRepository, notifier Notifier, config Config) *Scheduler {
+	if config.Interval <= 0 {
+		config.Interval = defaultInterval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultBatchSize
+	}
+	if config.Lease <= 0 {
+		config.Lease = defaultLease
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultMaxAttempts
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	return &Scheduler{
+		repo:     repo,
+		notifier: notifier,
+		config:   config,
+		logger:   log.Default(),
+	}
+}
+
+// Start inicia a goroutine do agendador, que termina com Stop ou quando ctx
+// é cancelado
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := s.RunOnce(ctx); err != nil && ctx.Err() == nil {
+				s.logger.Printf("Reminder scheduler: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop interrompe o agendador e espera o lote em andamento terminar
+func (s *Scheduler) Stop() {
+	s.once.Do(func() {
+		if s.cancel == nil {
+			return
+		}
+		s.cancel()
+		<-s.done
+	})
+}
+
+// RunOnce reserva e envia um lote de lembretes vencidos, retornando quantos
+// foram entregues
+func (s *Scheduler) RunOnce(ctx context.Context) (int, error) {
+	reminders, err := s.repo.ClaimDue(s.config.Now(), s.config.BatchSize, s.config.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, reminder := range reminders {
+		if ctx.Err() != nil {
+			// Os lembretes restantes voltam a ser elegíveis quando a reserva expirar
+			return sent, ctx.Err()
+		}
+		if !s.renew(&reminder) {
+			continue
+		}
+		if s.deliver(ctx, reminder) {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// renew estende a reserva do lembrete antes do envio. Se outra instância já o
+// reservou (a reserva deste lote expirou), o lembrete é deixado para ela
+func (s *Scheduler) renew(reminder *entity.Reminder) bool {
+	if reminder.ClaimedUntil == nil {
+		return false
+	}
+	until := s.config.Now().Add(s.config.Lease)
+	if err := s.repo.RenewClaim(reminder.ID, *reminder.ClaimedUntil, until); err != nil {
+		if !errors.Is(err, repository.ErrClaimLost) {
+			s.logger.Printf("Reminder %d: %v", reminder.ID, err)
+		}
+		return false
+	}
+	reminder.ClaimedUntil = &until
+	return true
+}
+
+func (s *Scheduler) deliver(ctx context.Context, reminder entity.Reminder) bool {
+	notification := Notification{
+		ReminderID: reminder.ID,
+		UserID:     reminder.UserID,
+		TodoID:     reminder.TodoID,
+		FireAt:     reminder.FireAt,
+	}
+	if reminder.Todo != nil {
+		notification.Title = reminder.Todo.Title
+		notification.DueDate = reminder.Todo.DueDate
+	}
+
+	if err := s.notifier.Notify(ctx, notification); err != nil {
+		now := s.config.Now()
+		var retryAt *time.Time
+		if reminder.Attempts+1 < s.config.MaxAttempts {
+			// Espera exponencial: 1, 2, 4, 8... minutos
+			next := now.Add(time.Minute << reminder.Attempts)
+			retryAt = &next
+		}
+		if markErr := s.repo.MarkFailed(reminder.ID, *reminder.ClaimedUntil, err.Error(), now, retryAt); markErr != nil {
+			s.logger.Printf("Reminder %d: %v", reminder.ID, markErr)
+		}
+		return false
+	}
+
+	if err := s.repo.MarkSent(reminder.ID, *reminder.ClaimedUntil, s.config.Now()); err != nil {
+		s.logger.Printf("Reminder %d: %v", reminder.ID, err)
+	}
+	return true
+}
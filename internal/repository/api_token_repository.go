@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/vinibsi/todo-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+type APITokenRepository interface {
+	Create(token *entity.APIToken) error
+	GetByHash(hash string) (*entity.APIToken, error)
+	ListByUser(userID uint) ([]entity.APIToken, error)
+	Delete(userID, id uint) (bool, error)
+	TouchLastUsed(id uint, usedAt time.Time, minInterval time.Duration) error
+}
+
+type apiTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewAPITokenRepository(db *gorm.DB) APITokenRepository {
+	return &apiTokenRepository{db: db}
+}
+
+func (repo *apiTokenRepository) Create(token *entity.APIToken) error {
+	return repo.db.Create(token).Error
+}
+
+func (repo *apiTokenRepository) GetByHash(hash string) (*entity.APIToken, error) {
+	var token entity.APIToken
+	if err := repo.db.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (repo *apiTokenRepository) ListByUser(userID uint) ([]entity.APIToken, error) {
+	var tokens []entity.APIToken
+	err := repo.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&tokens).Error
+	return tokens, err
+}
+
+// Delete revoga o token e informa se algum registro foi afetado
+func (repo *apiTokenRepository) Delete(userID, id uint) (bool, error) {
+	result := repo.db.Where("user_id = ?", userID).Delete(&entity.APIToken{}, id)
+	return result.RowsAffected > 0, result.Error
+}
+
+// TouchLastUsed atualiza last_used_at no máximo uma vez por minInterval,
+// evitando uma escrita no banco a cada requisição
+func (repo *apiTokenRepository) TouchLastUsed(id uint, usedAt time.Time, minInterval time.Duration) error {
+	return repo.db.Model(&entity.APIToken{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, usedAt.Add(-minInterval)).
+		UpdateColumn("last_used_at", usedAt).Error
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/vinibsi/todo-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+type CalendarFeedRepository interface {
+	GetByUser(userID uint) (*entity.CalendarFeed, error)
+	GetByHash(hash string) (*entity.CalendarFeed, error)
+	Replace(feed *entity.CalendarFeed) error
+	Delete(userID uint) (bool, error)
+	TouchLastUsed(id uint, usedAt time.Time, minInterval time.Duration) error
+}
+
+type calendarFeedRepository struct {
+	db *gorm.DB
+}
+
+func NewCalendarFeedRepository(db *gorm.DB) CalendarFeedRepository {
+	return &calendarFeedRepository{db: db}
+}
+
+func (repo *calendarFeedRepository) GetByUser(userID uint) (*entity.CalendarFeed, error) {
+	var feed entity.CalendarFeed
+	if err := repo.db.Where("user_id = ?", userID).First(&feed).Error; err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+func (repo *calendarFeedRepository) GetByHash(hash string) (*entity.CalendarFeed, error) {
+	var feed entity.CalendarFeed
+	if err := repo.db.Where("token_hash = ?", hash).First(&feed).Error; err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// Replace remove o link atual do usuário e grava o novo na mesma transação,
+// invalidando o anterior
+func (repo *calendarFeedRepository) Replace(feed *entity.CalendarFeed) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", feed.UserID).Delete(&entity.CalendarFeed{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(feed).Error
+	})
+}
+
+// Delete desativa o feed e informa se havia um link ativo
+func (repo *calendarFeedRepository) Delete(userID uint) (bool, error) {
+	result := repo.db.Where("user_id = ?", userID).Delete(&entity.CalendarFeed{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// TouchLastUsed atualiza last_used_at no máximo uma vez por minInterval
+func (repo *calendarFeedRepository) TouchLastUsed(id uint, usedAt time.Time, minInterval time.Duration) error {
+	return repo.db.Model(&entity.CalendarFeed{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, usedAt.Add(-minInterval)).
+		UpdateColumn("last_used_at", usedAt).Error
+}
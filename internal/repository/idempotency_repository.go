@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/vinibsi/todo-api/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyRepository interface {
+	Acquire(record *entity.IdempotencyKey, staleBefore time.Time) (*entity.IdempotencyKey, bool, error)
+	Complete(id uint, statusCode int, headers, body string) error
+	Release(id uint) error
+	DeleteExpired(now time.Time, limit int) (int, error)
+}
+
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &idempotencyRepository{db: db}
+}
+
+// Acquire tenta reservar a chave para record. O índice único (usuário, chave)
+// garante que só uma requisição reserva a mesma chave; as demais recebem o
+// registro existente e acquired falso. Registros vencidos são descartados e
+// uma trava mais antiga que staleBefore (requisição que nunca terminou) é
+// assumida por quem enviar a mesma requisição
+func (repo *idempotencyRepository) Acquire(record *entity.IdempotencyKey, staleBefore time.Time) (*entity.IdempotencyKey, bool, error) {
+	err := repo.db.Where("user_id = ? AND idempotency_key = ? AND expires_at <= ?", record.UserID, record.Key, record.LockedAt).
+		Delete(&entity.IdempotencyKey{}).Error
+	if err != nil {
+		return nil, false, err
+	}
+
+	result := repo.db.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return record, true, nil
+	}
+
+	var existing entity.IdempotencyKey
+	err = repo.db.Where("user_id = ? AND idempotency_key = ?", record.UserID, record.Key).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// O registro venceu e foi apagado entre as duas consultas
+		return repo.Acquire(record, staleBefore)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	if existing.Completed || existing.RequestHash != record.RequestHash || !existing.LockedAt.Before(staleBefore) {
+		return &existing, false, nil
+	}
+
+	// A condição sobre locked_at impede que duas tentativas assumam a mesma trava
+	result = repo.db.Model(&entity.IdempotencyKey{}).
+		Where("id = ? AND completed = ? AND locked_at = ?", existing.ID, false, existing.LockedAt).
+		UpdateColumn("locked_at", record.LockedAt)
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	acquired := result.RowsAffected > 0
+	if acquired {
+		existing.LockedAt = record.LockedAt
+	}
+	return &existing, acquired, nil
+}
+
+// Complete grava a resposta e libera a trava
+func (repo *idempotencyRepository) Complete(id uint, statusCode int, headers, body string) error {
+	return repo.db.Model(&entity.IdempotencyKey{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"completed":   true,
+		"status_code": statusCode,
+		"headers":     headers,
+		"body":        body,
+	}).Error
+}
+
+// Release apaga uma reserva sem resposta, permitindo que o cliente tente de novo
+func (repo *idempotencyRepository) Release(id uint) error {
+	return repo.db.Where("id = ? AND completed = ?", id, false).Delete(&entity.IdempotencyKey{}).Error
+}
+
+// DeleteExpired apaga até limit registros vencidos, retornando quantos foram apagados
+func (repo *idempotencyRepository) DeleteExpired(now time.Time, limit int) (int, error) {
+	var ids []uint
+	err := repo.db.Model(&entity.IdempotencyKey{}).Where("expires_at <= ?", now).
+		Order("id").Limit(limit).Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	result := repo.db.Where("id IN ?", ids).Delete(&entity.IdempotencyKey{})
+	return int(result.RowsAffected), result.Error
+}
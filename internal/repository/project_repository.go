@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"github.com/vinibsi/todo-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+type ProjectRepository interface {
+	Create(project *entity.Project) error
+	GetByID(userID, id uint) (*entity.Project, error)
+	List(userID uint, includeArchived bool) ([]entity.Project, error)
+	Update(project *entity.Project) error
+	Delete(userID, id uint, cascade bool) error
+}
+
+type projectRepository struct {
+	db *gorm.DB
+}
+
+func NewProjectRepository(db *gorm.DB) ProjectRepository {
+	return &projectRepository{db: db}
+}
+
+func (repo *projectRepository) Create(project *entity.Project) error {
+	return repo.db.Create(project).Error
+}
+
+func (repo *projectRepository) GetByID(userID, id uint) (*entity.Project, error) {
+	var project entity.Project
+	if err := repo.db.Where("user_id = ?", userID).First(&project, id).Error; err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+func (repo *projectRepository) List(userID uint, includeArchived bool) ([]entity.Project, error) {
+	var projects []entity.Project
+
+	db := repo.db.Where("user_id = ?", userID)
+	if !includeArchived {
+		db = db.Where("archived = ?", false)
+	}
+
+	err := db.Order("name ASC").Find(&projects).Error
+	return projects, err
+}
+
+func (repo *projectRepository) Update(project *entity.Project) error {
+	return repo.db.Model(project).Where("user_id = ?", project.UserID).Select("*").Updates(project).Error
+}
+
+// Delete remove o projeto e, na mesma transação, remove as tarefas dele
+// (cascade) ou as move para a caixa de entrada (sem projeto)
+func (repo *projectRepository) Delete(userID, id uint, cascade bool) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if cascade {
+			err = tx.Where("user_id = ? AND project_id = ?", userID, id).Delete(&entity.Todo{}).Error
+		} else {
+			// Inclui as tarefas na lixeira para que não apontem para um projeto removido
+			err = tx.Unscoped().Model(&entity.Todo{}).
+				Where("user_id = ? AND project_id = ?", userID, id).
+				Updates(map[string]interface{}{"project_id": nil, "version": gorm.Expr("version + 1")}).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		result := tx.Where("user_id = ?", userID).Delete(&entity.Project{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
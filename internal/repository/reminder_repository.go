@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/vinibsi/todo-api/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReminderRepository interface {
+	Create(reminder *entity.Reminder) error
+	ListByTodo(userID, todoID uint) ([]entity.Reminder, error)
+	Delete(userID, todoID, id uint) (bool, error)
+	ClaimDue(now time.Time, limit int, lease time.Duration) ([]entity.Reminder, error)
+	RenewClaim(id uint, claimedUntil, until time.Time) error
+	MarkSent(id uint, claimedUntil, sentAt time.Time) error
+	MarkFailed(id uint, claimedUntil time.Time, reason string, failedAt time.Time, retryAt *time.Time) error
+}
+
+// ErrClaimLost indica que a reserva do lembrete não é mais desta instância:
+// expirou e foi tomada por outra, ou o lembrete foi reagendado ou removido
+var ErrClaimLost = errors.New("reminder claim lost")
+
+type reminderRepository struct {
+	db *gorm.DB
+}
+
+func NewReminderRepository(db *gorm.DB) ReminderRepository {
+	return &reminderRepository{db: db}
+}
+
+func (repo *reminderRepository) Create(reminder *entity.Reminder) error {
+	return repo.db.Create(reminder).Error
+}
+
+func (repo *reminderRepository) ListByTodo(userID, todoID uint) ([]entity.Reminder, error) {
+	var reminders []entity.Reminder
+	err := repo.db.Where("user_id = ? AND todo_id = ?", userID, todoID).
+		Order("fire_at ASC").Order("id ASC").
+		Find(&reminders).Error
+	return reminders, err
+}
+
+func (repo *reminderRepository) Delete(userID, todoID, id uint) (bool, error) {
+	result := repo.db.Where("user_id = ? AND todo_id = ?", userID, todoID).Delete(&entity.Reminder{}, id)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ClaimDue reserva até limit lembretes vencidos de tarefas abertas, marcando
+// claimed_until para que outras instâncias os ignorem durante lease. No
+// PostgreSQL as linhas são travadas com FOR UPDATE SKIP LOCKED, então duas
+// instâncias nunca reservam o mesmo lembrete; no SQLite a transação já é
+// serializada. Um lembrete cuja reserva expirou (instância caiu) volta a ser
+// elegível. ClaimedUntil dos lembretes retornados identifica a reserva nas
+// chamadas seguintes (RenewClaim, MarkSent e MarkFailed)
+func (repo *reminderRepository) ClaimDue(now time.Time, limit int, lease time.Duration) ([]entity.Reminder, error) {
+	var reminders []entity.Reminder
+
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&entity.Reminder{}).
+			Joins("JOIN todos ON todos.id = reminders.todo_id").
+			Where("reminders.sent_at IS NULL AND reminders.failed_at IS NULL").
+			Where("reminders.fire_at <= ?", now).
+			Where("(reminders.claimed_until IS NULL OR reminders.claimed_until < ?)", now).
+			Where("todos.deleted_at IS NULL AND todos.completed = ?", false).
+			Order("reminders.fire_at ASC").
+			Limit(limit)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{
+				Strength: "UPDATE",
+				Table:    clause.Table{Name: "reminders"},
+				Options:  "SKIP LOCKED",
+			})
+		}
+
+		var ids []uint
+		if err := query.Pluck("reminders.id", &ids).Error; err != nil || len(ids) == 0 {
+			return err
+		}
+
+		claimedUntil := claimTime(now.Add(lease))
+		if err := tx.Model(&entity.Reminder{}).Where("id IN ?", ids).
+			Update("claimed_until", claimedUntil).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Preload("Todo").Order("fire_at ASC").Find(&reminders, ids).Error; err != nil {
+			return err
+		}
+		// Usa o valor gravado, e não o lido, para comparar a reserva depois
+		for i := range reminders {
+			reminders[i].ClaimedUntil = &claimedUntil
+		}
+		return nil
+	})
+
+	return reminders, err
+}
+
+// RenewClaim estende até until a reserva identificada por claimedUntil
+func (repo *reminderRepository) RenewClaim(id uint, claimedUntil, until time.Time) error {
+	return repo.updateClaimed(id, claimedUntil, map[string]interface{}{
+		"claimed_until": claimTime(until),
+	})
+}
+
+func (repo *reminderRepository) MarkSent(id uint, claimedUntil, sentAt time.Time) error {
+	return repo.updateClaimed(id, claimedUntil, map[string]interface{}{
+		"sent_at":       sentAt,
+		"claimed_until": nil,
+		"attempts":      gorm.Expr("attempts + 1"),
+		"last_error":    "",
+	})
+}
+
+// MarkFailed registra a falha de envio. Com retryAt o lembrete volta a ser
+// elegível naquele instante; sem ele o envio é abandonado em failedAt
+func (repo *reminderRepository) MarkFailed(id uint, claimedUntil time.Time, reason string, failedAt time.Time, retryAt *time.Time) error {
+	if len(reason) > 500 {
+		reason = reason[:500]
+	}
+
+	updates := map[string]interface{}{
+		"attempts":      gorm.Expr("attempts + 1"),
+		"last_error":    reason,
+		"claimed_until": retryAt,
+	}
+	if retryAt == nil {
+		updates["failed_at"] = failedAt
+	}
+
+	return repo.updateClaimed(id, claimedUntil, updates)
+}
+
+// updateClaimed só altera o lembrete se a reserva ainda for claimedUntil
+func (repo *reminderRepository) updateClaimed(id uint, claimedUntil time.Time, updates map[string]interface{}) error {
+	result := repo.db.Model(&entity.Reminder{}).
+		Where("id = ? AND claimed_until = ?", id, claimTime(claimedUntil)).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrClaimLost
+	}
+	return nil
+}
+
+// claimTime descarta a precisão que o banco não guarda (o PostgreSQL grava
+// microssegundos), para que a reserva lida seja igual à comparada
+func claimTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Microsecond)
+}
+
+// rescheduleReminders recalcula os lembretes relativos ao prazo da tarefa.
+// Os que mudam de horário voltam a ficar pendentes, o que também rearma os
+// lembretes de uma tarefa recorrente quando ela avança para a próxima ocorrência
+func rescheduleReminders(tx *gorm.DB, todo *entity.Todo) error {
+	if todo.DueDate == nil {
+		return nil
+	}
+
+	var reminders []entity.Reminder
+	err := tx.Where("todo_id = ? AND minutes_before IS NOT NULL", todo.ID).Find(&reminders).Error
+	if err != nil {
+		return err
+	}
+
+	for _, reminder := range reminders {
+		fireAt := todo.DueDate.Add(-time.Duration(*reminder.MinutesBefore) * time.Minute)
+		if fireAt.Equal(reminder.FireAt) {
+			continue
+		}
+
+		err := tx.Model(&reminder).Updates(map[string]interface{}{
+			"fire_at":       fireAt,
+			"sent_at":       nil,
+			"failed_at":     nil,
+			"claimed_until": nil,
+			"attempts":      0,
+			"last_error":    "",
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
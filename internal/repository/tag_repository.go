@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"github.com/vinibsi/todo-api/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagRepository interface {
+	FindOrCreate(userID uint, names []string) ([]entity.Tag, error)
+	ListWithUsage(userID uint) ([]TagUsage, error)
+}
+
+// TagUsage é uma tag com a quantidade de tarefas (fora da lixeira) que a usam
+type TagUsage struct {
+	ID    uint
+	Name  string
+	Count int64
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// FindOrCreate retorna as tags com os nomes informados (já normalizados),
+// criando as que ainda não existem. O conflito no índice único é ignorado
+// para que requisições concorrentes com a mesma tag não falhem
+func (repo *tagRepository) FindOrCreate(userID uint, names []string) ([]entity.Tag, error) {
+	if len(names) == 0 {
+		return []entity.Tag{}, nil
+	}
+
+	tags := make([]entity.Tag, len(names))
+	for i, name := range names {
+		tags[i] = entity.Tag{UserID: userID, Name: name}
+	}
+
+	err := repo.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "name"}},
+		DoNothing: true,
+	}).Create(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var found []entity.Tag
+	err = repo.db.Where("user_id = ? AND name IN ?", userID, names).Order("name ASC").Find(&found).Error
+	return found, err
+}
+
+func (repo *tagRepository) ListWithUsage(userID uint) ([]TagUsage, error) {
+	var usage []TagUsage
+	err := repo.db.Model(&entity.Tag{}).
+		Select("tags.id, tags.name, COUNT(todos.id) AS count").
+		Joins("LEFT JOIN todo_tags ON todo_tags.tag_id = tags.id").
+		Joins("LEFT JOIN todos ON todos.id = todo_tags.todo_id AND todos.deleted_at IS NULL").
+		Where("tags.user_id = ?", userID).
+		Group("tags.id, tags.name").
+		Order("tags.name ASC").
+		Scan(&usage).Error
+	return usage, err
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vinibsi/todo-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// TodoCursor guarda os valores das chaves de ordenação (incluindo o id) do
+// último item de uma página. É trafegado para o cliente de forma opaca.
+type TodoCursor struct {
+	Sort   string        `json:"s"`
+	Values []interface{} `json:"v"`
+}
+
+// EncodeTodoCursor gera o cursor que aponta para depois de todo
+func EncodeTodoCursor(fields []SortField, todo *entity.Todo) string {
+	keys := sortKeys(fields)
+	cursor := TodoCursor{Sort: sortString(keys), Values: make([]interface{}, len(keys))}
+	for i, key := range keys {
+		cursor.Values[i] = sortValue(key.Field, todo)
+	}
+
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeTodoCursor valida o cursor contra a ordenação da requisição atual
+func DecodeTodoCursor(raw string, fields []SortField) (*TodoCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor TodoCursor
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	keys := sortKeys(fields)
+	if cursor.Sort != sortString(keys) || len(cursor.Values) != len(keys) {
+		return nil, fmt.Errorf("%w: cursor does not match the requested sort", ErrInvalidCursor)
+	}
+
+	for i, key := range keys {
+		value, err := parseSortValue(key.Field, cursor.Values[i])
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		cursor.Values[i] = value
+	}
+
+	return &cursor, nil
+}
+
+// applyKeyset restringe a consulta aos registros posteriores ao cursor,
+// comparando as chaves em ordem lexicográfica:
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ...
+func applyKeyset(db *gorm.DB, fields []SortField, cursor *TodoCursor) *gorm.DB {
+	keys := sortKeys(fields)
+
+	var conditions []string
+	var args []interface{}
+	for i, key := range keys {
+		after, afterArgs := keysetAfter(key, cursor.Values[i])
+		if after == "" {
+			continue
+		}
+
+		parts := make([]string, 0, i+1)
+		var partArgs []interface{}
+		for j := 0; j < i; j++ {
+			equal, equalArgs := keysetEqual(keys[j], cursor.Values[j])
+			parts = append(parts, equal)
+			partArgs = append(partArgs, equalArgs...)
+		}
+		parts = append(parts, after)
+		partArgs = append(partArgs, afterArgs...)
+
+		conditions = append(conditions, "("+strings.Join(parts, " AND ")+")")
+		args = append(args, partArgs...)
+	}
+
+	if len(conditions) == 0 {
+		return db.Where("1 = 0")
+	}
+	return db.Where("("+strings.Join(conditions, " OR ")+")", args...)
+}
+
+// keysetAfter retorna a condição "vem depois de value" para a chave. Uma
+// string vazia indica que nenhum registro pode vir depois nesta chave.
+func keysetAfter(key SortField, value interface{}) (string, []interface{}) {
+	operator := ">"
+	if key.Desc {
+		operator = "<"
+	}
+	column := todoSortColumns[key.Field]
+
+	if key.Field == "due_date" {
+		// Datas vazias ficam sempre no fim
+		if value == nil {
+			return "", nil
+		}
+		return "(due_date IS NULL OR due_date " + operator + " ?)", []interface{}{value}
+	}
+
+	return column + " " + operator + " ?", []interface{}{value}
+}
+
+func keysetEqual(key SortField, value interface{}) (string, []interface{}) {
+	if value == nil {
+		return todoSortColumns[key.Field] + " IS NULL", nil
+	}
+	return todoSortColumns[key.Field] + " = ?", []interface{}{value}
+}
+
+func sortString(keys []SortField) string {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key.Field
+		if key.Desc {
+			parts[i] = "-" + key.Field
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// sortValue extrai de todo o valor equivalente à expressão de ordenação
+func sortValue(field string, todo *entity.Todo) interface{} {
+	switch field {
+	case "id":
+		return todo.ID
+	case "title":
+		return todo.Title
+	case "priority":
+		return priorityRank(todo.Priority)
+	case "due_date":
+		if todo.DueDate == nil {
+			return nil
+		}
+		return todo.DueDate.Format(time.RFC3339Nano)
+	case "completed":
+		return todo.Completed
+	case "created_at":
+		return todo.CreatedAt.Format(time.RFC3339Nano)
+	case "updated_at":
+		return todo.UpdatedAt.Format(time.RFC3339Nano)
+	}
+	return nil
+}
+
+// parseSortValue converte o valor decodificado do JSON para o tipo da coluna
+func parseSortValue(field string, raw interface{}) (interface{}, error) {
+	switch field {
+	case "id", "priority":
+		number, ok := raw.(json.Number)
+		if !ok {
+			return nil, ErrInvalidCursor
+		}
+		value, err := number.Int64()
+		if err != nil {
+			return nil, err
+		}
+		return value, nil
+	case "title":
+		value, ok := raw.(string)
+		if !ok {
+			return nil, ErrInvalidCursor
+		}
+		return value, nil
+	case "completed":
+		value, ok := raw.(bool)
+		if !ok {
+			return nil, ErrInvalidCursor
+		}
+		return value, nil
+	case "due_date", "created_at", "updated_at":
+		if raw == nil && field == "due_date" {
+			return nil, nil
+		}
+		value, ok := raw.(string)
+		if !ok {
+			return nil, ErrInvalidCursor
+		}
+		return time.Parse(time.RFC3339Nano, value)
+	}
+	return nil, ErrInvalidCursor
+}
+
+// priorityRank espelha a expressão CASE usada na ordenação por prioridade
+func priorityRank(priority string) int {
+	switch priority {
+	case "high":
+		return 3
+	case "medium":
+		return 2
+	case "low":
+		return 1
+	}
+	return 0
+}
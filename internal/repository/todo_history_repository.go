@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"github.com/vinibsi/todo-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+// Tentativas de gravar uma revisão quando outra requisição ocupa o mesmo número
+const historyRecordAttempts = 3
+
+type TodoHistoryRepository interface {
+	Record(entry *entity.TodoHistory) error
+	List(userID, todoID uint) ([]entity.TodoHistory, error)
+	GetRevision(userID, todoID uint, revision int) (*entity.TodoHistory, error)
+}
+
+type todoHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewTodoHistoryRepository(db *gorm.DB) TodoHistoryRepository {
+	return &todoHistoryRepository{db: db}
+}
+
+// Record grava a entrada com a próxima revisão da tarefa. O índice único em
+// (todo_id, revision) impede duas gravações com o mesmo número; a perdedora
+// tenta de novo com o número seguinte
+func (repo *todoHistoryRepository) Record(entry *entity.TodoHistory) error {
+	var err error
+	for attempt := 0; attempt < historyRecordAttempts; attempt++ {
+		err = repo.db.Transaction(func(tx *gorm.DB) error {
+			var last int
+			if err := tx.Model(&entity.TodoHistory{}).Where("todo_id = ?", entry.TodoID).
+				Select("COALESCE(MAX(revision), 0)").Scan(&last).Error; err != nil {
+				return err
+			}
+
+			entry.ID = 0
+			entry.Revision = last + 1
+			return tx.Create(entry).Error
+		})
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// List retorna as revisões da mais recente para a mais antiga
+func (repo *todoHistoryRepository) List(userID, todoID uint) ([]entity.TodoHistory, error) {
+	var entries []entity.TodoHistory
+	err := repo.db.Where("user_id = ? AND todo_id = ?", userID, todoID).
+		Order("revision DESC").
+		Find(&entries).Error
+	return entries, err
+}
+
+func (repo *todoHistoryRepository) GetRevision(userID, todoID uint, revision int) (*entity.TodoHistory, error) {
+	var entry entity.TodoHistory
+	err := repo.db.Where("user_id = ? AND todo_id = ? AND revision = ?", userID, todoID, revision).
+		First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
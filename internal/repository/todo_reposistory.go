@@ -1,73 +1,553 @@
 package repository
 
 import (
+	"errors"
+	"time"
+
 	"github.com/vinibsi/todo-api/internal/entity"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TodoRepository interface {
 	Create(todo *entity.Todo) error
-	GetByID(id uint) (*entity.Todo, error)
-	GetAll(limit, offset int) ([]entity.Todo, int64, error)
+	GetByID(userID, id uint) (*entity.Todo, error)
+	GetAll(query TodoQuery) ([]entity.Todo, int64, error)
 	Update(todo *entity.Todo) error
-	Delete(id uint) error
-	GetByCompleted(completed bool, limit, offset int) ([]entity.Todo, int64, error)
+	Delete(userID, id uint, version int) error
+	Search(term string, query TodoQuery) ([]TodoSearchResult, int64, error)
+	Descendants(userID, id uint) ([]entity.Todo, error)
+	ListDeleted(userID uint, limit, offset int) ([]entity.Todo, int64, error)
+	GetDeleted(userID, id uint) (*entity.Todo, error)
+	Restore(userID, id uint) error
+	Purge(userID, id uint, version int) error
+	PurgeDeletedBefore(cutoff time.Time, limit int) (int, error)
+	Each(query TodoQuery, batchSize int, fn func([]entity.Todo) error) error
+	ExistsByTitle(userID uint, title string, projectID *uint) (bool, error)
+}
+
+// TodoQuery agrupa os filtros e a paginação da listagem de tarefas.
+// A listagem é sempre restrita às tarefas de UserID; os demais filtros vazios
+// são ignorados e combinados com AND. Tags filtra pelas tarefas com qualquer
+// uma das tags (TagMatchAny) ou com todas elas (TagMatchAll). Quando After é
+// informado a paginação é feita por cursor e Offset é ignorado.
+type TodoQuery struct {
+	UserID    uint
+	Completed *bool
+	Priority  string
+	DueBefore *time.Time
+	DueAfter  *time.Time
+	HasDue    *bool
+	Overdue   bool
+	ProjectID *uint
+	Inbox     bool
+	ParentID  *uint
+	Tags      []string
+	TagMatch  string
+	Sort      []SortField
+	After     *TodoCursor
+	SkipCount bool
+	Limit     int
+	Offset    int
 }
 
+// Modos de combinação do filtro por tags
+const (
+	TagMatchAny = "any"
+	TagMatchAll = "all"
+)
+
+// ErrStaleVersion indica que a tarefa mudou (ou foi removida) desde que foi
+// lida: a gravação condicional à versão não encontrou a linha
+var ErrStaleVersion = errors.New("stale todo version")
+
+// ErrParentDeleted indica que a tarefa pai está na lixeira (ou não existe
+// mais): a subtarefa só pode voltar depois dela
+var ErrParentDeleted = errors.New("parent todo is deleted")
+
 type todoRepository struct {
-	db *gorm.DB
+	db         *gorm.DB
+	searchMode string
 }
 
 func NewTodoRepository(db *gorm.DB) TodoRepository {
-	return &todoRepository{db: db}
+	return &todoRepository{db: db, searchMode: detectSearchMode(db)}
 }
 
 func (repo *todoRepository) Create(todo *entity.Todo) error {
+	if todo.Version == 0 {
+		todo.Version = 1
+	}
 	return repo.db.Create(todo).Error
 }
 
-func (repo *todoRepository) GetByID(id uint) (*entity.Todo, error) {
+func (repo *todoRepository) GetByID(userID, id uint) (*entity.Todo, error) {
 	var todo entity.Todo
-	err := repo.db.First(&todo, id).Error
+	err := repo.db.Preload("Tags", orderTags).Where("user_id = ?", userID).First(&todo, id).Error
 	if err != nil {
 		return nil, err
 	}
+	if err := repo.loadProgress([]*entity.Todo{&todo}); err != nil {
+		return nil, err
+	}
 	return &todo, nil
 }
 
-func (repo *todoRepository) GetAll(limit, offset int) ([]entity.Todo, int64, error) {
+func (repo *todoRepository) GetAll(query TodoQuery) ([]entity.Todo, int64, error) {
 	var todos []entity.Todo
 	var total int64
 
-	// Conta o total de registros
-	if err := repo.db.Model(&entity.Todo{}).Count(&total).Error; err != nil {
-		return nil, 0, err
+	// Conta o total de registros que atendem aos filtros
+	if !query.SkipCount {
+		if err := repo.filtered(query).Count(&total).Error; err != nil {
+			return nil, 0, err
+		}
 	}
 
-	// Busca os registros com paginação
-	err := repo.db.Limit(limit).Offset(offset).Order("created_at DESC").Find(&todos).Error
+	// Busca os registros com paginação por cursor ou por offset
+	db := applySort(repo.filtered(query), query.Sort)
+	if query.After != nil {
+		db = applyKeyset(db, query.Sort, query.After)
+	} else {
+		db = db.Offset(query.Offset)
+	}
+	if err := db.Preload("Tags", orderTags).Limit(query.Limit).Find(&todos).Error; err != nil {
+		return nil, 0, err
+	}
 
+	err := repo.loadProgress(todoPointers(todos))
 	return todos, total, err
 }
 
+// Each percorre as tarefas que atendem aos filtros em lotes de batchSize,
+// ordenadas por id, sem carregar todas em memória. Ordenação e paginação de
+// query são ignoradas
+func (repo *todoRepository) Each(query TodoQuery, batchSize int, fn func([]entity.Todo) error) error {
+	var lastID uint
+	for {
+		var todos []entity.Todo
+		err := repo.filtered(query).
+			Where("todos.id > ?", lastID).
+			Preload("Tags", orderTags).
+			Order("todos.id ASC").Limit(batchSize).
+			Find(&todos).Error
+		if err != nil {
+			return err
+		}
+		if len(todos) == 0 {
+			return nil
+		}
+		if err := repo.loadProgress(todoPointers(todos)); err != nil {
+			return err
+		}
+		if err := fn(todos); err != nil {
+			return err
+		}
+		if len(todos) < batchSize {
+			return nil
+		}
+		lastID = todos[len(todos)-1].ID
+	}
+}
+
+// ExistsByTitle indica se o usuário já tem uma tarefa ativa com o título no
+// projeto (projectID nil é a caixa de entrada)
+func (repo *todoRepository) ExistsByTitle(userID uint, title string, projectID *uint) (bool, error) {
+	db := repo.db.Model(&entity.Todo{}).Where("user_id = ? AND title = ?", userID, title)
+	if projectID != nil {
+		db = db.Where("project_id = ?", *projectID)
+	} else {
+		db = db.Where("project_id IS NULL")
+	}
+
+	var count int64
+	err := db.Limit(1).Count(&count).Error
+	return count > 0, err
+}
+
+// Update grava a tarefa somente se a versão no banco ainda for a que foi lida
+// (todo.Version), incrementando-a. Caso contrário retorna ErrStaleVersion,
+// sem sobrescrever a alteração concorrente. Na mesma transação substitui as
+// tags associadas e recalcula os lembretes relativos ao prazo
 func (repo *todoRepository) Update(todo *entity.Todo) error {
-	return repo.db.Save(todo).Error
+	read := todo.Version
+	todo.Version = read + 1
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(todo).Where("user_id = ? AND version = ?", todo.UserID, read).
+			Select("*").Omit(clause.Associations).Updates(todo)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrStaleVersion
+		}
+		if err := tx.Model(todo).Association("Tags").Replace(todo.Tags); err != nil {
+			return err
+		}
+		return rescheduleReminders(tx, todo)
+	})
+	if err != nil {
+		todo.Version = read
+	}
+	return err
 }
 
-func (repo *todoRepository) Delete(id uint) error {
-	return repo.db.Delete(&entity.Todo{}, id).Error
+// Delete move a tarefa e as subtarefas para a lixeira se a versão da tarefa
+// ainda for version. Todas recebem o mesmo deleted_at, que Restore usa para
+// saber quais subtarefas voltam junto
+func (repo *todoRepository) Delete(userID, id uint, version int) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		ids, err := descendantIDs(tx, userID, id)
+		if err != nil {
+			return err
+		}
+
+		deletedAt := tx.NowFunc()
+		result := tx.Model(&entity.Todo{}).Where("id = ? AND user_id = ? AND version = ?", id, userID, version).
+			UpdateColumn("deleted_at", deletedAt)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrStaleVersion
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		return tx.Model(&entity.Todo{}).Where("id IN ? AND user_id = ?", ids, userID).
+			UpdateColumn("deleted_at", deletedAt).Error
+	})
 }
 
-func (r *todoRepository) GetByCompleted(completed bool, limit, offset int) ([]entity.Todo, int64, error) {
+// Descendants retorna todas as subtarefas abaixo da tarefa, em qualquer nível
+func (repo *todoRepository) Descendants(userID, id uint) ([]entity.Todo, error) {
+	ids, err := descendantIDs(repo.db, userID, id)
+	if err != nil || len(ids) == 0 {
+		return []entity.Todo{}, err
+	}
+
 	var todos []entity.Todo
-	var total int64
+	err = repo.db.Preload("Tags", orderTags).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").Order("id ASC").
+		Find(&todos, ids).Error
+	if err != nil {
+		return nil, err
+	}
 
-	query := r.db.Model(&entity.Todo{}).Where("completed = ?", completed)
+	err = repo.loadProgress(todoPointers(todos))
+	return todos, err
+}
 
-	if err := query.Count(&total).Error; err != nil {
+// descendantIDs percorre a hierarquia com uma CTE recursiva. UNION (e não
+// UNION ALL) impede laço infinito caso exista um ciclo no banco
+func descendantIDs(db *gorm.DB, userID, id uint) ([]uint, error) {
+	var ids []uint
+	err := db.Raw(`WITH RECURSIVE tree(id) AS (
+			SELECT id FROM todos WHERE parent_id = ? AND user_id = ? AND deleted_at IS NULL
+			UNION
+			SELECT todos.id FROM todos JOIN tree ON todos.parent_id = tree.id WHERE todos.deleted_at IS NULL
+		) SELECT id FROM tree`, id, userID).Scan(&ids).Error
+	return ids, err
+}
+
+// ListDeleted lista a lixeira, da remoção mais recente para a mais antiga.
+// Subtarefas removidas junto com a tarefa pai ficam de fora: voltam com ela
+func (repo *todoRepository) ListDeleted(userID uint, limit, offset int) ([]entity.Todo, int64, error) {
+	trash := func() *gorm.DB {
+		return repo.db.Unscoped().Model(&entity.Todo{}).
+			Joins("LEFT JOIN todos AS parent ON parent.id = todos.parent_id").
+			Where("todos.user_id = ? AND todos.deleted_at IS NOT NULL", userID).
+			Where("parent.id IS NULL OR parent.deleted_at IS NULL OR parent.deleted_at <> todos.deleted_at")
+	}
+
+	var total int64
+	if err := trash().Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	err := query.Limit(limit).Offset(offset).Order("created_at DESC").Find(&todos).Error
+	var todos []entity.Todo
+	err := trash().Select("todos.*").Preload("Tags", orderTags).
+		Order("todos.deleted_at DESC").Order("todos.id DESC").
+		Limit(limit).Offset(offset).
+		Find(&todos).Error
 	return todos, total, err
 }
+
+// GetDeleted busca uma tarefa que está na lixeira
+func (repo *todoRepository) GetDeleted(userID, id uint) (*entity.Todo, error) {
+	var todo entity.Todo
+	err := repo.db.Unscoped().Preload("Tags", orderTags).
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		First(&todo, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &todo, nil
+}
+
+// Restore tira a tarefa da lixeira junto com as subtarefas removidas na
+// mesma operação (mesmo deleted_at). Subtarefas removidas antes continuam lá.
+// Uma subtarefa cuja tarefa pai está na lixeira não volta (ErrParentDeleted):
+// ficaria ativa sob uma tarefa que a limpeza da lixeira apaga
+func (repo *todoRepository) Restore(userID, id uint) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var todo entity.Todo
+		err := tx.Unscoped().Select("id", "parent_id").
+			Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+			First(&todo, id).Error
+		if err != nil {
+			return err
+		}
+		if todo.ParentID != nil {
+			var count int64
+			err := tx.Model(&entity.Todo{}).Where("id = ? AND user_id = ?", *todo.ParentID, userID).Count(&count).Error
+			if err != nil {
+				return err
+			}
+			if count == 0 {
+				return ErrParentDeleted
+			}
+		}
+
+		var ids []uint
+		err = tx.Raw(`WITH RECURSIVE tree(id, deleted_at) AS (
+				SELECT id, deleted_at FROM todos WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL
+				UNION
+				SELECT todos.id, todos.deleted_at FROM todos
+				JOIN tree ON todos.parent_id = tree.id AND todos.deleted_at = tree.deleted_at
+			) SELECT id FROM tree`, id, userID).Scan(&ids).Error
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Unscoped().Model(&entity.Todo{}).Where("id IN ?", ids).
+			Updates(map[string]interface{}{"deleted_at": nil, "version": gorm.Expr("version + 1")}).Error
+	})
+}
+
+// Purge apaga definitivamente a tarefa com as subtarefas, seus lembretes,
+// tags e histórico. Uma tarefa ativa leva todas as subtarefas; uma tarefa na
+// lixeira leva apenas as que também estão lá
+func (repo *todoRepository) Purge(userID, id uint, version int) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var todo entity.Todo
+		err := tx.Unscoped().Select("id", "deleted_at").Where("user_id = ?", userID).First(&todo, id).Error
+		if err != nil {
+			return err
+		}
+
+		// Incrementar a versão condicionalmente trava a linha até o fim da
+		// transação, tornando a verificação atômica
+		result := tx.Unscoped().Model(&entity.Todo{}).Where("id = ? AND user_id = ? AND version = ?", id, userID, version).
+			UpdateColumn("version", gorm.Expr("version + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrStaleVersion
+		}
+
+		ids, err := subtreeIDs(tx, []uint{id}, todo.DeletedAt.Valid)
+		if err != nil {
+			return err
+		}
+		return purgeTodos(tx, ids)
+	})
+}
+
+// PurgeDeletedBefore apaga definitivamente até limit tarefas que estão na
+// lixeira desde antes de cutoff, retornando quantas foram apagadas
+func (repo *todoRepository) PurgeDeletedBefore(cutoff time.Time, limit int) (int, error) {
+	purged := 0
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var roots []uint
+		err := tx.Unscoped().Model(&entity.Todo{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+			Order("deleted_at ASC").Limit(limit).
+			Pluck("id", &roots).Error
+		if err != nil || len(roots) == 0 {
+			return err
+		}
+
+		ids, err := subtreeIDs(tx, roots, true)
+		if err != nil {
+			return err
+		}
+		purged = len(ids)
+		return purgeTodos(tx, ids)
+	})
+	return purged, err
+}
+
+// subtreeIDs retorna as tarefas informadas e as suas subtarefas, inclusive as
+// que estão na lixeira. Com onlyDeleted o percurso segue apenas pelas
+// subtarefas na lixeira
+func subtreeIDs(db *gorm.DB, roots []uint, onlyDeleted bool) ([]uint, error) {
+	children := "SELECT todos.id FROM todos JOIN tree ON todos.parent_id = tree.id"
+	if onlyDeleted {
+		children += " WHERE todos.deleted_at IS NOT NULL"
+	}
+
+	var ids []uint
+	err := db.Raw(`WITH RECURSIVE tree(id) AS (
+			SELECT id FROM todos WHERE id IN ?
+			UNION
+			`+children+`
+		) SELECT id FROM tree`, roots).Scan(&ids).Error
+	return ids, err
+}
+
+// purgeTodos apaga as tarefas e seus dados. Subtarefas que ficam (ativas sob
+// uma tarefa na lixeira) passam para a raiz em vez de apontar para uma tarefa
+// que não existe mais
+func purgeTodos(tx *gorm.DB, ids []uint) error {
+	err := tx.Unscoped().Model(&entity.Todo{}).Where("parent_id IN ? AND id NOT IN ?", ids, ids).
+		UpdateColumn("parent_id", nil).Error
+	if err != nil {
+		return err
+	}
+	for _, table := range []string{"reminders", "todo_tags", "todo_histories"} {
+		if err := tx.Exec("DELETE FROM "+table+" WHERE todo_id IN ?", ids).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Unscoped().Delete(&entity.Todo{}, ids).Error
+}
+
+// filtered monta uma nova consulta com os filtros informados
+func (repo *todoRepository) filtered(query TodoQuery) *gorm.DB {
+	db := repo.db.Model(&entity.Todo{}).Where("todos.user_id = ?", query.UserID)
+
+	if query.Completed != nil {
+		db = db.Where("todos.completed = ?", *query.Completed)
+	}
+	if query.Priority != "" {
+		db = db.Where("todos.priority = ?", query.Priority)
+	}
+	if query.DueBefore != nil {
+		db = db.Where("todos.due_date < ?", *query.DueBefore)
+	}
+	if query.DueAfter != nil {
+		db = db.Where("todos.due_date > ?", *query.DueAfter)
+	}
+	if query.HasDue != nil {
+		if *query.HasDue {
+			db = db.Where("todos.due_date IS NOT NULL")
+		} else {
+			db = db.Where("todos.due_date IS NULL")
+		}
+	}
+	if query.ProjectID != nil {
+		db = db.Where("todos.project_id = ?", *query.ProjectID)
+	}
+	if query.Inbox {
+		db = db.Where("todos.project_id IS NULL")
+	}
+	if query.ParentID != nil {
+		db = db.Where("todos.parent_id = ?", *query.ParentID)
+	}
+	if len(query.Tags) > 0 {
+		tagged := repo.db.Table("todo_tags").
+			Select("todo_tags.todo_id").
+			Joins("JOIN tags ON tags.id = todo_tags.tag_id").
+			Where("tags.user_id = ? AND tags.name IN ?", query.UserID, query.Tags)
+		if query.TagMatch == TagMatchAll {
+			tagged = tagged.Group("todo_tags.todo_id").Having("COUNT(DISTINCT tags.id) = ?", len(query.Tags))
+		}
+		db = db.Where("todos.id IN (?)", tagged)
+	}
+	if query.Overdue {
+		db = db.Where("todos.completed = ? AND todos.due_date IS NOT NULL AND todos.due_date < ?", false, time.Now())
+	}
+
+	return db
+}
+
+func orderTags(db *gorm.DB) *gorm.DB {
+	return db.Order("tags.name ASC")
+}
+
+// loadTags carrega as tags de várias tarefas com uma única consulta, para os
+// casos em que Preload não se aplica (ex.: resultados lidos com Scan)
+func (repo *todoRepository) loadTags(todos []*entity.Todo) error {
+	if len(todos) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(todos))
+	for i, todo := range todos {
+		ids[i] = todo.ID
+	}
+
+	var rows []struct {
+		TodoID uint
+		entity.Tag
+	}
+	err := repo.db.Table("tags").
+		Select("todo_tags.todo_id, tags.*").
+		Joins("JOIN todo_tags ON todo_tags.tag_id = tags.id").
+		Where("todo_tags.todo_id IN ?", ids).
+		Order("tags.name ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	byTodo := make(map[uint][]entity.Tag, len(todos))
+	for _, row := range rows {
+		byTodo[row.TodoID] = append(byTodo[row.TodoID], row.Tag)
+	}
+	for _, todo := range todos {
+		todo.Tags = byTodo[todo.ID]
+	}
+	return nil
+}
+
+// loadProgress preenche os contadores de subtarefas diretas de cada tarefa
+func (repo *todoRepository) loadProgress(todos []*entity.Todo) error {
+	if len(todos) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(todos))
+	for i, todo := range todos {
+		ids[i] = todo.ID
+	}
+
+	var rows []struct {
+		ParentID uint
+		Total    int
+		Done     int
+	}
+	err := repo.db.Model(&entity.Todo{}).
+		Select("parent_id, COUNT(*) AS total, SUM(CASE WHEN completed THEN 1 ELSE 0 END) AS done").
+		Where("parent_id IN ?", ids).
+		Group("parent_id").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	byParent := make(map[uint]int, len(rows))
+	for i, row := range rows {
+		byParent[row.ParentID] = i
+	}
+	for _, todo := range todos {
+		todo.SubtasksTotal, todo.SubtasksDone = 0, 0
+		if i, ok := byParent[todo.ID]; ok {
+			todo.SubtasksTotal, todo.SubtasksDone = rows[i].Total, rows[i].Done
+		}
+	}
+	return nil
+}
+
+func todoPointers(todos []entity.Todo) []*entity.Todo {
+	pointers := make([]*entity.Todo, len(todos))
+	for i := range todos {
+		pointers[i] = &todos[i]
+	}
+	return pointers
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/vinibsi/todo-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+// TodoSearchResult é uma tarefa encontrada pela busca textual, com a
+// relevância (maior é melhor) e um trecho com os termos destacados
+type TodoSearchResult struct {
+	entity.Todo
+	Rank    float64 `gorm:"column:search_rank"`
+	Snippet string  `gorm:"column:search_snippet"`
+}
+
+const (
+	highlightStart = "<mark>"
+	highlightEnd   = "</mark>"
+)
+
+// Estratégias de busca de acordo com o banco em uso
+const (
+	searchPostgres = "postgres"
+	searchFTS5     = "fts5"
+	searchLike     = "like"
+)
+
+func detectSearchMode(db *gorm.DB) string {
+	switch {
+	case db.Dialector.Name() == "postgres":
+		return searchPostgres
+	case db.Migrator().HasTable("todos_fts"):
+		return searchFTS5
+	default:
+		return searchLike
+	}
+}
+
+func (repo *todoRepository) Search(term string, query TodoQuery) ([]TodoSearchResult, int64, error) {
+	terms := searchTerms(term)
+	if len(terms) == 0 {
+		return []TodoSearchResult{}, 0, nil
+	}
+
+	var build func() *gorm.DB
+	switch repo.searchMode {
+	case searchPostgres:
+		build = func() *gorm.DB {
+			return repo.filtered(query).Where("todos.search_vector @@ websearch_to_tsquery('simple', ?)", term)
+		}
+	case searchFTS5:
+		match := fts5Match(terms)
+		build = func() *gorm.DB {
+			return repo.filtered(query).
+				Joins("JOIN todos_fts ON todos_fts.rowid = todos.id").
+				Where("todos_fts MATCH ?", match)
+		}
+	default:
+		build = func() *gorm.DB {
+			db := repo.filtered(query)
+			for _, t := range terms {
+				pattern := "%" + escapeLike(t) + "%"
+				db = db.Where("(todos.title LIKE ? ESCAPE '\\' OR todos.description LIKE ? ESCAPE '\\')", pattern, pattern)
+			}
+			return db
+		}
+	}
+
+	var total int64
+	if !query.SkipCount {
+		if err := build().Count(&total).Error; err != nil {
+			return nil, 0, err
+		}
+	}
+
+	db := build()
+	switch repo.searchMode {
+	case searchPostgres:
+		db = db.Select(`todos.*,
+			ts_rank(todos.search_vector, websearch_to_tsquery('simple', ?)) AS search_rank,
+			ts_headline('simple', coalesce(todos.title, '') || ' ' || coalesce(todos.description, ''),
+				websearch_to_tsquery('simple', ?), 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2') AS search_snippet`,
+			term, term)
+	case searchFTS5:
+		// bm25 retorna valores menores para resultados melhores
+		db = db.Select(`todos.*,
+			-bm25(todos_fts, 10.0, 5.0) AS search_rank,
+			snippet(todos_fts, -1, '<mark>', '</mark>', '…', 12) AS search_snippet`)
+	default:
+		pattern := "%" + escapeLike(strings.Join(terms, " ")) + "%"
+		db = db.Select(`todos.*,
+			(CASE WHEN todos.title LIKE ? ESCAPE '\' THEN 2 ELSE 0 END +
+			 CASE WHEN todos.description LIKE ? ESCAPE '\' THEN 1 ELSE 0 END) AS search_rank`,
+			pattern, pattern)
+	}
+
+	var results []TodoSearchResult
+	err := db.Order("search_rank DESC").Order("todos.id DESC").
+		Limit(query.Limit).Offset(query.Offset).
+		Scan(&results).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	todos := make([]*entity.Todo, len(results))
+	for i := range results {
+		todos[i] = &results[i].Todo
+		if repo.searchMode == searchLike {
+			results[i].Snippet = highlight(results[i].Title+" "+results[i].Description, terms)
+		}
+	}
+	if err := repo.loadTags(todos); err != nil {
+		return nil, 0, err
+	}
+	if err := repo.loadProgress(todos); err != nil {
+		return nil, 0, err
+	}
+
+	return results, total, nil
+}
+
+// searchTerms separa o texto em palavras, descartando pontuação e operadores
+func searchTerms(term string) []string {
+	return strings.FieldsFunc(term, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// fts5Match monta uma expressão MATCH segura: cada termo entre aspas, todos
+// obrigatórios, e o último aceitando prefixo para buscas enquanto se digita
+func fts5Match(terms []string) string {
+	quoted := make([]string, len(terms))
+	for i, t := range terms {
+		quoted[i] = `"` + t + `"`
+	}
+	quoted[len(quoted)-1] += "*"
+	return strings.Join(quoted, " ")
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// highlight destaca os termos no texto, usado quando o banco não gera trechos
+func highlight(text string, terms []string) string {
+	quoted := make([]string, len(terms))
+	for i, t := range terms {
+		quoted[i] = regexp.QuoteMeta(t)
+	}
+	pattern := regexp.MustCompile("(?i)(" + strings.Join(quoted, "|") + ")")
+	return pattern.ReplaceAllString(strings.TrimSpace(text), highlightStart+"$1"+highlightEnd)
+}
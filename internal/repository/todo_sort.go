@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// DefaultTodoSort é a ordenação usada quando o cliente não informa nenhuma
+const DefaultTodoSort = "-created_at"
+
+// SortField representa uma chave de ordenação já validada
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// todoSortColumns é a lista de campos aceitos na ordenação e a expressão SQL
+// correspondente. Somente expressões desta lista chegam à consulta.
+var todoSortColumns = map[string]string{
+	"id":         "id",
+	"title":      "title",
+	"priority":   "CASE priority WHEN 'high' THEN 3 WHEN 'medium' THEN 2 WHEN 'low' THEN 1 ELSE 0 END",
+	"due_date":   "due_date",
+	"completed":  "completed",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// AllowedTodoSortFields retorna os campos aceitos em ordem alfabética
+func AllowedTodoSortFields() []string {
+	fields := make([]string, 0, len(todoSortColumns))
+	for field := range todoSortColumns {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// ParseTodoSort interpreta valores como "-priority,due_date,title". O prefixo
+// "-" indica ordem decrescente; campos fora da lista permitida geram erro.
+func ParseTodoSort(raw string) ([]SortField, error) {
+	if strings.TrimSpace(raw) == "" {
+		raw = DefaultTodoSort
+	}
+
+	var fields []SortField
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		desc := strings.HasPrefix(part, "-")
+		name := strings.TrimLeft(part, "+-")
+
+		if _, ok := todoSortColumns[name]; !ok {
+			return nil, fmt.Errorf("invalid sort field %q, allowed fields: %s", name, strings.Join(AllowedTodoSortFields(), ", "))
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate sort field %q", name)
+		}
+		seen[name] = true
+
+		fields = append(fields, SortField{Field: name, Desc: desc})
+	}
+
+	return fields, nil
+}
+
+// sortKeys completa a ordenação com o id como critério de desempate para que
+// a ordem seja sempre total e determinística.
+func sortKeys(fields []SortField) []SortField {
+	if len(fields) == 0 {
+		fields, _ = ParseTodoSort(DefaultTodoSort)
+	}
+
+	for _, field := range fields {
+		if field.Field == "id" {
+			return fields
+		}
+	}
+
+	keys := make([]SortField, len(fields), len(fields)+1)
+	copy(keys, fields)
+	return append(keys, SortField{Field: "id", Desc: fields[len(fields)-1].Desc})
+}
+
+// applySort adiciona as cláusulas ORDER BY
+func applySort(db *gorm.DB, fields []SortField) *gorm.DB {
+	for _, field := range sortKeys(fields) {
+		direction := "ASC"
+		if field.Desc {
+			direction = "DESC"
+		}
+
+		// Datas vazias ficam sempre no fim, independente do banco
+		if field.Field == "due_date" {
+			db = db.Order("due_date IS NULL")
+		}
+		db = db.Order(todoSortColumns[field.Field] + " " + direction)
+	}
+
+	return db
+}
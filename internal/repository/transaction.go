@@ -0,0 +1,39 @@
+package repository
+
+import "gorm.io/gorm"
+
+// Repositories agrupa os repositórios ligados a uma mesma transação.
+// Transaction, chamado dentro dela, abre um savepoint
+type Repositories struct {
+	Transactor
+	Todos    TodoRepository
+	Projects ProjectRepository
+	Tags     TagRepository
+	History  TodoHistoryRepository
+}
+
+// Transactor executa fn em uma transação: se fn retornar erro, tudo o que foi
+// gravado pelos repositórios recebidos é desfeito
+type Transactor interface {
+	Transaction(fn func(repos Repositories) error) error
+}
+
+type gormTransactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) Transactor {
+	return &gormTransactor{db: db}
+}
+
+func (t *gormTransactor) Transaction(fn func(repos Repositories) error) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		return fn(Repositories{
+			Transactor: &gormTransactor{db: tx},
+			Todos:      NewTodoRepository(tx),
+			Projects:   NewProjectRepository(tx),
+			Tags:       NewTagRepository(tx),
+			History:    NewTodoHistoryRepository(tx),
+		})
+	})
+}
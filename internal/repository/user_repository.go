@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"github.com/vinibsi/todo-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+type UserRepository interface {
+	Create(user *entity.User) error
+	GetByID(id uint) (*entity.User, error)
+	GetByEmail(email string) (*entity.User, error)
+}
+
+type userRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRepository(db *gorm.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+func (repo *userRepository) Create(user *entity.User) error {
+	return repo.db.Create(user).Error
+}
+
+func (repo *userRepository) GetByID(id uint) (*entity.User, error) {
+	var user entity.User
+	if err := repo.db.First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (repo *userRepository) GetByEmail(email string) (*entity.User, error) {
+	var user entity.User
+	if err := repo.db.Where("email = ?", email).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
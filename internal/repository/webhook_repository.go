@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"github.com/vinibsi/todo-api/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository interface {
+	Create(webhook *entity.Webhook) error
+	GetByID(userID, id uint) (*entity.Webhook, error)
+	ListByUser(userID uint) ([]entity.Webhook, error)
+	ListActive(userID uint) ([]entity.Webhook, error)
+	Update(webhook *entity.Webhook) error
+	Delete(userID, id uint) (bool, error)
+
+	CreateDeliveries(deliveries []entity.WebhookDelivery) error
+	ListDeliveries(webhookID uint, limit int) ([]entity.WebhookDelivery, error)
+	GetDelivery(webhookID, id uint) (*entity.WebhookDelivery, error)
+	Redeliver(webhookID, id uint, now time.Time) (bool, error)
+	ClaimDue(now time.Time, limit int, lease time.Duration) ([]entity.WebhookDelivery, error)
+	RecordAttempt(delivery *entity.WebhookDelivery, attempt *entity.WebhookAttempt) error
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (repo *webhookRepository) Create(webhook *entity.Webhook) error {
+	return repo.db.Create(webhook).Error
+}
+
+func (repo *webhookRepository) GetByID(userID, id uint) (*entity.Webhook, error) {
+	var webhook entity.Webhook
+	if err := repo.db.Where("user_id = ?", userID).First(&webhook, id).Error; err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (repo *webhookRepository) ListByUser(userID uint) ([]entity.Webhook, error) {
+	var webhooks []entity.Webhook
+	err := repo.db.Where("user_id = ?", userID).Order("id ASC").Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (repo *webhookRepository) ListActive(userID uint) ([]entity.Webhook, error) {
+	var webhooks []entity.Webhook
+	err := repo.db.Where("user_id = ? AND active = ?", userID, true).Order("id ASC").Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (repo *webhookRepository) Update(webhook *entity.Webhook) error {
+	return repo.db.Save(webhook).Error
+}
+
+// Delete remove o webhook junto com as entregas e tentativas registradas
+func (repo *webhookRepository) Delete(userID, id uint) (bool, error) {
+	deleted := false
+
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ?", userID).Delete(&entity.Webhook{}, id)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		deleted = true
+
+		deliveries := tx.Model(&entity.WebhookDelivery{}).Select("id").Where("webhook_id = ?", id)
+		if err := tx.Where("delivery_id IN (?)", deliveries).Delete(&entity.WebhookAttempt{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("webhook_id = ?", id).Delete(&entity.WebhookDelivery{}).Error
+	})
+
+	return deleted, err
+}
+
+func (repo *webhookRepository) CreateDeliveries(deliveries []entity.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return repo.db.Create(&deliveries).Error
+}
+
+// ListDeliveries retorna as entregas mais recentes primeiro
+func (repo *webhookRepository) ListDeliveries(webhookID uint, limit int) ([]entity.WebhookDelivery, error) {
+	var deliveries []entity.WebhookDelivery
+	err := repo.db.Where("webhook_id = ?", webhookID).
+		Order("id DESC").Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+// GetDelivery carrega a entrega com o histórico de tentativas
+func (repo *webhookRepository) GetDelivery(webhookID, id uint) (*entity.WebhookDelivery, error) {
+	var delivery entity.WebhookDelivery
+	err := repo.db.Preload("AttemptLog", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Where("webhook_id = ?", webhookID).First(&delivery, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// Redeliver devolve a entrega à fila para ser enviada em now, com o número de
+// tentativas zerado. O histórico de tentativas é mantido
+func (repo *webhookRepository) Redeliver(webhookID, id uint, now time.Time) (bool, error) {
+	result := repo.db.Model(&entity.WebhookDelivery{}).
+		Where("webhook_id = ? AND id = ?", webhookID, id).
+		Updates(map[string]interface{}{
+			"status":          entity.DeliveryPending,
+			"attempts":        0,
+			"next_attempt_at": now,
+			"claimed_until":   nil,
+			"delivered_at":    nil,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ClaimDue reserva até limit entregas pendentes de webhooks ativos, do mesmo
+// modo que ReminderRepository.ClaimDue
+func (repo *webhookRepository) ClaimDue(now time.Time, limit int, lease time.Duration) ([]entity.WebhookDelivery, error) {
+	var deliveries []entity.WebhookDelivery
+
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&entity.WebhookDelivery{}).
+			Joins("JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id").
+			Where("webhook_deliveries.status = ?", entity.DeliveryPending).
+			Where("webhook_deliveries.next_attempt_at <= ?", now).
+			Where("(webhook_deliveries.claimed_until IS NULL OR webhook_deliveries.claimed_until < ?)", now).
+			Where("webhooks.active = ?", true).
+			Order("webhook_deliveries.next_attempt_at ASC").
+			Limit(limit)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{
+				Strength: "UPDATE",
+				Table:    clause.Table{Name: "webhook_deliveries"},
+				Options:  "SKIP LOCKED",
+			})
+		}
+
+		var ids []uint
+		if err := query.Pluck("webhook_deliveries.id", &ids).Error; err != nil || len(ids) == 0 {
+			return err
+		}
+
+		claimedUntil := now.Add(lease)
+		if err := tx.Model(&entity.WebhookDelivery{}).Where("id IN ?", ids).
+			Update("claimed_until", claimedUntil).Error; err != nil {
+			return err
+		}
+
+		return tx.Preload("Webhook").Order("next_attempt_at ASC").Find(&deliveries, ids).Error
+	})
+
+	return deliveries, err
+}
+
+// RecordAttempt grava a tentativa e o novo estado da entrega na mesma transação
+func (repo *webhookRepository) RecordAttempt(delivery *entity.WebhookDelivery, attempt *entity.WebhookAttempt) error {
+	attempt.DeliveryID = delivery.ID
+	attempt.Error = truncate(attempt.Error, 500)
+	delivery.LastError = truncate(delivery.LastError, 500)
+
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(attempt).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&entity.WebhookDelivery{}).Where("id = ?", delivery.ID).Updates(map[string]interface{}{
+			"status":           delivery.Status,
+			"attempts":         delivery.Attempts,
+			"next_attempt_at":  delivery.NextAttemptAt,
+			"claimed_until":    nil,
+			"last_status_code": delivery.LastStatusCode,
+			"last_error":       delivery.LastError,
+			"delivered_at":     delivery.DeliveredAt,
+		}).Error
+	})
+}
+
+// truncate limita o tamanho mantendo o texto em UTF-8 válido (respostas de
+// webhooks podem vir em qualquer codificação)
+func truncate(value string, size int) string {
+	value = strings.ToValidUTF8(value, "")
+	if len(value) > size {
+		value = strings.ToValidUTF8(value[:size], "")
+	}
+	return value
+}
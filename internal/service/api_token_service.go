@@ -0,0 +1,161 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/auth"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Intervalo mínimo entre atualizações de last_used_at do mesmo token
+const lastUsedResolution = time.Minute
+
+var (
+	ErrAPITokenNotFound = apperror.NotFound("api token not found")
+	ErrInvalidExpiry    = apperror.Validation("expires_at must be in the future")
+)
+
+type APITokenService interface {
+	Create(userID uint, req *dto.CreateAPITokenRequest) (*dto.CreatedAPITokenResponse, error)
+	List(userID uint) ([]dto.APITokenResponse, error)
+	Revoke(userID, id uint) error
+	Authenticate(rawToken string) (*auth.Principal, error)
+}
+
+type apiTokenService struct {
+	repo repository.APITokenRepository
+}
+
+func NewAPITokenService(repo repository.APITokenRepository) APITokenService {
+	return &apiTokenService{repo: repo}
+}
+
+func (s *apiTokenService) Create(userID uint, req *dto.CreateAPITokenRequest) (*dto.CreatedAPITokenResponse, error) {
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidExpiry
+	}
+
+	for _, scope := range req.Scopes {
+		if !auth.IsGrantableScope(scope) {
+			return nil, fmt.Errorf("%w: scope %q cannot be granted", ErrInvalidQuery, scope)
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	raw := auth.APITokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	token := &entity.APIToken{
+		UserID:    userID,
+		Name:      strings.TrimSpace(req.Name),
+		Prefix:    raw[:len(auth.APITokenPrefix)+6],
+		TokenHash: hashAPIToken(raw),
+		Scopes:    strings.Join(uniqueStrings(req.Scopes), ","),
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := s.repo.Create(token); err != nil {
+		return nil, err
+	}
+
+	return &dto.CreatedAPITokenResponse{APITokenResponse: *apiTokenToDTO(token), Token: raw}, nil
+}
+
+func (s *apiTokenService) List(userID uint) ([]dto.APITokenResponse, error) {
+	tokens, err := s.repo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.APITokenResponse, len(tokens))
+	for i, token := range tokens {
+		responses[i] = *apiTokenToDTO(&token)
+	}
+	return responses, nil
+}
+
+func (s *apiTokenService) Revoke(userID, id uint) error {
+	deleted, err := s.repo.Delete(userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrAPITokenNotFound
+	}
+	return nil
+}
+
+// Authenticate valida o token recebido e registra o uso
+func (s *apiTokenService) Authenticate(rawToken string) (*auth.Principal, error) {
+	token, err := s.repo.GetByHash(hashAPIToken(rawToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, auth.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if token.ExpiresAt != nil && !token.ExpiresAt.After(now) {
+		return nil, auth.ErrInvalidToken
+	}
+
+	if err := s.repo.TouchLastUsed(token.ID, now, lastUsedResolution); err != nil {
+		return nil, err
+	}
+
+	return &auth.Principal{
+		UserID:  token.UserID,
+		TokenID: token.ID,
+		Scopes:  splitScopes(token.Scopes),
+	}, nil
+}
+
+// Tokens têm 256 bits aleatórios, por isso um hash rápido é suficiente
+func hashAPIToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+func splitScopes(scopes string) []string {
+	if scopes == "" {
+		return []string{}
+	}
+	return strings.Split(scopes, ",")
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func apiTokenToDTO(token *entity.APIToken) *dto.APITokenResponse {
+	return &dto.APITokenResponse{
+		ID:         token.ID,
+		Name:       token.Name,
+		Prefix:     token.Prefix,
+		Scopes:     splitScopes(token.Scopes),
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/auth"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrEmailTaken         = apperror.Conflict("email already registered")
+	ErrInvalidCredentials = apperror.Unauthorized("invalid email or password")
+	ErrUserNotFound       = apperror.NotFound("user not found")
+)
+
+type AuthService interface {
+	Register(req *dto.RegisterRequest) (*dto.UserResponse, error)
+	Login(req *dto.LoginRequest) (*dto.TokenResponse, error)
+	Refresh(req *dto.RefreshRequest) (*dto.TokenResponse, error)
+	Me(userID uint) (*dto.UserResponse, error)
+}
+
+type authService struct {
+	repo   repository.UserRepository
+	tokens *auth.TokenManager
+}
+
+func NewAuthService(repo repository.UserRepository, tokens *auth.TokenManager) AuthService {
+	return &authService{repo: repo, tokens: tokens}
+}
+
+func (s *authService) Register(req *dto.RegisterRequest) (*dto.UserResponse, error) {
+	email := normalizeEmail(req.Email)
+
+	_, err := s.repo.GetByEmail(email)
+	if err == nil {
+		return nil, ErrEmailTaken
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &entity.User{
+		Name:         strings.TrimSpace(req.Name),
+		Email:        email,
+		PasswordHash: string(hash),
+	}
+	if err := s.repo.Create(user); err != nil {
+		return nil, err
+	}
+
+	return userToDTO(user), nil
+}
+
+func (s *authService) Login(req *dto.LoginRequest) (*dto.TokenResponse, error) {
+	user, err := s.repo.GetByEmail(normalizeEmail(req.Email))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	return s.issueTokens(user.ID)
+}
+
+func (s *authService) Refresh(req *dto.RefreshRequest) (*dto.TokenResponse, error) {
+	userID, err := s.tokens.Parse(req.RefreshToken, auth.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// O usuário pode ter sido removido depois da emissão do token
+	if _, err := s.repo.GetByID(userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, auth.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	return s.issueTokens(userID)
+}
+
+func (s *authService) Me(userID uint) (*dto.UserResponse, error) {
+	user, err := s.repo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return userToDTO(user), nil
+}
+
+func (s *authService) issueTokens(userID uint) (*dto.TokenResponse, error) {
+	pair, err := s.tokens.IssuePair(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TokenResponse{
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(pair.ExpiresIn.Seconds()),
+	}, nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func userToDTO(user *entity.User) *dto.UserResponse {
+	return &dto.UserResponse{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		CreatedAt: user.CreatedAt,
+	}
+}
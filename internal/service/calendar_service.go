@@ -0,0 +1,102 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+// CalendarTokenPrefix identifica os links secretos do feed iCalendar
+const CalendarTokenPrefix = "cal_"
+
+// ErrCalendarFeedNotFound indica que o usuário não tem feed ativo ou que o
+// link informado não existe
+var ErrCalendarFeedNotFound = apperror.NotFound("calendar feed not found")
+
+type CalendarService interface {
+	Get(userID uint) (*dto.CalendarFeedResponse, error)
+	Rotate(userID uint) (*dto.CreatedCalendarFeedResponse, error)
+	Disable(userID uint) error
+	Authenticate(rawToken string) (uint, error)
+}
+
+type calendarService struct {
+	repo repository.CalendarFeedRepository
+}
+
+func NewCalendarService(repo repository.CalendarFeedRepository) CalendarService {
+	return &calendarService{repo: repo}
+}
+
+func (s *calendarService) Get(userID uint) (*dto.CalendarFeedResponse, error) {
+	feed, err := s.repo.GetByUser(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCalendarFeedNotFound
+		}
+		return nil, err
+	}
+	return calendarFeedToDTO(feed), nil
+}
+
+// Rotate gera um novo link secreto, invalidando o anterior
+func (s *calendarService) Rotate(userID uint) (*dto.CreatedCalendarFeedResponse, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	raw := CalendarTokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	feed := &entity.CalendarFeed{
+		UserID:    userID,
+		Prefix:    raw[:len(CalendarTokenPrefix)+6],
+		TokenHash: hashAPIToken(raw),
+	}
+	if err := s.repo.Replace(feed); err != nil {
+		return nil, err
+	}
+
+	return &dto.CreatedCalendarFeedResponse{CalendarFeedResponse: *calendarFeedToDTO(feed), Token: raw}, nil
+}
+
+func (s *calendarService) Disable(userID uint) error {
+	deleted, err := s.repo.Delete(userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrCalendarFeedNotFound
+	}
+	return nil
+}
+
+// Authenticate resolve o dono do link secreto e registra o acesso
+func (s *calendarService) Authenticate(rawToken string) (uint, error) {
+	feed, err := s.repo.GetByHash(hashAPIToken(rawToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrCalendarFeedNotFound
+		}
+		return 0, err
+	}
+
+	if err := s.repo.TouchLastUsed(feed.ID, time.Now(), lastUsedResolution); err != nil {
+		return 0, err
+	}
+	return feed.UserID, nil
+}
+
+func calendarFeedToDTO(feed *entity.CalendarFeed) *dto.CalendarFeedResponse {
+	return &dto.CalendarFeedResponse{
+		Prefix:     feed.Prefix,
+		LastUsedAt: feed.LastUsedAt,
+		CreatedAt:  feed.CreatedAt,
+	}
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+const defaultProjectColor = "#808080"
+
+var (
+	ErrProjectNotFound = apperror.NotFound("project not found")
+	ErrProjectArchived = apperror.Conflict("project is archived")
+)
+
+type ProjectService interface {
+	Create(userID uint, req *dto.CreateProjectRequest) (*dto.ProjectResponse, error)
+	GetByID(userID, id uint) (*dto.ProjectResponse, error)
+	List(userID uint, includeArchived bool) ([]dto.ProjectResponse, error)
+	Update(userID, id uint, req *dto.UpdateProjectRequest) (*dto.ProjectResponse, error)
+}
+
+type projectService struct {
+	repo repository.ProjectRepository
+}
+
+func NewProjectService(repo repository.ProjectRepository) ProjectService {
+	return &projectService{repo: repo}
+}
+
+func (s *projectService) Create(userID uint, req *dto.CreateProjectRequest) (*dto.ProjectResponse, error) {
+	project := &entity.Project{
+		UserID: userID,
+		Name:   strings.TrimSpace(req.Name),
+		Color:  strings.ToLower(req.Color),
+	}
+
+	if project.Color == "" {
+		project.Color = defaultProjectColor
+	}
+
+	if err := s.repo.Create(project); err != nil {
+		return nil, err
+	}
+
+	return projectToDTO(project), nil
+}
+
+func (s *projectService) GetByID(userID, id uint) (*dto.ProjectResponse, error) {
+	project, err := s.getProject(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return projectToDTO(project), nil
+}
+
+func (s *projectService) List(userID uint, includeArchived bool) ([]dto.ProjectResponse, error) {
+	projects, err := s.repo.List(userID, includeArchived)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.ProjectResponse, len(projects))
+	for i, project := range projects {
+		responses[i] = *projectToDTO(&project)
+	}
+	return responses, nil
+}
+
+func (s *projectService) Update(userID, id uint, req *dto.UpdateProjectRequest) (*dto.ProjectResponse, error) {
+	project, err := s.getProject(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Atualiza somente campos fornecidos
+	if req.Name != nil {
+		project.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Color != nil {
+		project.Color = strings.ToLower(*req.Color)
+	}
+	if req.Archived != nil {
+		project.Archived = *req.Archived
+	}
+
+	if err := s.repo.Update(project); err != nil {
+		return nil, err
+	}
+
+	return projectToDTO(project), nil
+}
+
+func (s *projectService) getProject(userID, id uint) (*entity.Project, error) {
+	project, err := s.repo.GetByID(userID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, err
+	}
+	return project, nil
+}
+
+func projectToDTO(project *entity.Project) *dto.ProjectResponse {
+	return &dto.ProjectResponse{
+		ID:        project.ID,
+		Name:      project.Name,
+		Color:     project.Color,
+		Archived:  project.Archived,
+		CreatedAt: project.CreatedAt,
+		UpdatedAt: project.UpdatedAt,
+	}
+}
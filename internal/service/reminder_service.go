@@ -0,0 +1,121 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrReminderNotFound = apperror.NotFound("reminder not found")
+	ErrInvalidReminder  = apperror.Validation("exactly one of remind_at or minutes_before is required")
+	ErrTodoHasNoDueDate = apperror.Validation("todo has no due date")
+)
+
+type ReminderService interface {
+	Create(userID, todoID uint, req *dto.CreateReminderRequest) (*dto.ReminderResponse, error)
+	List(userID, todoID uint) ([]dto.ReminderResponse, error)
+	Delete(userID, todoID, id uint) error
+}
+
+type reminderService struct {
+	repo     repository.ReminderRepository
+	todoRepo repository.TodoRepository
+}
+
+func NewReminderService(repo repository.ReminderRepository, todoRepo repository.TodoRepository) ReminderService {
+	return &reminderService{repo: repo, todoRepo: todoRepo}
+}
+
+func (s *reminderService) Create(userID, todoID uint, req *dto.CreateReminderRequest) (*dto.ReminderResponse, error) {
+	if (req.RemindAt == nil) == (req.MinutesBefore == nil) {
+		return nil, ErrInvalidReminder
+	}
+
+	todo, err := s.getTodo(userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	reminder := &entity.Reminder{
+		UserID:        userID,
+		TodoID:        todo.ID,
+		RemindAt:      req.RemindAt,
+		MinutesBefore: req.MinutesBefore,
+	}
+
+	if req.RemindAt != nil {
+		reminder.FireAt = *req.RemindAt
+	} else {
+		// Lembretes relativos são recalculados sempre que o prazo muda
+		if todo.DueDate == nil {
+			return nil, ErrTodoHasNoDueDate
+		}
+		reminder.FireAt = todo.DueDate.Add(-time.Duration(*req.MinutesBefore) * time.Minute)
+	}
+
+	if err := s.repo.Create(reminder); err != nil {
+		return nil, err
+	}
+
+	return reminderToDTO(reminder), nil
+}
+
+func (s *reminderService) List(userID, todoID uint) ([]dto.ReminderResponse, error) {
+	if _, err := s.getTodo(userID, todoID); err != nil {
+		return nil, err
+	}
+
+	reminders, err := s.repo.ListByTodo(userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.ReminderResponse, len(reminders))
+	for i, reminder := range reminders {
+		responses[i] = *reminderToDTO(&reminder)
+	}
+	return responses, nil
+}
+
+func (s *reminderService) Delete(userID, todoID, id uint) error {
+	deleted, err := s.repo.Delete(userID, todoID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrReminderNotFound
+	}
+	return nil
+}
+
+func (s *reminderService) getTodo(userID, todoID uint) (*entity.Todo, error) {
+	todo, err := s.todoRepo.GetByID(userID, todoID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTodoNotFound
+		}
+		return nil, err
+	}
+	return todo, nil
+}
+
+func reminderToDTO(reminder *entity.Reminder) *dto.ReminderResponse {
+	return &dto.ReminderResponse{
+		ID:            reminder.ID,
+		TodoID:        reminder.TodoID,
+		RemindAt:      reminder.RemindAt,
+		MinutesBefore: reminder.MinutesBefore,
+		FireAt:        reminder.FireAt,
+		SentAt:        reminder.SentAt,
+		FailedAt:      reminder.FailedAt,
+		Attempts:      reminder.Attempts,
+		LastError:     reminder.LastError,
+		CreatedAt:     reminder.CreatedAt,
+	}
+}
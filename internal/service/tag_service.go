@@ -0,0 +1,68 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/repository"
+)
+
+// ErrInvalidTag indica uma tag vazia depois da normalização
+var ErrInvalidTag = apperror.Validation("invalid tag")
+
+type TagService interface {
+	List(userID uint) ([]dto.TagResponse, error)
+}
+
+type tagService struct {
+	repo repository.TagRepository
+}
+
+func NewTagService(repo repository.TagRepository) TagService {
+	return &tagService{repo: repo}
+}
+
+func (s *tagService) List(userID uint) ([]dto.TagResponse, error) {
+	usage, err := s.repo.ListWithUsage(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.TagResponse, len(usage))
+	for i, tag := range usage {
+		responses[i] = dto.TagResponse{ID: tag.ID, Name: tag.Name, Count: tag.Count}
+	}
+	return responses, nil
+}
+
+// normalizeTags converte os nomes para minúsculas, junta espaços repetidos
+// e remove duplicados, mantendo a ordem informada
+func normalizeTags(names []string) ([]string, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	normalized := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.Join(strings.Fields(name), " "))
+		if name == "" {
+			return nil, ErrInvalidTag
+		}
+		if !seen[name] {
+			seen[name] = true
+			normalized = append(normalized, name)
+		}
+	}
+	return normalized, nil
+}
+
+func tagNames(tags []entity.Tag) []string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return names
+}
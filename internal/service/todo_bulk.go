@@ -0,0 +1,193 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/event"
+	"github.com/vinibsi/todo-api/internal/repository"
+)
+
+// Operações aceitas em lote
+const (
+	BulkCreate   = "create"
+	BulkUpdate   = "update"
+	BulkComplete = "complete"
+	BulkDelete   = "delete"
+	BulkMove     = "move"
+)
+
+// Modos de execução do lote: no atômico qualquer falha desfaz todas as
+// operações; no best effort só a operação que falhou é desfeita
+const (
+	BulkAtomic     = "atomic"
+	BulkBestEffort = "best_effort"
+)
+
+var (
+	// ErrBulkUnavailable indica um serviço configurado sem transações
+	ErrBulkUnavailable = apperror.New(apperror.KindUnavailable, "bulk operations are not available")
+
+	// ErrBulkRolledBack marca operações desfeitas pela falha de outra no modo atômico
+	ErrBulkRolledBack = apperror.New(apperror.KindFailedDependency, "rolled back because another operation failed")
+
+	// ErrBulkNotExecuted marca operações que não chegaram a rodar no modo atômico
+	ErrBulkNotExecuted = apperror.New(apperror.KindFailedDependency, "not executed because another operation failed")
+)
+
+// BulkOperation é uma operação já decodificada. Update também carrega o
+// destino de move (project_id/parent_id). Invalid guarda um erro de validação
+// encontrado antes da execução
+type BulkOperation struct {
+	Op      string
+	ID      uint
+	Create  *dto.CreateTodoRequest
+	Update  *dto.UpdateTodoRequest
+	Invalid error
+}
+
+// BulkItemResult é o resultado de uma operação: Todo no sucesso (nil em
+// delete) ou Err na falha
+type BulkItemResult struct {
+	Todo *dto.TodoResponse
+	Err  error
+}
+
+type BulkResult struct {
+	Committed bool
+	Items     []BulkItemResult
+}
+
+// WithTransactor permite executar operações em lote dentro de uma transação
+func WithTransactor(transactor repository.Transactor) TodoServiceOption {
+	return func(s *todoService) {
+		s.transactor = transactor
+	}
+}
+
+// Bulk executa as operações em ordem em uma única transação. Os eventos só
+// são publicados após o commit e apenas para as operações efetivadas
+func (s *todoService) Bulk(userID uint, mode string, operations []BulkOperation) (*BulkResult, error) {
+	if s.transactor == nil {
+		return nil, ErrBulkUnavailable
+	}
+
+	result := &BulkResult{Items: make([]BulkItemResult, len(operations))}
+	atomic := mode != BulkBestEffort
+
+	// No modo atômico uma operação inválida impede o lote inteiro
+	if atomic {
+		for i, op := range operations {
+			if op.Invalid != nil {
+				return abortBulk(result, operations, i, op.Invalid), nil
+			}
+		}
+	}
+
+	committed := &event.Buffer{}
+	errAborted := errors.New("bulk aborted")
+	failed := -1
+
+	err := s.transactor.Transaction(func(repos repository.Repositories) error {
+		for i, op := range operations {
+			if op.Invalid != nil {
+				result.Items[i].Err = op.Invalid
+				continue
+			}
+
+			// Cada operação roda em um savepoint para que uma falha não deixe
+			// gravações pela metade
+			pending := &event.Buffer{}
+			err := repos.Transaction(func(repos repository.Repositories) error {
+				todo, err := s.inTransaction(repos, pending).applyBulk(userID, op)
+				result.Items[i].Todo = todo
+				return err
+			})
+			if err != nil {
+				result.Items[i] = BulkItemResult{Err: err}
+				if atomic {
+					failed = i
+					return errAborted
+				}
+				continue
+			}
+			pending.Flush(committed)
+		}
+		return nil
+	})
+
+	if failed >= 0 {
+		return abortBulk(result, operations, failed, result.Items[failed].Err), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	committed.Flush(s.publisher)
+	result.Committed = true
+	return result, nil
+}
+
+// abortBulk monta o resultado de um lote atômico desfeito pela operação failed
+func abortBulk(result *BulkResult, operations []BulkOperation, failed int, cause error) *BulkResult {
+	for i := range operations {
+		switch {
+		case i < failed:
+			result.Items[i] = BulkItemResult{Err: ErrBulkRolledBack}
+		case i == failed:
+			result.Items[i] = BulkItemResult{Err: cause}
+		default:
+			result.Items[i] = BulkItemResult{Err: ErrBulkNotExecuted}
+		}
+	}
+	result.Committed = false
+	return result
+}
+
+// inTransaction retorna uma cópia do serviço que usa os repositórios da
+// transação e guarda os eventos em publisher
+func (s *todoService) inTransaction(repos repository.Repositories, publisher event.Publisher) *todoService {
+	tx := *s
+	tx.repo = repos.Todos
+	tx.projectRepo = repos.Projects
+	tx.tagRepo = repos.Tags
+	if s.history != nil {
+		tx.history = repos.History
+	}
+	tx.transactor = repos
+	tx.publisher = publisher
+	return &tx
+}
+
+// atomically executa fn em uma transação, com uma cópia do serviço ligada a
+// ela: a alteração e o histórico são gravados juntos e os eventos só são
+// publicados após o commit. Sem transactor fn recebe o próprio serviço
+func (s *todoService) atomically(fn func(tx *todoService) error) error {
+	if s.transactor == nil {
+		return fn(s)
+	}
+
+	pending := &event.Buffer{}
+	err := s.transactor.Transaction(func(repos repository.Repositories) error {
+		return fn(s.inTransaction(repos, pending))
+	})
+	if err != nil {
+		return err
+	}
+	pending.Flush(s.publisher)
+	return nil
+}
+
+func (s *todoService) applyBulk(userID uint, op BulkOperation) (*dto.TodoResponse, error) {
+	switch op.Op {
+	case BulkCreate:
+		return s.Create(userID, op.Create)
+	case BulkUpdate, BulkMove:
+		return s.Update(userID, op.ID, op.Update, nil)
+	case BulkComplete:
+		return s.Complete(userID, op.ID, nil)
+	default:
+		return nil, s.Delete(userID, op.ID, nil)
+	}
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/event"
+	"github.com/vinibsi/todo-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ErrRevisionNotFound indica uma revisão inexistente no histórico da tarefa
+var ErrRevisionNotFound = apperror.NotFound("todo revision not found")
+
+// todoSnapshot é o estado de uma tarefa guardado em cada revisão do histórico.
+// Datas ficam em UTC para que o diff não acuse mudanças só de fuso
+type todoSnapshot struct {
+	Title       string                  `json:"title"`
+	Description string                  `json:"description"`
+	Priority    string                  `json:"priority"`
+	DueDate     *time.Time              `json:"due_date"`
+	Completed   bool                    `json:"completed"`
+	ProjectID   *uint                   `json:"project_id"`
+	ParentID    *uint                   `json:"parent_id"`
+	Tags        []string                `json:"tags"`
+	Recurrence  *dto.RecurrenceResponse `json:"recurrence"`
+}
+
+// WithHistory registra cada alteração das tarefas no histórico de revisões
+func WithHistory(history repository.TodoHistoryRepository) TodoServiceOption {
+	return func(s *todoService) {
+		s.history = history
+	}
+}
+
+func snapshotOf(todo *entity.Todo) *todoSnapshot {
+	tags := tagNames(todo.Tags)
+	sort.Strings(tags)
+
+	snapshot := &todoSnapshot{
+		Title:       todo.Title,
+		Description: todo.Description,
+		Priority:    todo.Priority,
+		DueDate:     utcTime(todo.DueDate),
+		Completed:   todo.Completed,
+		ProjectID:   copyID(todo.ProjectID),
+		ParentID:    copyID(todo.ParentID),
+		Tags:        tags,
+		Recurrence:  recurrenceToDTO(todo.Recurrence),
+	}
+	if snapshot.Recurrence != nil {
+		snapshot.Recurrence.Start = utcTime(snapshot.Recurrence.Start)
+	}
+	return snapshot
+}
+
+// diffSnapshots compara os campos serializados das duas revisões; before nil
+// (criação) lista todos os campos vindos de null
+func diffSnapshots(before, after *todoSnapshot) (map[string]dto.FieldChange, error) {
+	from, err := snapshotFields(before)
+	if err != nil {
+		return nil, err
+	}
+	to, err := snapshotFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]dto.FieldChange)
+	for field, value := range to {
+		previous, ok := from[field]
+		if !ok {
+			previous = json.RawMessage("null")
+		}
+		if !bytes.Equal(previous, value) {
+			changes[field] = dto.FieldChange{From: previous, To: value}
+		}
+	}
+	return changes, nil
+}
+
+func snapshotFields(snapshot *todoSnapshot) (map[string]json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	if snapshot == nil {
+		return fields, nil
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return fields, json.Unmarshal(data, &fields)
+}
+
+// record grava uma revisão da tarefa. Operações sem nenhuma mudança não geram
+// revisão, exceto a restauração da lixeira. Deve ser chamado dentro da
+// transação da alteração (atomically): uma falha aqui a desfaz
+func (s *todoService) record(userID uint, todo *entity.Todo, operation string, before *todoSnapshot, sourceRevision *int) error {
+	if s.history == nil {
+		return nil
+	}
+	after := snapshotOf(todo)
+
+	changes := map[string]dto.FieldChange{}
+	if operation != entity.HistoryDelete {
+		var err error
+		if changes, err = diffSnapshots(before, after); err != nil {
+			return err
+		}
+		if len(changes) == 0 && operation != entity.HistoryRestore {
+			return nil
+		}
+	}
+
+	snapshot, err := json.Marshal(after)
+	if err != nil {
+		return err
+	}
+	diff, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	return s.history.Record(&entity.TodoHistory{
+		TodoID:         todo.ID,
+		UserID:         userID,
+		ActorID:        userID,
+		Operation:      operation,
+		Changes:        string(diff),
+		Snapshot:       string(snapshot),
+		SourceRevision: sourceRevision,
+	})
+}
+
+// History lista as revisões da tarefa, inclusive depois de removida
+func (s *todoService) History(userID, id uint) ([]dto.TodoHistoryResponse, error) {
+	var entries []entity.TodoHistory
+	if s.history != nil {
+		var err error
+		if entries, err = s.history.List(userID, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(entries) == 0 {
+		// Sem histórico: distingue tarefa inexistente de tarefa anterior ao registro
+		if _, err := s.GetByID(userID, id); err != nil {
+			return nil, err
+		}
+	}
+
+	responses := make([]dto.TodoHistoryResponse, len(entries))
+	for i, entry := range entries {
+		response, err := historyToDTO(&entry)
+		if err != nil {
+			return nil, err
+		}
+		responses[i] = *response
+	}
+	return responses, nil
+}
+
+// Revert restaura o estado da tarefa gravado na revisão informada, gerando uma
+// nova revisão. Projeto e tarefa pai são validados de novo, pois podem ter
+// mudado desde então
+func (s *todoService) Revert(userID, id uint, revision int) (*dto.TodoResponse, error) {
+	todo, err := s.repo.GetByID(userID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTodoNotFound
+		}
+		return nil, err
+	}
+	if s.history == nil {
+		return nil, ErrRevisionNotFound
+	}
+
+	entry, err := s.history.GetRevision(userID, id, revision)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, err
+	}
+
+	var target todoSnapshot
+	if err := json.Unmarshal([]byte(entry.Snapshot), &target); err != nil {
+		return nil, err
+	}
+
+	before := snapshotOf(todo)
+	wasCompleted := todo.Completed
+	if err := s.applySnapshot(userID, todo, &target); err != nil {
+		return nil, err
+	}
+	if todo.Completed && !wasCompleted && todo.SubtasksDone < todo.SubtasksTotal {
+		return nil, ErrOpenSubtasks
+	}
+
+	var response *dto.TodoResponse
+	err = s.atomically(func(tx *todoService) error {
+		if err := tx.repo.Update(todo); err != nil {
+			return versionError(err, nil)
+		}
+		if err := tx.record(userID, todo, entity.HistoryRevert, before, &entry.Revision); err != nil {
+			return err
+		}
+		response = tx.entityToDTO(todo)
+		tx.publish(event.TodoUpdated, userID, response)
+		if todo.Completed && !wasCompleted {
+			tx.publish(event.TodoCompleted, userID, response)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *todoService) applySnapshot(userID uint, todo *entity.Todo, target *todoSnapshot) error {
+	if !sameID(target.ProjectID, todo.ProjectID) {
+		if target.ProjectID != nil {
+			if err := s.checkProject(userID, *target.ProjectID); err != nil {
+				return err
+			}
+		}
+		todo.ProjectID = target.ProjectID
+	}
+	if !sameID(target.ParentID, todo.ParentID) {
+		if target.ParentID != nil {
+			if err := s.checkParent(userID, *target.ParentID, todo); err != nil {
+				return err
+			}
+		}
+		todo.ParentID = target.ParentID
+	}
+
+	tags := []entity.Tag{}
+	if len(target.Tags) > 0 {
+		var err error
+		if tags, err = s.resolveTags(userID, target.Tags); err != nil {
+			return err
+		}
+	}
+
+	todo.Title = target.Title
+	todo.Description = target.Description
+	todo.Priority = target.Priority
+	todo.DueDate = target.DueDate
+	todo.Completed = target.Completed
+	todo.Tags = tags
+
+	todo.Recurrence = entity.Recurrence{}
+	if rec := target.Recurrence; rec != nil {
+		todo.Recurrence = entity.Recurrence{
+			Rule:           rec.Rule,
+			Timezone:       rec.Timezone,
+			Exdates:        strings.Join(rec.Exdates, ","),
+			FromCompletion: rec.FromCompletion,
+			Start:          rec.Start,
+			Completed:      rec.CompletedOccurrences,
+		}
+	}
+	return nil
+}
+
+func historyToDTO(entry *entity.TodoHistory) (*dto.TodoHistoryResponse, error) {
+	changes := map[string]dto.FieldChange{}
+	if entry.Changes != "" {
+		if err := json.Unmarshal([]byte(entry.Changes), &changes); err != nil {
+			return nil, err
+		}
+	}
+
+	return &dto.TodoHistoryResponse{
+		Revision:       entry.Revision,
+		Operation:      entry.Operation,
+		ActorID:        entry.ActorID,
+		Changes:        changes,
+		State:          json.RawMessage(entry.Snapshot),
+		SourceRevision: entry.SourceRevision,
+		CreatedAt:      entry.CreatedAt,
+	}, nil
+}
+
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+func copyID(id *uint) *uint {
+	if id == nil {
+		return nil
+	}
+	value := *id
+	return &value
+}
+
+func sameID(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
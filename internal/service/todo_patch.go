@@ -0,0 +1,127 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+// PatchFunc recebe o documento atual da tarefa e devolve o documento com o
+// patch aplicado e validado
+type PatchFunc func(current *dto.TodoPatchDocument) (*dto.TodoPatchDocument, error)
+
+// Patch aplica apply ao documento da tarefa e grava somente os campos que
+// mudaram, com as mesmas regras do Update. O patch é calculado sobre a
+// versão lida e a gravação é condicional a ela, então uma alteração
+// concorrente resulta em ErrConcurrentUpdate em vez de ser sobrescrita
+func (s *todoService) Patch(userID, id uint, apply PatchFunc, ifMatch *int) (*dto.TodoResponse, error) {
+	todo, err := s.repo.GetByID(userID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTodoNotFound
+		}
+		return nil, err
+	}
+	if err := checkVersion(todo, ifMatch); err != nil {
+		return nil, err
+	}
+
+	current := patchDocumentOf(todo)
+	patched, err := apply(patchDocumentOf(todo))
+	if err != nil {
+		return nil, err
+	}
+	return s.update(userID, todo, patchChanges(current, patched), ifMatch)
+}
+
+func patchDocumentOf(todo *entity.Todo) *dto.TodoPatchDocument {
+	doc := &dto.TodoPatchDocument{
+		Title:       todo.Title,
+		Description: todo.Description,
+		Priority:    todo.Priority,
+		DueDate:     todo.DueDate,
+		Completed:   todo.Completed,
+		ProjectID:   todo.ProjectID,
+		ParentID:    todo.ParentID,
+		Tags:        tagNames(todo.Tags),
+	}
+	if rec := todo.Recurrence; rec.Rule != "" {
+		doc.Recurrence = &dto.RecurrenceRequest{
+			Rule:           rec.Rule,
+			Timezone:       rec.Timezone,
+			Exdates:        splitDates(rec.Exdates),
+			FromCompletion: rec.FromCompletion,
+		}
+	}
+	return doc
+}
+
+// patchChanges converte a diferença entre os documentos em uma atualização
+// parcial. Campos iguais ficam de fora para não disparar efeitos do Update
+// (uma nova data de prazo, por exemplo, reinicia a série da repetição)
+func patchChanges(current, patched *dto.TodoPatchDocument) *dto.UpdateTodoRequest {
+	req := &dto.UpdateTodoRequest{}
+	if patched.Title != current.Title {
+		req.Title = &patched.Title
+	}
+	if patched.Description != current.Description {
+		req.Description = &patched.Description
+	}
+	if patched.Priority != current.Priority {
+		req.Priority = &patched.Priority
+	}
+	if patched.Completed != current.Completed {
+		req.Completed = &patched.Completed
+	}
+
+	switch {
+	case patched.DueDate == nil:
+		req.ClearDueDate = current.DueDate != nil
+	case current.DueDate == nil || !patched.DueDate.Equal(*current.DueDate):
+		req.DueDate = patched.DueDate
+	}
+
+	// Nos ids, 0 e nulo significam sem projeto e tarefa raiz
+	if projectID := valueOrZero(patched.ProjectID); projectID != valueOrZero(current.ProjectID) {
+		req.ProjectID = &projectID
+	}
+	if parentID := valueOrZero(patched.ParentID); parentID != valueOrZero(current.ParentID) {
+		req.ParentID = &parentID
+	}
+
+	if !slices.Equal(patched.Tags, current.Tags) {
+		req.Tags = patched.Tags
+		if req.Tags == nil {
+			req.Tags = []string{}
+		}
+	}
+
+	if !sameRecurrence(patched.Recurrence, current.Recurrence) {
+		req.Recurrence = patched.Recurrence
+		if req.Recurrence == nil {
+			req.Recurrence = &dto.RecurrenceRequest{}
+		}
+	}
+	return req
+}
+
+func valueOrZero(id *uint) uint {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
+
+func sameRecurrence(a, b *dto.RecurrenceRequest) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if len(a.Exdates) == 0 && len(b.Exdates) == 0 {
+		return a.Rule == b.Rule && a.Timezone == b.Timezone && a.FromCompletion == b.FromCompletion
+	}
+	return reflect.DeepEqual(a, b)
+}
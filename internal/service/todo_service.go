@@ -2,33 +2,132 @@ package service
 
 import (
 	"errors"
+	"fmt"
 	"math"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/vinibsi/todo-api/internal/apperror"
 	"github.com/vinibsi/todo-api/internal/dto"
 	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/event"
+	"github.com/vinibsi/todo-api/internal/recurrence"
 	"github.com/vinibsi/todo-api/internal/repository"
 	"gorm.io/gorm"
 )
 
 type TodoService interface {
-	Create(req *dto.CreateTodoRequest) (*dto.TodoResponse, error)
-	GetByID(id uint) (*dto.TodoResponse, error)
-	GetAll(page, pageSize int) (*dto.TodoListResponse, error)
-	Update(id uint, req *dto.UpdateTodoRequest) (*dto.TodoResponse, error)
-	Delete(id uint) error
-	Complete(id uint) (*dto.TodoResponse, error)
+	Create(userID uint, req *dto.CreateTodoRequest) (*dto.TodoResponse, error)
+	GetByID(userID, id uint) (*dto.TodoResponse, error)
+	GetAll(userID uint, query *dto.TodoListQuery) (*dto.TodoListResponse, error)
+	Update(userID, id uint, req *dto.UpdateTodoRequest, ifMatch *int) (*dto.TodoResponse, error)
+	Patch(userID, id uint, apply PatchFunc, ifMatch *int) (*dto.TodoResponse, error)
+	Delete(userID, id uint, ifMatch *int) error
+	DeleteProject(userID, projectID uint, cascade bool) error
+	Complete(userID, id uint, ifMatch *int) (*dto.TodoResponse, error)
+	Tree(userID, id uint) (*dto.TodoTreeResponse, error)
+	History(userID, id uint) ([]dto.TodoHistoryResponse, error)
+	Revert(userID, id uint, revision int) (*dto.TodoResponse, error)
+	Trash(userID uint, query *dto.TrashQuery) (*dto.TrashListResponse, error)
+	Restore(userID, id uint) (*dto.TodoResponse, error)
+	Purge(userID, id uint, ifMatch *int) error
+	Bulk(userID uint, mode string, operations []BulkOperation) (*BulkResult, error)
+	Export(userID uint, query *dto.TodoListQuery, fn func(*dto.TodoResponse) error) error
+	Import(userID uint, rows []ImportRow, opts ImportOptions) ([]ImportRowResult, error)
 }
 
+var (
+	ErrTodoNotFound = apperror.NotFound("todo not found")
+
+	// ErrInvalidQuery indica parâmetros de listagem inválidos (ex.: ordenação)
+	ErrInvalidQuery = apperror.Validation("invalid query")
+)
+
+// Erros da hierarquia de subtarefas
+var (
+	ErrParentNotFound   = apperror.Validation("parent todo not found")
+	ErrInvalidParent    = apperror.Validation("todo cannot be placed under itself or one of its subtasks")
+	ErrMaxDepthExceeded = apperror.Unprocessable("maximum subtask depth exceeded")
+	ErrOpenSubtasks     = apperror.Conflict("todo has open subtasks")
+)
+
+// Erros da concorrência otimista. ifMatch nos métodos do serviço é a versão
+// esperada da tarefa (If-Match); nil aceita qualquer versão
+var (
+	ErrVersionMismatch  = apperror.PreconditionFailed("todo version does not match If-Match")
+	ErrConcurrentUpdate = apperror.Conflict("todo was modified by another request")
+)
+
+// ErrInvalidRecurrence indica uma regra de repetição, fuso ou exceção inválidos
+var ErrInvalidRecurrence = apperror.Validation("invalid recurrence")
+
+// DefaultMaxTodoDepth é o número padrão de níveis da hierarquia (a tarefa
+// raiz conta como o primeiro nível)
+const DefaultMaxTodoDepth = 3
+
 type todoService struct {
-	repo repository.TodoRepository
+	repo        repository.TodoRepository
+	projectRepo repository.ProjectRepository
+	tagRepo     repository.TagRepository
+
+	maxDepth           int
+	autoCompleteParent bool
+	now                func() time.Time
+	publisher          event.Publisher
+	history            repository.TodoHistoryRepository
+	trashRetention     time.Duration
+	transactor         repository.Transactor
+}
+
+// TodoServiceOption ajusta comportamentos opcionais do serviço
+type TodoServiceOption func(*todoService)
+
+// WithMaxDepth limita quantos níveis de subtarefas são permitidos
+func WithMaxDepth(depth int) TodoServiceOption {
+	return func(s *todoService) {
+		if depth > 0 {
+			s.maxDepth = depth
+		}
+	}
 }
 
-func NewTodoService(repo repository.TodoRepository) TodoService {
-	return &todoService{repo: repo}
+// WithAutoCompleteParent conclui a tarefa pai quando a última subtarefa
+// aberta é concluída
+func WithAutoCompleteParent(enabled bool) TodoServiceOption {
+	return func(s *todoService) {
+		s.autoCompleteParent = enabled
+	}
 }
 
-func (s *todoService) Create(req *dto.CreateTodoRequest) (*dto.TodoResponse, error) {
+// WithClock substitui o relógio usado nas repetições (útil em testes)
+func WithClock(now func() time.Time) TodoServiceOption {
+	return func(s *todoService) {
+		s.now = now
+	}
+}
+
+// WithPublisher publica os eventos de criação, alteração, conclusão e remoção
+// das tarefas (webhooks, streams)
+func WithPublisher(publisher event.Publisher) TodoServiceOption {
+	return func(s *todoService) {
+		if publisher != nil {
+			s.publisher = publisher
+		}
+	}
+}
+
+func NewTodoService(repo repository.TodoRepository, projectRepo repository.ProjectRepository, tagRepo repository.TagRepository, opts ...TodoServiceOption) TodoService {
+	s := &todoService{repo: repo, projectRepo: projectRepo, tagRepo: tagRepo, maxDepth: DefaultMaxTodoDepth, now: time.Now, publisher: event.Discard}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *todoService) Create(userID uint, req *dto.CreateTodoRequest) (*dto.TodoResponse, error) {
 	todo := &entity.Todo{
+		UserID:      userID,
 		Title:       req.Title,
 		Description: req.Description,
 		Priority:    req.Priority,
@@ -40,42 +139,111 @@ func (s *todoService) Create(req *dto.CreateTodoRequest) (*dto.TodoResponse, err
 		todo.Priority = "medium"
 	}
 
-	if err := s.repo.Create(todo); err != nil {
-		return nil, err
+	if req.ProjectID != nil {
+		if err := s.checkProject(userID, *req.ProjectID); err != nil {
+			return nil, err
+		}
+		todo.ProjectID = req.ProjectID
 	}
 
-	return s.entityToDTO(todo), nil
+	if req.ParentID != nil {
+		if err := s.checkParent(userID, *req.ParentID, nil); err != nil {
+			return nil, err
+		}
+		todo.ParentID = req.ParentID
+	}
+
+	if len(req.Tags) > 0 {
+		tags, err := s.resolveTags(userID, req.Tags)
+		if err != nil {
+			return nil, err
+		}
+		todo.Tags = tags
+	}
+
+	if req.Recurrence != nil {
+		if err := s.setRecurrence(todo, req.Recurrence); err != nil {
+			return nil, err
+		}
+	}
+
+	var response *dto.TodoResponse
+	err := s.atomically(func(tx *todoService) error {
+		if err := tx.repo.Create(todo); err != nil {
+			return err
+		}
+		if err := tx.record(userID, todo, entity.HistoryCreate, nil, nil); err != nil {
+			return err
+		}
+		response = tx.entityToDTO(todo)
+		tx.publish(event.TodoCreated, userID, response)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
 }
 
-func (s *todoService) GetByID(id uint) (*dto.TodoResponse, error) {
-	todo, err := s.repo.GetByID(id)
+func (s *todoService) GetByID(userID, id uint) (*dto.TodoResponse, error) {
+	todo, err := s.repo.GetByID(userID, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("todo not found")
+			return nil, ErrTodoNotFound
 		}
 		return nil, err
 	}
 	return s.entityToDTO(todo), nil
 }
 
-func (s *todoService) Complete(id uint) (*dto.TodoResponse, error) {
-	todo, err := s.repo.GetByID(id)
+func (s *todoService) Complete(userID, id uint, ifMatch *int) (*dto.TodoResponse, error) {
+	todo, err := s.repo.GetByID(userID, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("todo not found")
+			return nil, ErrTodoNotFound
 		}
 		return nil, err
 	}
+	if err := checkVersion(todo, ifMatch); err != nil {
+		return nil, err
+	}
+
+	if todo.Completed {
+		return s.entityToDTO(todo), nil
+	}
+	if todo.SubtasksDone < todo.SubtasksTotal {
+		return nil, ErrOpenSubtasks
+	}
 
+	before := snapshotOf(todo)
 	todo.Completed = true
-	if err := s.repo.Update(todo); err != nil {
+	s.rollForward(todo)
+
+	var response *dto.TodoResponse
+	err = s.atomically(func(tx *todoService) error {
+		if err := tx.repo.Update(todo); err != nil {
+			return versionError(err, ifMatch)
+		}
+		if err := tx.record(userID, todo, entity.HistoryComplete, before, nil); err != nil {
+			return err
+		}
+		response = tx.entityToDTO(todo)
+		// uma tarefa recorrente reaberta para a próxima ocorrência não foi concluída
+		if !todo.Completed {
+			tx.publish(event.TodoUpdated, userID, response)
+			return nil
+		}
+		tx.publish(event.TodoCompleted, userID, response)
+		return tx.completeParents(userID, todo)
+	})
+	if err != nil {
 		return nil, err
 	}
-
-	return s.entityToDTO(todo), nil
+	return response, nil
 }
 
-func (s *todoService) GetAll(page, pageSize int) (*dto.TodoListResponse, error) {
+func (s *todoService) GetAll(userID uint, query *dto.TodoListQuery) (*dto.TodoListResponse, error) {
+	page, pageSize := query.Page, query.Size
 	if page < 1 {
 		page = 1
 	}
@@ -83,36 +251,158 @@ func (s *todoService) GetAll(page, pageSize int) (*dto.TodoListResponse, error)
 		pageSize = 10
 	}
 
-	offset := (page - 1) * pageSize
-	todos, total, err := s.repo.GetAll(pageSize, offset)
+	repoQuery, err := listFilters(userID, query)
 	if err != nil {
 		return nil, err
 	}
+	repoQuery.SkipCount = query.SkipTotal
+	repoQuery.Limit = pageSize
 
-	todoResponses := make([]dto.TodoResponse, len(todos))
-	for i, todo := range todos {
-		todoResponses[i] = *s.entityToDTO(&todo)
+	if query.Q != "" {
+		if query.Cursor != "" || query.Pagination == "cursor" {
+			return nil, fmt.Errorf("%w: cursor pagination is not supported with q", ErrInvalidQuery)
+		}
+		repoQuery.Offset = (page - 1) * pageSize
+		return s.search(query.Q, page, repoQuery)
+	}
+
+	if query.Cursor != "" || query.Pagination == "cursor" {
+		return s.getAllByCursor(query.Cursor, repoQuery)
+	}
+
+	repoQuery.Offset = (page - 1) * pageSize
+	todos, total, err := s.repo.GetAll(repoQuery)
+	if err != nil {
+		return nil, err
 	}
 
-	totalPages := int(math.Ceil(float64(total) / float64(pageSize)))
+	response := &dto.TodoListResponse{
+		Data:     s.entitiesToDTO(todos),
+		Page:     page,
+		PageSize: pageSize,
+		HasMore:  len(todos) == pageSize,
+	}
 
-	return &dto.TodoListResponse{
-		Data:       todoResponses,
-		Total:      total,
-		Page:       page,
-		PageSize:   pageSize,
-		TotalPages: totalPages,
+	if !query.SkipTotal {
+		response.Total = &total
+		response.TotalPages = int(math.Ceil(float64(total) / float64(pageSize)))
+		response.HasMore = page < response.TotalPages
+	}
+
+	return response, nil
+}
+
+// listFilters converte os filtros e a ordenação da listagem para o repositório
+func listFilters(userID uint, query *dto.TodoListQuery) (repository.TodoQuery, error) {
+	sortFields, err := repository.ParseTodoSort(query.Sort)
+	if err != nil {
+		return repository.TodoQuery{}, fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+	}
+
+	tags, err := normalizeTags(query.Tags)
+	if err != nil {
+		return repository.TodoQuery{}, fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+	}
+
+	return repository.TodoQuery{
+		UserID:    userID,
+		Completed: query.Completed,
+		Priority:  query.Priority,
+		DueBefore: query.DueBefore,
+		DueAfter:  query.DueAfter,
+		HasDue:    query.HasDue,
+		Overdue:   query.Overdue,
+		ProjectID: query.ProjectID,
+		Inbox:     query.Inbox,
+		ParentID:  query.ParentID,
+		Tags:      tags,
+		TagMatch:  query.TagMatch,
+		Sort:      sortFields,
 	}, nil
 }
 
-func (s *todoService) Update(id uint, req *dto.UpdateTodoRequest) (*dto.TodoResponse, error) {
-	todo, err := s.repo.GetByID(id)
+// getAllByCursor busca um item a mais que o tamanho da página para saber se
+// existe uma próxima página sem depender da contagem total
+func (s *todoService) getAllByCursor(rawCursor string, repoQuery repository.TodoQuery) (*dto.TodoListResponse, error) {
+	if rawCursor != "" {
+		cursor, err := repository.DecodeTodoCursor(rawCursor, repoQuery.Sort)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+		}
+		repoQuery.After = cursor
+	}
+
+	pageSize := repoQuery.Limit
+	repoQuery.Limit = pageSize + 1
+
+	todos, total, err := s.repo.GetAll(repoQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.TodoListResponse{PageSize: pageSize}
+	if len(todos) > pageSize {
+		todos = todos[:pageSize]
+		response.HasMore = true
+		response.NextCursor = repository.EncodeTodoCursor(repoQuery.Sort, &todos[len(todos)-1])
+	}
+	response.Data = s.entitiesToDTO(todos)
+
+	if !repoQuery.SkipCount {
+		response.Total = &total
+	}
+
+	return response, nil
+}
+
+// search executa a busca textual, sempre ordenada por relevância
+func (s *todoService) search(term string, page int, repoQuery repository.TodoQuery) (*dto.TodoListResponse, error) {
+	results, total, err := s.repo.Search(term, repoQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	todoResponses := make([]dto.TodoResponse, len(results))
+	for i, result := range results {
+		todoResponses[i] = *s.entityToDTO(&result.Todo)
+		todoResponses[i].Search = &dto.SearchHit{Rank: result.Rank, Snippet: result.Snippet}
+	}
+
+	response := &dto.TodoListResponse{
+		Data:     todoResponses,
+		Page:     page,
+		PageSize: repoQuery.Limit,
+		HasMore:  len(results) == repoQuery.Limit,
+	}
+
+	if !repoQuery.SkipCount {
+		response.Total = &total
+		response.TotalPages = int(math.Ceil(float64(total) / float64(repoQuery.Limit)))
+		response.HasMore = page < response.TotalPages
+	}
+
+	return response, nil
+}
+
+func (s *todoService) Update(userID, id uint, req *dto.UpdateTodoRequest, ifMatch *int) (*dto.TodoResponse, error) {
+	todo, err := s.repo.GetByID(userID, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("todo not found")
+			return nil, ErrTodoNotFound
 		}
 		return nil, err
 	}
+	if err := checkVersion(todo, ifMatch); err != nil {
+		return nil, err
+	}
+
+	return s.update(userID, todo, req, ifMatch)
+}
+
+// update aplica req à tarefa já lida; a gravação é condicional à versão lida
+func (s *todoService) update(userID uint, todo *entity.Todo, req *dto.UpdateTodoRequest, ifMatch *int) (*dto.TodoResponse, error) {
+	before := snapshotOf(todo)
+	wasCompleted := todo.Completed
 
 	// Atualiza somente campos fornecidos
 	if req.Title != nil {
@@ -124,42 +414,488 @@ func (s *todoService) Update(id uint, req *dto.UpdateTodoRequest) (*dto.TodoResp
 	if req.Priority != nil {
 		todo.Priority = *req.Priority
 	}
+	if req.ClearDueDate {
+		todo.DueDate = nil
+	}
 	if req.DueDate != nil {
 		todo.DueDate = req.DueDate
+		// Uma nova data reinicia a série a partir dela
+		if todo.Recurrence.Rule != "" {
+			todo.Recurrence.Start = req.DueDate
+		}
 	}
 	if req.Completed != nil {
 		todo.Completed = *req.Completed
 	}
+	if req.ProjectID != nil {
+		if *req.ProjectID == 0 {
+			todo.ProjectID = nil
+		} else {
+			if err := s.checkProject(userID, *req.ProjectID); err != nil {
+				return nil, err
+			}
+			todo.ProjectID = req.ProjectID
+		}
+	}
+	if req.ParentID != nil {
+		if *req.ParentID == 0 {
+			todo.ParentID = nil
+		} else {
+			if err := s.checkParent(userID, *req.ParentID, todo); err != nil {
+				return nil, err
+			}
+			todo.ParentID = req.ParentID
+		}
+	}
+	if req.Tags != nil {
+		tags, err := s.resolveTags(userID, req.Tags)
+		if err != nil {
+			return nil, err
+		}
+		todo.Tags = tags
+	}
 
-	if err := s.repo.Update(todo); err != nil {
+	if req.Recurrence != nil {
+		if err := s.setRecurrence(todo, req.Recurrence); err != nil {
+			return nil, err
+		}
+	}
+
+	completing := todo.Completed && !wasCompleted
+	if completing {
+		if todo.SubtasksDone < todo.SubtasksTotal {
+			return nil, ErrOpenSubtasks
+		}
+		s.rollForward(todo)
+	}
+
+	var response *dto.TodoResponse
+	err := s.atomically(func(tx *todoService) error {
+		if err := tx.repo.Update(todo); err != nil {
+			return versionError(err, ifMatch)
+		}
+		if err := tx.record(userID, todo, entity.HistoryUpdate, before, nil); err != nil {
+			return err
+		}
+		response = tx.entityToDTO(todo)
+		tx.publish(event.TodoUpdated, userID, response)
+		if completing && todo.Completed {
+			tx.publish(event.TodoCompleted, userID, response)
+			return tx.completeParents(userID, todo)
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
+	return response, nil
+}
 
-	return s.entityToDTO(todo), nil
+// Tree retorna a tarefa com todas as suas subtarefas aninhadas
+func (s *todoService) Tree(userID, id uint) (*dto.TodoTreeResponse, error) {
+	root, err := s.repo.GetByID(userID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTodoNotFound
+		}
+		return nil, err
+	}
+
+	descendants, err := s.repo.Descendants(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[uint][]*entity.Todo)
+	for i := range descendants {
+		todo := &descendants[i]
+		children[*todo.ParentID] = append(children[*todo.ParentID], todo)
+	}
+
+	var build func(todo *entity.Todo) dto.TodoTreeResponse
+	build = func(todo *entity.Todo) dto.TodoTreeResponse {
+		node := dto.TodoTreeResponse{TodoResponse: *s.entityToDTO(todo), Children: []dto.TodoTreeResponse{}}
+		for _, child := range children[todo.ID] {
+			node.Children = append(node.Children, build(child))
+		}
+		return node
+	}
+
+	tree := build(root)
+	return &tree, nil
 }
 
-func (s *todoService) Delete(id uint) error {
+func (s *todoService) Delete(userID, id uint, ifMatch *int) error {
 	// Verifica se a tarefa existe
-	_, err := s.repo.GetByID(id)
+	todo, err := s.repo.GetByID(userID, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("todo not found")
+			return ErrTodoNotFound
 		}
 		return err
 	}
+	if err := checkVersion(todo, ifMatch); err != nil {
+		return err
+	}
+
+	// As subtarefas são removidas junto e também geram eventos
+	removed := []entity.Todo{*todo}
+	if todo.SubtasksTotal > 0 {
+		descendants, err := s.repo.Descendants(userID, id)
+		if err != nil {
+			return err
+		}
+		removed = append(removed, descendants...)
+	}
+
+	return s.atomically(func(tx *todoService) error {
+		if err := tx.repo.Delete(userID, id, todo.Version); err != nil {
+			return versionError(err, ifMatch)
+		}
+		for i := range removed {
+			if err := tx.record(userID, &removed[i], entity.HistoryDelete, nil, nil); err != nil {
+				return err
+			}
+			tx.publish(event.TodoDeleted, userID, tx.entityToDTO(&removed[i]))
+		}
+		return nil
+	})
+}
+
+// checkProject garante que o projeto existe, pertence ao usuário e aceita tarefas
+// projectTodosBatchSize é o número de tarefas do projeto lidas por vez
+const projectTodosBatchSize = 500
+
+// DeleteProject remove o projeto e, na mesma transação, remove as tarefas
+// dele (cascade) ou as move para a caixa de entrada. Cada tarefa ativa
+// afetada ganha uma revisão no histórico e um evento, como em Delete e Update
+func (s *todoService) DeleteProject(userID, projectID uint, cascade bool) error {
+	err := s.atomically(func(tx *todoService) error {
+		var affected []entity.Todo
+		query := repository.TodoQuery{UserID: userID, ProjectID: &projectID, SkipCount: true}
+		err := tx.repo.Each(query, projectTodosBatchSize, func(todos []entity.Todo) error {
+			affected = append(affected, todos...)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := tx.projectRepo.Delete(userID, projectID, cascade); err != nil {
+			return err
+		}
+
+		for i := range affected {
+			todo := &affected[i]
+			if cascade {
+				if err := tx.record(userID, todo, entity.HistoryDelete, nil, nil); err != nil {
+					return err
+				}
+				tx.publish(event.TodoDeleted, userID, tx.entityToDTO(todo))
+				continue
+			}
+
+			// Relê a tarefa para devolver a versão incrementada pelo repositório
+			before := snapshotOf(todo)
+			moved, err := tx.repo.GetByID(userID, todo.ID)
+			if err != nil {
+				return err
+			}
+			if err := tx.record(userID, moved, entity.HistoryUpdate, before, nil); err != nil {
+				return err
+			}
+			tx.publish(event.TodoUpdated, userID, tx.entityToDTO(moved))
+		}
+		return nil
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrProjectNotFound
+	}
+	return err
+}
+
+func (s *todoService) checkProject(userID, projectID uint) error {
+	project, err := s.projectRepo.GetByID(userID, projectID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Aqui o projeto é um dado da tarefa, então a falha é de validação
+			return apperror.Wrap(apperror.KindValidation, ErrProjectNotFound)
+		}
+		return err
+	}
+	if project.Archived {
+		return ErrProjectArchived
+	}
+	return nil
+}
+
+// checkParent valida a nova tarefa pai: ela deve existir, não pode ser a
+// própria tarefa nem uma de suas subtarefas e a hierarquia resultante deve
+// respeitar a profundidade máxima. todo é nil na criação
+func (s *todoService) checkParent(userID, parentID uint, todo *entity.Todo) error {
+	parent, err := s.repo.GetByID(userID, parentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrParentNotFound
+		}
+		return err
+	}
+
+	// Altura da subárvore que será movida (1 para uma tarefa sem filhas)
+	height := 1
+	if todo != nil {
+		if parentID == todo.ID {
+			return ErrInvalidParent
+		}
+
+		descendants, err := s.repo.Descendants(userID, todo.ID)
+		if err != nil {
+			return err
+		}
+
+		children := make(map[uint][]uint)
+		for _, d := range descendants {
+			if d.ID == parentID {
+				return ErrInvalidParent
+			}
+			children[*d.ParentID] = append(children[*d.ParentID], d.ID)
+		}
+
+		// Desce nível a nível até a subtarefa mais profunda
+		for level := children[todo.ID]; len(level) > 0; height++ {
+			var next []uint
+			for _, id := range level {
+				next = append(next, children[id]...)
+			}
+			level = next
+		}
+	}
+
+	depth, err := s.depthOf(userID, parent)
+	if err != nil {
+		return err
+	}
+	if depth+height > s.maxDepth {
+		return ErrMaxDepthExceeded
+	}
+	return nil
+}
+
+// depthOf conta os níveis da raiz até a tarefa (a raiz tem profundidade 1)
+func (s *todoService) depthOf(userID uint, todo *entity.Todo) (int, error) {
+	depth := 1
+	for todo.ParentID != nil && depth <= s.maxDepth {
+		parent, err := s.repo.GetByID(userID, *todo.ParentID)
+		if err != nil {
+			return 0, err
+		}
+		todo = parent
+		depth++
+	}
+	return depth, nil
+}
+
+// completeParents conclui os ancestrais cujas subtarefas foram todas
+// concluídas, quando a opção está habilitada
+func (s *todoService) completeParents(userID uint, todo *entity.Todo) error {
+	if !s.autoCompleteParent {
+		return nil
+	}
 
-	return s.repo.Delete(id)
+	for todo.ParentID != nil {
+		parent, err := s.repo.GetByID(userID, *todo.ParentID)
+		if err != nil {
+			return err
+		}
+		if parent.Completed || parent.SubtasksDone < parent.SubtasksTotal {
+			return nil
+		}
+
+		before := snapshotOf(parent)
+		parent.Completed = true
+		if err := s.repo.Update(parent); err != nil {
+			return versionError(err, nil)
+		}
+		if err := s.record(userID, parent, entity.HistoryComplete, before, nil); err != nil {
+			return err
+		}
+		s.publish(event.TodoCompleted, userID, s.entityToDTO(parent))
+		todo = parent
+	}
+	return nil
+}
+
+// setRecurrence valida e grava a regra de repetição. Uma regra vazia remove a
+// repetição; uma regra diferente da atual reinicia a série a partir do prazo
+func (s *todoService) setRecurrence(todo *entity.Todo, req *dto.RecurrenceRequest) error {
+	if strings.TrimSpace(req.Rule) == "" {
+		todo.Recurrence = entity.Recurrence{}
+		return nil
+	}
+
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	schedule, err := recurrence.NewSchedule(req.Rule, timezone, req.Exdates, req.FromCompletion)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+	}
+
+	exdates := make([]string, 0, len(schedule.Exdates))
+	for date := range schedule.Exdates {
+		exdates = append(exdates, date)
+	}
+	sort.Strings(exdates)
+
+	rule := schedule.Rule.String()
+	if rule != todo.Recurrence.Rule || todo.Recurrence.Start == nil {
+		start := s.now()
+		if todo.DueDate != nil {
+			start = *todo.DueDate
+		}
+		todo.Recurrence.Start = &start
+		todo.Recurrence.Completed = 0
+	}
+
+	todo.Recurrence.Rule = rule
+	todo.Recurrence.Timezone = timezone
+	todo.Recurrence.Exdates = strings.Join(exdates, ",")
+	todo.Recurrence.FromCompletion = req.FromCompletion
+	return nil
+}
+
+// rollForward é chamado quando uma tarefa recorrente é concluída: em vez de
+// ficar concluída, ela é reaberta com o prazo na próxima ocorrência. A tarefa
+// só permanece concluída quando a série termina (COUNT, UNTIL ou sem datas)
+func (s *todoService) rollForward(todo *entity.Todo) {
+	rec := &todo.Recurrence
+	if rec.Rule == "" {
+		return
+	}
+
+	schedule, err := recurrence.NewSchedule(rec.Rule, rec.Timezone, splitDates(rec.Exdates), rec.FromCompletion)
+	if err != nil {
+		return
+	}
+
+	rec.Completed++
+	if schedule.Rule.Count > 0 && rec.Completed >= schedule.Rule.Count {
+		return
+	}
+
+	// Ocorrências que já passaram são puladas
+	now := s.now()
+	after := now
+	if todo.DueDate != nil && todo.DueDate.After(now) && !rec.FromCompletion {
+		after = *todo.DueDate
+	}
+	start := after
+	if rec.Start != nil {
+		start = *rec.Start
+	}
+
+	next, ok := schedule.Next(start, after)
+	if !ok {
+		return
+	}
+
+	todo.DueDate = &next
+	todo.Completed = false
+}
+
+// resolveTags normaliza os nomes e obtém as tags do usuário, criando as novas
+func (s *todoService) resolveTags(userID uint, names []string) ([]entity.Tag, error) {
+	normalized, err := normalizeTags(names)
+	if err != nil {
+		return nil, err
+	}
+	return s.tagRepo.FindOrCreate(userID, normalized)
+}
+
+// publish notifica os interessados depois que a mudança foi gravada
+func (s *todoService) publish(eventType string, userID uint, todo *dto.TodoResponse) {
+	s.publisher.Publish(event.Event{
+		Type:       eventType,
+		UserID:     userID,
+		TodoID:     todo.ID,
+		Todo:       todo,
+		OccurredAt: s.now().UTC(),
+	})
 }
 
 func (s *todoService) entityToDTO(todo *entity.Todo) *dto.TodoResponse {
 	return &dto.TodoResponse{
 		ID:          todo.ID,
+		ProjectID:   todo.ProjectID,
+		ParentID:    todo.ParentID,
 		Title:       todo.Title,
 		Description: todo.Description,
 		Completed:   todo.Completed,
 		Priority:    todo.Priority,
 		DueDate:     todo.DueDate,
+		Tags:        tagNames(todo.Tags),
+		Subtasks:    subtaskProgress(todo),
+		Recurrence:  recurrenceToDTO(todo.Recurrence),
+		Version:     todo.Version,
 		CreatedAt:   todo.CreatedAt,
 		UpdatedAt:   todo.UpdatedAt,
 	}
 }
+
+func recurrenceToDTO(rec entity.Recurrence) *dto.RecurrenceResponse {
+	if rec.Rule == "" {
+		return nil
+	}
+	return &dto.RecurrenceResponse{
+		Rule:                 rec.Rule,
+		Timezone:             rec.Timezone,
+		Exdates:              splitDates(rec.Exdates),
+		FromCompletion:       rec.FromCompletion,
+		Start:                rec.Start,
+		CompletedOccurrences: rec.Completed,
+	}
+}
+
+func splitDates(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+	return strings.Split(value, ",")
+}
+
+func subtaskProgress(todo *entity.Todo) *dto.SubtaskProgress {
+	if todo.SubtasksTotal == 0 {
+		return nil
+	}
+	return &dto.SubtaskProgress{Total: todo.SubtasksTotal, Done: todo.SubtasksDone}
+}
+
+func (s *todoService) entitiesToDTO(todos []entity.Todo) []dto.TodoResponse {
+	todoResponses := make([]dto.TodoResponse, len(todos))
+	for i, todo := range todos {
+		todoResponses[i] = *s.entityToDTO(&todo)
+	}
+	return todoResponses
+}
+
+// checkVersion compara a versão lida com a esperada pelo cliente
+func checkVersion(todo *entity.Todo, ifMatch *int) error {
+	if ifMatch != nil && *ifMatch != todo.Version {
+		return ErrVersionMismatch
+	}
+	return nil
+}
+
+// versionError traduz a falha da gravação condicional: com If-Match é uma
+// pré-condição que deixou de valer, sem ele outra requisição gravou antes
+func versionError(err error, ifMatch *int) error {
+	if !errors.Is(err, repository.ErrStaleVersion) {
+		return err
+	}
+	if ifMatch != nil {
+		return ErrVersionMismatch
+	}
+	return ErrConcurrentUpdate
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/event"
+	"github.com/vinibsi/todo-api/internal/repository"
+)
+
+// exportBatchSize é o número de tarefas lidas do banco por vez na exportação
+const exportBatchSize = 500
+
+// ImportRow é uma linha do arquivo de importação já decodificada. Blank marca
+// linhas vazias e Invalid um erro de leitura ou validação da linha
+type ImportRow struct {
+	Line    int
+	Request *dto.CreateTodoRequest
+	Blank   bool
+	Invalid error
+}
+
+// ImportOptions: DryRun valida e simula a importação sem gravar nada,
+// SkipDuplicates pula linhas cujo título já existe no mesmo projeto e
+// DropReferences ignora project_id e parent_id, que são ids do ambiente de
+// origem e apontariam para outros registros em um banco diferente
+type ImportOptions struct {
+	DryRun         bool
+	SkipDuplicates bool
+	DropReferences bool
+}
+
+// Motivos de linhas puladas na importação
+const (
+	ImportSkippedBlank     = "blank row"
+	ImportSkippedDuplicate = "duplicate title"
+)
+
+// ImportRowResult é o resultado de uma linha: Todo quando criada, Skipped com
+// o motivo quando pulada ou Err na falha
+type ImportRowResult struct {
+	Line    int
+	Todo    *dto.TodoResponse
+	Skipped string
+	Err     error
+}
+
+// Export percorre todas as tarefas que atendem aos filtros, ordenadas por id,
+// entregando uma a uma para fn. Paginação e ordenação são ignoradas e a
+// busca textual não é suportada
+func (s *todoService) Export(userID uint, query *dto.TodoListQuery, fn func(*dto.TodoResponse) error) error {
+	if query.Q != "" {
+		return fmt.Errorf("%w: q is not supported in export", ErrInvalidQuery)
+	}
+
+	repoQuery, err := listFilters(userID, query)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Each(repoQuery, exportBatchSize, func(todos []entity.Todo) error {
+		for i := range todos {
+			if err := fn(s.entityToDTO(&todos[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Import cria as tarefas das linhas em uma única transação. Cada linha roda
+// em um savepoint: linhas com erro são desfeitas e reportadas sem impedir as
+// demais. No dry run a transação inteira é desfeita ao final
+func (s *todoService) Import(userID uint, rows []ImportRow, opts ImportOptions) ([]ImportRowResult, error) {
+	if s.transactor == nil {
+		return nil, ErrBulkUnavailable
+	}
+
+	results := make([]ImportRowResult, len(rows))
+	committed := &event.Buffer{}
+	errDryRun := errors.New("dry run")
+
+	err := s.transactor.Transaction(func(repos repository.Repositories) error {
+		for i, row := range rows {
+			results[i].Line = row.Line
+			switch {
+			case row.Blank:
+				results[i].Skipped = ImportSkippedBlank
+				continue
+			case row.Invalid != nil:
+				results[i].Err = row.Invalid
+				continue
+			}
+			if opts.DropReferences {
+				row.Request.ProjectID, row.Request.ParentID = nil, nil
+			}
+
+			if opts.SkipDuplicates {
+				exists, err := repos.Todos.ExistsByTitle(userID, row.Request.Title, row.Request.ProjectID)
+				if err != nil {
+					return err
+				}
+				if exists {
+					results[i].Skipped = ImportSkippedDuplicate
+					continue
+				}
+			}
+
+			pending := &event.Buffer{}
+			err := repos.Transaction(func(repos repository.Repositories) error {
+				todo, err := s.inTransaction(repos, pending).Create(userID, row.Request)
+				results[i].Todo = todo
+				return err
+			})
+			if err != nil {
+				results[i].Todo, results[i].Err = nil, err
+				continue
+			}
+			pending.Flush(committed)
+		}
+
+		if opts.DryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+
+	if !opts.DryRun {
+		committed.Flush(s.publisher)
+	}
+	return results, nil
+}
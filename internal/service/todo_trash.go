@@ -0,0 +1,187 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/event"
+	"github.com/vinibsi/todo-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Erros da lixeira
+var (
+	ErrNotInTrash    = apperror.NotFound("todo is not in the trash")
+	ErrParentInTrash = apperror.Conflict("parent todo is in the trash; restore it first")
+)
+
+// WithTrashRetention informa por quanto tempo as tarefas ficam na lixeira,
+// usado apenas para calcular purge_at (zero omite o prazo)
+func WithTrashRetention(retention time.Duration) TodoServiceOption {
+	return func(s *todoService) {
+		s.trashRetention = retention
+	}
+}
+
+// Trash lista as tarefas na lixeira, da remoção mais recente para a mais antiga
+func (s *todoService) Trash(userID uint, query *dto.TrashQuery) (*dto.TrashListResponse, error) {
+	page, pageSize := query.Page, query.Size
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	todos, total, err := s.repo.ListDeleted(userID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.TrashItemResponse, len(todos))
+	for i := range todos {
+		items[i] = dto.TrashItemResponse{
+			TodoResponse: *s.entityToDTO(&todos[i]),
+			DeletedAt:    todos[i].DeletedAt.Time,
+		}
+		if s.trashRetention > 0 {
+			purgeAt := todos[i].DeletedAt.Time.Add(s.trashRetention)
+			items[i].PurgeAt = &purgeAt
+		}
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(pageSize)))
+	return &dto.TrashListResponse{
+		Data:       items,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+		HasMore:    page < totalPages,
+	}, nil
+}
+
+// Restore tira a tarefa da lixeira junto com as subtarefas removidas com ela.
+// A tarefa pai precisa estar ativa; se o projeto foi removido, as tarefas
+// voltam para a caixa de entrada
+func (s *todoService) Restore(userID, id uint) (*dto.TodoResponse, error) {
+	var response *dto.TodoResponse
+	err := s.atomically(func(tx *todoService) error {
+		var err error
+		response, err = tx.restore(userID, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *todoService) restore(userID, id uint) (*dto.TodoResponse, error) {
+	if err := s.repo.Restore(userID, id); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrParentDeleted):
+			return nil, ErrParentInTrash
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, ErrNotInTrash
+		}
+		return nil, err
+	}
+
+	todo, err := s.repo.GetByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	restored := []entity.Todo{*todo}
+	if todo.SubtasksTotal > 0 {
+		descendants, err := s.repo.Descendants(userID, id)
+		if err != nil {
+			return nil, err
+		}
+		restored = append(restored, descendants...)
+	}
+
+	projects := make(map[uint]bool)
+	for i := range restored {
+		todo := &restored[i]
+		before := snapshotOf(todo)
+		if todo.ProjectID != nil {
+			exists, err := s.projectExists(userID, *todo.ProjectID, projects)
+			if err != nil {
+				return nil, err
+			}
+			if !exists {
+				todo.ProjectID = nil
+				if err := s.repo.Update(todo); err != nil {
+					return nil, versionError(err, nil)
+				}
+			}
+		}
+
+		if err := s.record(userID, todo, entity.HistoryRestore, before, nil); err != nil {
+			return nil, err
+		}
+		s.publish(event.TodoRestored, userID, s.entityToDTO(todo))
+	}
+
+	return s.entityToDTO(&restored[0]), nil
+}
+
+// Purge apaga definitivamente a tarefa, esteja ela ativa ou na lixeira, com
+// todas as subtarefas e o histórico
+func (s *todoService) Purge(userID, id uint, ifMatch *int) error {
+	// Tarefas ativas ainda não geraram todo.deleted
+	var removed []entity.Todo
+	todo, err := s.repo.GetByID(userID, id)
+	switch {
+	case err == nil:
+		removed = append(removed, *todo)
+		if todo.SubtasksTotal > 0 {
+			descendants, err := s.repo.Descendants(userID, id)
+			if err != nil {
+				return err
+			}
+			removed = append(removed, descendants...)
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if todo, err = s.repo.GetDeleted(userID, id); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrTodoNotFound
+			}
+			return err
+		}
+	default:
+		return err
+	}
+	if err := checkVersion(todo, ifMatch); err != nil {
+		return err
+	}
+
+	if err := s.repo.Purge(userID, id, todo.Version); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTodoNotFound
+		}
+		return versionError(err, ifMatch)
+	}
+
+	for i := range removed {
+		s.publish(event.TodoDeleted, userID, s.entityToDTO(&removed[i]))
+	}
+	return nil
+}
+
+func (s *todoService) projectExists(userID, projectID uint, cache map[uint]bool) (bool, error) {
+	if exists, ok := cache[projectID]; ok {
+		return exists, nil
+	}
+	_, err := s.projectRepo.GetByID(userID, projectID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+	cache[projectID] = err == nil
+	return err == nil, nil
+}
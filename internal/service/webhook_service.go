@@ -0,0 +1,277 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/event"
+	"github.com/vinibsi/todo-api/internal/repository"
+	"github.com/vinibsi/todo-api/internal/webhook"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrWebhookNotFound  = apperror.NotFound("webhook not found")
+	ErrDeliveryNotFound = apperror.NotFound("webhook delivery not found")
+	ErrInvalidWebhook   = apperror.Validation("invalid webhook")
+)
+
+// Quantidade de entregas retornadas na listagem (as mais recentes)
+const webhookDeliveriesLimit = 100
+
+type WebhookService interface {
+	Create(userID uint, req *dto.CreateWebhookRequest) (*dto.CreatedWebhookResponse, error)
+	List(userID uint) ([]dto.WebhookResponse, error)
+	GetByID(userID, id uint) (*dto.WebhookResponse, error)
+	Update(userID, id uint, req *dto.UpdateWebhookRequest) (*dto.WebhookResponse, error)
+	Delete(userID, id uint) error
+	ListDeliveries(userID, webhookID uint) ([]dto.WebhookDeliveryResponse, error)
+	GetDelivery(userID, webhookID, id uint) (*dto.WebhookDeliveryResponse, error)
+	Redeliver(userID, webhookID, id uint) (*dto.WebhookDeliveryResponse, error)
+}
+
+type webhookService struct {
+	repo repository.WebhookRepository
+}
+
+func NewWebhookService(repo repository.WebhookRepository) WebhookService {
+	return &webhookService{repo: repo}
+}
+
+func (s *webhookService) Create(userID uint, req *dto.CreateWebhookRequest) (*dto.CreatedWebhookResponse, error) {
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	events, err := webhookEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		// Sem segredo informado, gera um com 256 bits aleatórios
+		raw := make([]byte, 32)
+		rand.Read(raw)
+		secret = "whsec_" + base64.RawURLEncoding.EncodeToString(raw)
+	}
+
+	webhook := &entity.Webhook{
+		UserID: userID,
+		URL:    req.URL,
+		Secret: secret,
+		Events: events,
+		Active: req.Active == nil || *req.Active,
+	}
+	if err := s.repo.Create(webhook); err != nil {
+		return nil, err
+	}
+
+	return &dto.CreatedWebhookResponse{WebhookResponse: *webhookToDTO(webhook), Secret: secret}, nil
+}
+
+func (s *webhookService) List(userID uint) ([]dto.WebhookResponse, error) {
+	webhooks, err := s.repo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.WebhookResponse, len(webhooks))
+	for i := range webhooks {
+		responses[i] = *webhookToDTO(&webhooks[i])
+	}
+	return responses, nil
+}
+
+func (s *webhookService) GetByID(userID, id uint) (*dto.WebhookResponse, error) {
+	webhook, err := s.get(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return webhookToDTO(webhook), nil
+}
+
+func (s *webhookService) Update(userID, id uint, req *dto.UpdateWebhookRequest) (*dto.WebhookResponse, error) {
+	webhook, err := s.get(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		if err := validateWebhookURL(*req.URL); err != nil {
+			return nil, err
+		}
+		webhook.URL = *req.URL
+	}
+	if req.Secret != nil {
+		webhook.Secret = *req.Secret
+	}
+	if req.Events != nil {
+		events, err := webhookEvents(req.Events)
+		if err != nil {
+			return nil, err
+		}
+		webhook.Events = events
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+
+	if err := s.repo.Update(webhook); err != nil {
+		return nil, err
+	}
+	return webhookToDTO(webhook), nil
+}
+
+func (s *webhookService) Delete(userID, id uint) error {
+	deleted, err := s.repo.Delete(userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (s *webhookService) ListDeliveries(userID, webhookID uint) ([]dto.WebhookDeliveryResponse, error) {
+	if _, err := s.get(userID, webhookID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.repo.ListDeliveries(webhookID, webhookDeliveriesLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.WebhookDeliveryResponse, len(deliveries))
+	for i := range deliveries {
+		responses[i] = *deliveryToDTO(&deliveries[i], false)
+	}
+	return responses, nil
+}
+
+// GetDelivery retorna a entrega com o corpo enviado e o histórico de tentativas
+func (s *webhookService) GetDelivery(userID, webhookID, id uint) (*dto.WebhookDeliveryResponse, error) {
+	if _, err := s.get(userID, webhookID); err != nil {
+		return nil, err
+	}
+
+	delivery, err := s.repo.GetDelivery(webhookID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+	return deliveryToDTO(delivery, true), nil
+}
+
+// Redeliver coloca a entrega de volta na fila com o mesmo corpo
+func (s *webhookService) Redeliver(userID, webhookID, id uint) (*dto.WebhookDeliveryResponse, error) {
+	if _, err := s.get(userID, webhookID); err != nil {
+		return nil, err
+	}
+
+	found, err := s.repo.Redeliver(webhookID, id, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrDeliveryNotFound
+	}
+	return s.GetDelivery(userID, webhookID, id)
+}
+
+func (s *webhookService) get(userID, id uint) (*entity.Webhook, error) {
+	webhook, err := s.repo.GetByID(userID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// validateWebhookURL recusa de antemão os destinos óbvios na rede interna.
+// Nomes que resolvem para ela são barrados pelo cliente do worker na conexão
+func validateWebhookURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidWebhook)
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(parsed.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: url must not point to a private address", ErrInvalidWebhook)
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !webhook.IsPublicAddr(addr) {
+		return fmt.Errorf("%w: url must not point to a private address", ErrInvalidWebhook)
+	}
+	return nil
+}
+
+// webhookEvents valida os tipos e os grava sem repetições
+func webhookEvents(events []string) (string, error) {
+	if len(events) == 0 {
+		return "", fmt.Errorf("%w: at least one event is required", ErrInvalidWebhook)
+	}
+	for _, eventType := range events {
+		if !event.IsValidType(eventType) {
+			return "", fmt.Errorf("%w: unknown event %q", ErrInvalidWebhook, eventType)
+		}
+	}
+	return strings.Join(uniqueStrings(events), ","), nil
+}
+
+func webhookToDTO(webhook *entity.Webhook) *dto.WebhookResponse {
+	return &dto.WebhookResponse{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    splitScopes(webhook.Events),
+		Active:    webhook.Active,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+}
+
+func deliveryToDTO(delivery *entity.WebhookDelivery, detailed bool) *dto.WebhookDeliveryResponse {
+	response := &dto.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		Event:          delivery.Event,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+	if !detailed {
+		return response
+	}
+
+	response.Payload = json.RawMessage(delivery.Payload)
+	response.History = make([]dto.WebhookAttemptResponse, len(delivery.AttemptLog))
+	for i, attempt := range delivery.AttemptLog {
+		response.History[i] = dto.WebhookAttemptResponse{
+			ID:         attempt.ID,
+			StatusCode: attempt.StatusCode,
+			Error:      attempt.Error,
+			DurationMs: attempt.DurationMs,
+			CreatedAt:  attempt.CreatedAt,
+		}
+	}
+	return response
+}
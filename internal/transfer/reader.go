@@ -0,0 +1,245 @@
+package transfer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/dto"
+)
+
+// Row é uma linha lida do arquivo de importação. Line é a linha em que o
+// registro começa; Blank marca linhas vazias e Err um registro que não pôde
+// ser convertido em CreateTodoRequest
+type Row struct {
+	Line  int
+	Todo  dto.CreateTodoRequest
+	Blank bool
+	Err   error
+}
+
+// ErrInvalidFile indica um arquivo que não pode ser lido até o fim (cabeçalho
+// ausente, JSON malformado)
+var ErrInvalidFile = apperror.Validation("invalid import file")
+
+// importColumns são as colunas do CSV lidas na importação
+var importColumns = map[string]bool{
+	"title": true, "description": true, "priority": true, "due_date": true, "project_id": true,
+	"parent_id": true, "tags": true, "recurrence_rule": true, "recurrence_timezone": true,
+	"recurrence_exdates": true, "recurrence_from_completion": true,
+}
+
+// ReadAll lê todas as linhas do arquivo no formato informado
+func ReadAll(format string, r io.Reader) ([]Row, error) {
+	switch format {
+	case FormatCSV:
+		return readCSV(r)
+	case FormatJSON:
+		return readJSON(r)
+	case FormatNDJSON:
+		return readNDJSON(r)
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+func readCSV(r io.Reader) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: missing header", ErrInvalidFile)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if importColumns[name] {
+			columns[name] = i
+		}
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, fmt.Errorf("%w: header must include a title column", ErrInvalidFile)
+	}
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return rows, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := Row{Line: line}
+		if len(record) != len(header) {
+			// O csv.Reader pula linhas totalmente vazias; aqui sobram as
+			// linhas com o número errado de colunas
+			row.Err = fmt.Errorf("expected %d columns, got %d", len(header), len(record))
+		} else if isBlank(record) {
+			row.Blank = true
+		} else {
+			row.Err = parseCSVRecord(record, columns, &row.Todo)
+		}
+		rows = append(rows, row)
+	}
+}
+
+func parseCSVRecord(record []string, columns map[string]int, todo *dto.CreateTodoRequest) error {
+	value := func(name string) string {
+		if i, ok := columns[name]; ok {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	todo.Title = unescapeCell(value("title"))
+	todo.Description = unescapeCell(value("description"))
+	todo.Priority = value("priority")
+	todo.Tags = splitList(unescapeCell(value("tags")))
+
+	var err error
+	if todo.DueDate, err = parseTime("due_date", value("due_date")); err != nil {
+		return err
+	}
+	if todo.ProjectID, err = parseID("project_id", value("project_id")); err != nil {
+		return err
+	}
+	if todo.ParentID, err = parseID("parent_id", value("parent_id")); err != nil {
+		return err
+	}
+
+	if rule := value("recurrence_rule"); rule != "" {
+		todo.Recurrence = &dto.RecurrenceRequest{
+			Rule:     rule,
+			Timezone: value("recurrence_timezone"),
+			Exdates:  splitList(value("recurrence_exdates")),
+		}
+		if raw := value("recurrence_from_completion"); raw != "" {
+			if todo.Recurrence.FromCompletion, err = strconv.ParseBool(raw); err != nil {
+				return fmt.Errorf("recurrence_from_completion: invalid boolean %q", raw)
+			}
+		}
+	}
+	return nil
+}
+
+// readJSON lê um array de objetos. Line é a linha em que cada objeto começa
+func readJSON(r io.Reader) ([]Row, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return nil, fmt.Errorf("%w: expected a JSON array", ErrInvalidFile)
+	}
+
+	var rows []Row
+	for decoder.More() {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return rows, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		start := decoder.InputOffset() - int64(len(raw))
+		line := 1 + bytes.Count(data[:start], []byte("\n"))
+		rows = append(rows, decodeJSONRow(line, raw))
+	}
+	if _, err := decoder.Token(); err != nil {
+		return rows, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	return rows, nil
+}
+
+// readNDJSON lê um objeto por linha; linhas malformadas viram erros da linha
+func readNDJSON(r io.Reader) ([]Row, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var rows []Row
+	for line := 1; scanner.Scan(); line++ {
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			rows = append(rows, Row{Line: line, Blank: true})
+			continue
+		}
+		rows = append(rows, decodeJSONRow(line, raw))
+	}
+	if err := scanner.Err(); err != nil {
+		return rows, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	return rows, nil
+}
+
+func decodeJSONRow(line int, raw []byte) Row {
+	row := Row{Line: line}
+	if string(raw) == "null" {
+		row.Blank = true
+		return row
+	}
+	if err := json.Unmarshal(raw, &row.Todo); err != nil {
+		row.Err = err
+	}
+	return row
+}
+
+func isBlank(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	var items []string
+	for _, item := range strings.Split(value, listSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parseTime(field, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: expected an RFC 3339 date, got %q", field, value)
+	}
+	return &t, nil
+}
+
+func parseID(field, value string) (*uint, error) {
+	if value == "" {
+		return nil, nil
+	}
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || id == 0 {
+		return nil, fmt.Errorf("%s: invalid id %q", field, value)
+	}
+	result := uint(id)
+	return &result, nil
+}
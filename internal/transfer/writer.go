@@ -0,0 +1,224 @@
+// Package transfer lê e grava tarefas em CSV, JSON e NDJSON para a
+// importação e a exportação.
+package transfer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/dto"
+)
+
+// Formatos aceitos
+const (
+	FormatCSV    = "csv"
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+)
+
+// ErrUnknownFormat indica um formato fora de Formats
+var ErrUnknownFormat = apperror.Validation("unknown format")
+
+// listSeparator separa as tags e as exceções da repetição nas colunas do CSV
+const listSeparator = ";"
+
+// formulaPrefixes são os inícios de célula que as planilhas interpretam como
+// fórmula (CSV injection)
+const formulaPrefixes = "=+-@\t\r"
+
+// csvColumns são as colunas exportadas. Na importação, colunas fora de
+// importColumns (id, completed, datas de criação) são ignoradas
+var csvColumns = []string{
+	"id", "title", "description", "priority", "completed", "due_date", "project_id", "parent_id", "tags",
+	"recurrence_rule", "recurrence_timezone", "recurrence_exdates", "recurrence_from_completion",
+	"created_at", "updated_at",
+}
+
+// ContentType retorna o tipo MIME do formato
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	default:
+		return "application/json"
+	}
+}
+
+// FormatFromFilename deduz o formato pela extensão do arquivo (vazio se
+// não reconhecida)
+func FormatFromFilename(name string) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".csv":
+		return FormatCSV
+	case ".json":
+		return FormatJSON
+	case ".ndjson", ".jsonl":
+		return FormatNDJSON
+	default:
+		return ""
+	}
+}
+
+// Writer grava as tarefas uma a uma. Nada é escrito antes da primeira
+// chamada de Write ou Close; Close completa o documento
+type Writer interface {
+	Write(todo *dto.TodoResponse) error
+	Close() error
+}
+
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	buffered := bufio.NewWriter(w)
+	switch format {
+	case FormatCSV:
+		return &csvWriter{out: buffered, csv: csv.NewWriter(buffered)}, nil
+	case FormatJSON:
+		return &jsonWriter{out: buffered, array: true}, nil
+	case FormatNDJSON:
+		return &jsonWriter{out: buffered}, nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+type csvWriter struct {
+	out     *bufio.Writer
+	csv     *csv.Writer
+	started bool
+}
+
+func (w *csvWriter) start() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+	return w.csv.Write(csvColumns)
+}
+
+func (w *csvWriter) Write(todo *dto.TodoResponse) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	record := []string{
+		strconv.FormatUint(uint64(todo.ID), 10),
+		escapeCell(todo.Title),
+		escapeCell(todo.Description),
+		todo.Priority,
+		strconv.FormatBool(todo.Completed),
+		formatTime(todo.DueDate),
+		formatID(todo.ProjectID),
+		formatID(todo.ParentID),
+		escapeCell(strings.Join(todo.Tags, listSeparator)),
+		"", "", "", "",
+		todo.CreatedAt.UTC().Format(time.RFC3339),
+		todo.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	if rec := todo.Recurrence; rec != nil {
+		record[9] = rec.Rule
+		record[10] = rec.Timezone
+		record[11] = strings.Join(rec.Exdates, listSeparator)
+		record[12] = strconv.FormatBool(rec.FromCompletion)
+	}
+	return w.csv.Write(record)
+}
+
+// escapeCell prefixa com ' os textos que uma planilha leria como fórmula. Um
+// texto que já começa com ' antes desses caracteres também ganha o prefixo,
+// para que unescapeCell devolva sempre o valor original na importação
+func escapeCell(value string) string {
+	if isFormula(strings.TrimLeft(value, "'")) {
+		return "'" + value
+	}
+	return value
+}
+
+func unescapeCell(value string) string {
+	if strings.HasPrefix(value, "'") && isFormula(strings.TrimLeft(value, "'")) {
+		return value[1:]
+	}
+	return value
+}
+
+func isFormula(value string) bool {
+	return value != "" && strings.ContainsRune(formulaPrefixes, rune(value[0]))
+}
+
+func (w *csvWriter) Close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	w.csv.Flush()
+	if err := w.csv.Error(); err != nil {
+		return err
+	}
+	return w.out.Flush()
+}
+
+// jsonWriter grava um array JSON (array) ou um objeto por linha (NDJSON)
+type jsonWriter struct {
+	out     *bufio.Writer
+	array   bool
+	written int
+}
+
+func (w *jsonWriter) Write(todo *dto.TodoResponse) error {
+	data, err := json.Marshal(todo)
+	if err != nil {
+		return err
+	}
+
+	separator := "\n"
+	if w.array {
+		separator = ",\n"
+		if w.written == 0 {
+			separator = "[\n"
+		}
+	} else if w.written == 0 {
+		separator = ""
+	}
+	if _, err := w.out.WriteString(separator); err != nil {
+		return err
+	}
+	w.written++
+	_, err = w.out.Write(data)
+	return err
+}
+
+func (w *jsonWriter) Close() error {
+	closing := "\n"
+	if w.array {
+		closing = "\n]\n"
+		if w.written == 0 {
+			closing = "[]\n"
+		}
+	} else if w.written == 0 {
+		closing = ""
+	}
+	if _, err := w.out.WriteString(closing); err != nil {
+		return err
+	}
+	return w.out.Flush()
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
@@ -0,0 +1,111 @@
+// Package trash apaga definitivamente as tarefas que passaram do prazo de
+// retenção na lixeira.
+package trash
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/vinibsi/todo-api/internal/repository"
+)
+
+// Config ajusta a limpeza da lixeira. Valores zerados usam os padrões
+type Config struct {
+	Retention time.Duration // tempo que uma tarefa fica na lixeira
+	Interval  time.Duration // intervalo entre as limpezas
+	BatchSize int           // tarefas apagadas por transação
+	Now       func() time.Time
+}
+
+const (
+	defaultRetention = 30 * 24 * time.Hour
+	defaultInterval  = time.Hour
+	defaultBatchSize = 500
+)
+
+// Purger apaga periodicamente as tarefas removidas há mais tempo que a
+// retenção. Várias instâncias podem rodar ao mesmo tempo: apagar uma tarefa
+// que outra instância já apagou não tem efeito
+type Purger struct {
+	repo   repository.TodoRepository
+	config Config
+	logger *log.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+func NewPurger(repo repository.TodoRepository, config Config) *Purger {
+	if config.Retention <= 0 {
+		config.Retention = defaultRetention
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaultInterval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultBatchSize
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	return &Purger{repo: repo, config: config, logger: log.Default()}
+}
+
+// Start inicia a goroutine da limpeza, que termina com Stop ou quando ctx é
+// cancelado
+func (p *Purger) Start(ctx context.Context) {
+	ctx, p.cancel = context.WithCancel(ctx)
+	p.done = make(chan struct{})
+
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(p.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			if purged, err := p.RunOnce(ctx); err != nil && ctx.Err() == nil {
+				p.logger.Printf("Trash purger: %v", err)
+			} else if purged > 0 {
+				p.logger.Printf("Trash purger: %d todos permanently deleted", purged)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop interrompe a limpeza e espera o lote em andamento terminar
+func (p *Purger) Stop() {
+	p.once.Do(func() {
+		if p.cancel == nil {
+			return
+		}
+		p.cancel()
+		<-p.done
+	})
+}
+
+// RunOnce apaga em lotes as tarefas vencidas, retornando quantas foram
+// apagadas (subtarefas incluídas)
+func (p *Purger) RunOnce(ctx context.Context) (int, error) {
+	cutoff := p.config.Now().Add(-p.config.Retention)
+
+	total := 0
+	for ctx.Err() == nil {
+		purged, err := p.repo.PurgeDeletedBefore(cutoff, p.config.BatchSize)
+		total += purged
+		if err != nil || purged == 0 {
+			return total, err
+		}
+	}
+	return total, ctx.Err()
+}
@@ -0,0 +1,96 @@
+// Package webhook entrega os eventos das tarefas aos webhooks cadastrados
+// pelos usuários, com assinatura HMAC e novas tentativas.
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/event"
+	"github.com/vinibsi/todo-api/internal/repository"
+)
+
+// Payload é o corpo JSON enviado aos webhooks
+type Payload struct {
+	ID         string            `json:"id"`
+	Type       string            `json:"type"`
+	OccurredAt time.Time         `json:"occurred_at"`
+	Data       *dto.TodoResponse `json:"data"`
+}
+
+// Dispatcher é o event.Publisher que enfileira uma entrega para cada webhook
+// ativo inscrito no evento. O envio em si fica a cargo do Worker
+type Dispatcher struct {
+	repo   repository.WebhookRepository
+	now    func() time.Time
+	logger *log.Logger
+}
+
+func NewDispatcher(repo repository.WebhookRepository) *Dispatcher {
+	return &Dispatcher{repo: repo, now: time.Now, logger: log.Default()}
+}
+
+func (d *Dispatcher) Publish(e event.Event) {
+	if err := d.enqueue(e); err != nil {
+		// A mudança na tarefa já foi gravada; a falha só afeta as notificações
+		d.logger.Printf("Webhook dispatcher: %s for todo %d: %v", e.Type, e.TodoID, err)
+	}
+}
+
+func (d *Dispatcher) enqueue(e event.Event) error {
+	webhooks, err := d.repo.ListActive(e.UserID)
+	if err != nil {
+		return err
+	}
+
+	var subscribed []entity.Webhook
+	for _, webhook := range webhooks {
+		if Subscribes(&webhook, e.Type) {
+			subscribed = append(subscribed, webhook)
+		}
+	}
+	if len(subscribed) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(Payload{ID: newEventID(), Type: e.Type, OccurredAt: e.OccurredAt, Data: e.Todo})
+	if err != nil {
+		return err
+	}
+
+	now := d.now()
+	deliveries := make([]entity.WebhookDelivery, len(subscribed))
+	for i, webhook := range subscribed {
+		deliveries[i] = entity.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			Event:         e.Type,
+			Payload:       string(body),
+			Status:        entity.DeliveryPending,
+			NextAttemptAt: &now,
+		}
+	}
+	return d.repo.CreateDeliveries(deliveries)
+}
+
+// Subscribes informa se o webhook está inscrito no tipo de evento
+func Subscribes(webhook *entity.Webhook, eventType string) bool {
+	for _, subscribed := range strings.Split(webhook.Events, ",") {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// newEventID identifica o evento; é o mesmo em todas as entregas e repetições
+func newEventID() string {
+	id := make([]byte, 12)
+	rand.Read(id)
+	return "evt_" + hex.EncodeToString(id)
+}
@@ -0,0 +1,225 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/repository"
+)
+
+// Config ajusta o worker. Valores zerados usam os padrões
+type Config struct {
+	Interval    time.Duration // intervalo entre as verificações
+	BatchSize   int           // entregas reservadas por verificação
+	Lease       time.Duration // tempo de reserva de uma entrega em envio
+	MaxAttempts int           // tentativas antes de marcar a entrega como falha
+	BaseBackoff time.Duration // espera após a primeira falha, dobrada a cada nova falha
+	MaxBackoff  time.Duration
+	Timeout     time.Duration // limite de cada requisição
+	Client      *http.Client
+	Now         func() time.Time
+}
+
+const (
+	defaultInterval    = 5 * time.Second
+	defaultBatchSize   = 50
+	defaultLease       = 2 * time.Minute
+	defaultMaxAttempts = 8
+	defaultBaseBackoff = 30 * time.Second
+	defaultMaxBackoff  = 6 * time.Hour
+	defaultTimeout     = 10 * time.Second
+)
+
+// Worker envia as entregas pendentes. Assim como o agendador de lembretes,
+// pode rodar em várias instâncias porque cada entrega é reservada no banco
+type Worker struct {
+	repo   repository.WebhookRepository
+	config Config
+	logger *log.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+func NewWorker(repo repository.WebhookRepository, config Config) *Worker {
+	if config.Interval <= 0 {
+		config.Interval = defaultInterval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultBatchSize
+	}
+	if config.Lease <= 0 {
+		config.Lease = defaultLease
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultMaxAttempts
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = defaultBaseBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = defaultMaxBackoff
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTimeout
+	}
+	if config.Client == nil {
+		config.Client = NewClient()
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	return &Worker{repo: repo, config: config, logger: log.Default()}
+}
+
+// Start inicia a goroutine do worker, que termina com Stop ou quando ctx é
+// cancelado
+func (w *Worker) Start(ctx context.Context) {
+	ctx, w.cancel = context.WithCancel(ctx)
+	w.done = make(chan struct{})
+
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(w.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := w.RunOnce(ctx); err != nil && ctx.Err() == nil {
+				w.logger.Printf("Webhook worker: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop interrompe o worker e espera o lote em andamento terminar
+func (w *Worker) Stop() {
+	w.once.Do(func() {
+		if w.cancel == nil {
+			return
+		}
+		w.cancel()
+		<-w.done
+	})
+}
+
+// RunOnce reserva e envia um lote de entregas pendentes, retornando quantas
+// foram aceitas pelos receptores
+func (w *Worker) RunOnce(ctx context.Context) (int, error) {
+	deliveries, err := w.repo.ClaimDue(w.config.Now(), w.config.BatchSize, w.config.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	succeeded := 0
+	for i := range deliveries {
+		if ctx.Err() != nil {
+			// As entregas restantes voltam a ser elegíveis quando a reserva expirar
+			return succeeded, ctx.Err()
+		}
+		if w.deliver(ctx, &deliveries[i]) {
+			succeeded++
+		}
+	}
+	return succeeded, nil
+}
+
+func (w *Worker) deliver(ctx context.Context, delivery *entity.WebhookDelivery) bool {
+	started := w.config.Now()
+	statusCode, err := w.send(ctx, delivery)
+
+	attempt := &entity.WebhookAttempt{
+		StatusCode: statusCode,
+		DurationMs: w.config.Now().Sub(started).Milliseconds(),
+	}
+
+	now := w.config.Now()
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+
+	if err == nil {
+		delivery.Status = entity.DeliverySucceeded
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+		delivery.LastError = ""
+	} else {
+		attempt.Error = err.Error()
+		delivery.LastError = err.Error()
+		if delivery.Attempts < w.config.MaxAttempts {
+			next := now.Add(w.backoff(delivery.Attempts))
+			delivery.NextAttemptAt = &next
+		} else {
+			delivery.Status = entity.DeliveryFailed
+			delivery.NextAttemptAt = nil
+		}
+	}
+
+	if recordErr := w.repo.RecordAttempt(delivery, attempt); recordErr != nil {
+		w.logger.Printf("Webhook delivery %d: %v", delivery.ID, recordErr)
+	}
+	return err == nil
+}
+
+// backoff dobra a espera a cada falha: 30s, 1m, 2m, 4m... até MaxBackoff
+func (w *Worker) backoff(attempts int) time.Duration {
+	wait := w.config.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= w.config.MaxBackoff {
+			return w.config.MaxBackoff
+		}
+	}
+	return wait
+}
+
+// send faz o POST assinado. Respostas fora da faixa 2xx são tratadas como
+// falha; o corpo da resposta é descartado para não expor à API o conteúdo
+// de quem recebe a entrega
+func (w *Worker) send(ctx context.Context, delivery *entity.WebhookDelivery) (int, error) {
+	if delivery.Webhook == nil {
+		return 0, fmt.Errorf("webhook %d not found", delivery.WebhookID)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, w.config.Timeout)
+	defer cancel()
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := w.config.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "todo-api-webhooks")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(delivery.Webhook.Secret, timestamp, body))
+
+	resp, err := w.config.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/vinibsi/todo-api/internal/entity"
+)
+
+type MockAPITokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPITokenRepository) Create(token *entity.APIToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockAPITokenRepository) GetByHash(hash string) (*entity.APIToken, error) {
+	args := m.Called(hash)
+	return args.Get(0).(*entity.APIToken), args.Error(1)
+}
+
+func (m *MockAPITokenRepository) ListByUser(userID uint) ([]entity.APIToken, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]entity.APIToken), args.Error(1)
+}
+
+func (m *MockAPITokenRepository) Delete(userID, id uint) (bool, error) {
+	args := m.Called(userID, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAPITokenRepository) TouchLastUsed(id uint, usedAt time.Time, minInterval time.Duration) error {
+	args := m.Called(id, usedAt, minInterval)
+	return args.Error(0)
+}
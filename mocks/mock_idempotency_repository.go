@@ -0,0 +1,35 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/vinibsi/todo-api/internal/entity"
+)
+
+type MockIdempotencyRepository struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyRepository) Acquire(record *entity.IdempotencyKey, staleBefore time.Time) (*entity.IdempotencyKey, bool, error) {
+	args := m.Called(record, staleBefore)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).(*entity.IdempotencyKey), args.Bool(1), args.Error(2)
+}
+
+func (m *MockIdempotencyRepository) Complete(id uint, statusCode int, headers, body string) error {
+	args := m.Called(id, statusCode, headers, body)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) Release(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) DeleteExpired(now time.Time, limit int) (int, error) {
+	args := m.Called(now, limit)
+	return args.Int(0), args.Error(1)
+}
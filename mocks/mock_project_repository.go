@@ -0,0 +1,35 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/vinibsi/todo-api/internal/entity"
+)
+
+type MockProjectRepository struct {
+	mock.Mock
+}
+
+func (m *MockProjectRepository) Create(project *entity.Project) error {
+	args := m.Called(project)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) GetByID(userID, id uint) (*entity.Project, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(*entity.Project), args.Error(1)
+}
+
+func (m *MockProjectRepository) List(userID uint, includeArchived bool) ([]entity.Project, error) {
+	args := m.Called(userID, includeArchived)
+	return args.Get(0).([]entity.Project), args.Error(1)
+}
+
+func (m *MockProjectRepository) Update(project *entity.Project) error {
+	args := m.Called(project)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) Delete(userID, id uint, cascade bool) error {
+	args := m.Called(userID, id, cascade)
+	return args.Error(0)
+}
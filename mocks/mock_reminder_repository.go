@@ -0,0 +1,47 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/vinibsi/todo-api/internal/entity"
+)
+
+type MockReminderRepository struct {
+	mock.Mock
+}
+
+func (m *MockReminderRepository) Create(reminder *entity.Reminder) error {
+	args := m.Called(reminder)
+	return args.Error(0)
+}
+
+func (m *MockReminderRepository) ListByTodo(userID, todoID uint) ([]entity.Reminder, error) {
+	args := m.Called(userID, todoID)
+	return args.Get(0).([]entity.Reminder), args.Error(1)
+}
+
+func (m *MockReminderRepository) Delete(userID, todoID, id uint) (bool, error) {
+	args := m.Called(userID, todoID, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockReminderRepository) ClaimDue(now time.Time, limit int, lease time.Duration) ([]entity.Reminder, error) {
+	args := m.Called(now, limit, lease)
+	return args.Get(0).([]entity.Reminder), args.Error(1)
+}
+
+func (m *MockReminderRepository) RenewClaim(id uint, claimedUntil, until time.Time) error {
+	args := m.Called(id, claimedUntil, until)
+	return args.Error(0)
+}
+
+func (m *MockReminderRepository) MarkSent(id uint, claimedUntil, sentAt time.Time) error {
+	args := m.Called(id, claimedUntil, sentAt)
+	return args.Error(0)
+}
+
+func (m *MockReminderRepository) MarkFailed(id uint, claimedUntil time.Time, reason string, failedAt time.Time, retryAt *time.Time) error {
+	args := m.Called(id, claimedUntil, reason, failedAt, retryAt)
+	return args.Error(0)
+}
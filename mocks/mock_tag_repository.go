@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/repository"
+)
+
+type MockTagRepository struct {
+	mock.Mock
+}
+
+func (m *MockTagRepository) FindOrCreate(userID uint, names []string) ([]entity.Tag, error) {
+	args := m.Called(userID, names)
+	return args.Get(0).([]entity.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) ListWithUsage(userID uint) ([]repository.TagUsage, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]repository.TagUsage), args.Error(1)
+}
@@ -0,0 +1,28 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/vinibsi/todo-api/internal/entity"
+)
+
+type MockTodoHistoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockTodoHistoryRepository) Record(entry *entity.TodoHistory) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockTodoHistoryRepository) List(userID, todoID uint) ([]entity.TodoHistory, error) {
+	args := m.Called(userID, todoID)
+	return args.Get(0).([]entity.TodoHistory), args.Error(1)
+}
+
+func (m *MockTodoHistoryRepository) GetRevision(userID, todoID uint, revision int) (*entity.TodoHistory, error) {
+	args := m.Called(userID, todoID, revision)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.TodoHistory), args.Error(1)
+}
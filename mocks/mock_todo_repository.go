@@ -1,8 +1,11 @@
 package mocks
 
 import (
+	"time"
+
 	"github.com/stretchr/testify/mock"
 	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/repository"
 )
 
 type MockTodoRepository struct {
@@ -14,13 +17,13 @@ func (m *MockTodoRepository) Create(todo *entity.Todo) error {
 	return args.Error(0)
 }
 
-func (m *MockTodoRepository) GetByID(id uint) (*entity.Todo, error) {
-	args := m.Called(id)
+func (m *MockTodoRepository) GetByID(userID, id uint) (*entity.Todo, error) {
+	args := m.Called(userID, id)
 	return args.Get(0).(*entity.Todo), args.Error(1)
 }
 
-func (m *MockTodoRepository) GetAll(limit, offset int) ([]entity.Todo, int64, error) {
-	args := m.Called(limit, offset)
+func (m *MockTodoRepository) GetAll(query repository.TodoQuery) ([]entity.Todo, int64, error) {
+	args := m.Called(query)
 	return args.Get(0).([]entity.Todo), args.Get(1).(int64), args.Error(2)
 }
 
@@ -29,12 +32,52 @@ func (m *MockTodoRepository) Update(todo *entity.Todo) error {
 	return args.Error(0)
 }
 
-func (m *MockTodoRepository) Delete(id uint) error {
-	args := m.Called(id)
+func (m *MockTodoRepository) Delete(userID, id uint, version int) error {
+	args := m.Called(userID, id, version)
 	return args.Error(0)
 }
 
-func (m *MockTodoRepository) GetByCompleted(completed bool, limit, offset int) ([]entity.Todo, int64, error) {
-	args := m.Called(completed, limit, offset)
+func (m *MockTodoRepository) Search(term string, query repository.TodoQuery) ([]repository.TodoSearchResult, int64, error) {
+	args := m.Called(term, query)
+	return args.Get(0).([]repository.TodoSearchResult), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTodoRepository) Descendants(userID, id uint) ([]entity.Todo, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).([]entity.Todo), args.Error(1)
+}
+
+func (m *MockTodoRepository) ListDeleted(userID uint, limit, offset int) ([]entity.Todo, int64, error) {
+	args := m.Called(userID, limit, offset)
 	return args.Get(0).([]entity.Todo), args.Get(1).(int64), args.Error(2)
 }
+
+func (m *MockTodoRepository) GetDeleted(userID, id uint) (*entity.Todo, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(*entity.Todo), args.Error(1)
+}
+
+func (m *MockTodoRepository) Restore(userID, id uint) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *MockTodoRepository) Purge(userID, id uint, version int) error {
+	args := m.Called(userID, id, version)
+	return args.Error(0)
+}
+
+func (m *MockTodoRepository) PurgeDeletedBefore(cutoff time.Time, limit int) (int, error) {
+	args := m.Called(cutoff, limit)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTodoRepository) Each(query repository.TodoQuery, batchSize int, fn func([]entity.Todo) error) error {
+	args := m.Called(query, batchSize, fn)
+	return args.Error(0)
+}
+
+func (m *MockTodoRepository) ExistsByTitle(userID uint, title string, projectID *uint) (bool, error) {
+	args := m.Called(userID, title, projectID)
+	return args.Bool(0), args.Error(1)
+}
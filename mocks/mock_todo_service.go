@@ -3,38 +3,94 @@ package mocks
 import (
 	"github.com/stretchr/testify/mock"
 	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/service"
 )
 
 type MockTodoService struct {
 	mock.Mock
 }
 
-func (m *MockTodoService) Create(req *dto.CreateTodoRequest) (*dto.TodoResponse, error) {
-	args := m.Called(req)
+func (m *MockTodoService) Create(userID uint, req *dto.CreateTodoRequest) (*dto.TodoResponse, error) {
+	args := m.Called(userID, req)
 	return args.Get(0).(*dto.TodoResponse), args.Error(1)
 }
 
-func (m *MockTodoService) GetByID(id uint) (*dto.TodoResponse, error) {
-	args := m.Called(id)
+func (m *MockTodoService) GetByID(userID, id uint) (*dto.TodoResponse, error) {
+	args := m.Called(userID, id)
 	return args.Get(0).(*dto.TodoResponse), args.Error(1)
 }
 
-func (m *MockTodoService) GetAll(page, pageSize int) (*dto.TodoListResponse, error) {
-	args := m.Called(page, pageSize)
+func (m *MockTodoService) GetAll(userID uint, query *dto.TodoListQuery) (*dto.TodoListResponse, error) {
+	args := m.Called(userID, query)
 	return args.Get(0).(*dto.TodoListResponse), args.Error(1)
 }
 
-func (m *MockTodoService) Update(id uint, req *dto.UpdateTodoRequest) (*dto.TodoResponse, error) {
-	args := m.Called(id, req)
+func (m *MockTodoService) Update(userID, id uint, req *dto.UpdateTodoRequest, ifMatch *int) (*dto.TodoResponse, error) {
+	args := m.Called(userID, id, req, ifMatch)
 	return args.Get(0).(*dto.TodoResponse), args.Error(1)
 }
 
-func (m *MockTodoService) Delete(id uint) error {
-	args := m.Called(id)
+func (m *MockTodoService) Patch(userID, id uint, apply service.PatchFunc, ifMatch *int) (*dto.TodoResponse, error) {
+	args := m.Called(userID, id, apply, ifMatch)
+	return args.Get(0).(*dto.TodoResponse), args.Error(1)
+}
+
+func (m *MockTodoService) Delete(userID, id uint, ifMatch *int) error {
+	args := m.Called(userID, id, ifMatch)
 	return args.Error(0)
 }
 
-func (m *MockTodoService) Complete(id uint) (*dto.TodoResponse, error) {
-	args := m.Called(id)
+func (m *MockTodoService) DeleteProject(userID, projectID uint, cascade bool) error {
+	args := m.Called(userID, projectID, cascade)
+	return args.Error(0)
+}
+
+func (m *MockTodoService) Complete(userID, id uint, ifMatch *int) (*dto.TodoResponse, error) {
+	args := m.Called(userID, id, ifMatch)
+	return args.Get(0).(*dto.TodoResponse), args.Error(1)
+}
+
+func (m *MockTodoService) Tree(userID, id uint) (*dto.TodoTreeResponse, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(*dto.TodoTreeResponse), args.Error(1)
+}
+
+func (m *MockTodoService) History(userID, id uint) ([]dto.TodoHistoryResponse, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).([]dto.TodoHistoryResponse), args.Error(1)
+}
+
+func (m *MockTodoService) Revert(userID, id uint, revision int) (*dto.TodoResponse, error) {
+	args := m.Called(userID, id, revision)
+	return args.Get(0).(*dto.TodoResponse), args.Error(1)
+}
+
+func (m *MockTodoService) Trash(userID uint, query *dto.TrashQuery) (*dto.TrashListResponse, error) {
+	args := m.Called(userID, query)
+	return args.Get(0).(*dto.TrashListResponse), args.Error(1)
+}
+
+func (m *MockTodoService) Restore(userID, id uint) (*dto.TodoResponse, error) {
+	args := m.Called(userID, id)
 	return args.Get(0).(*dto.TodoResponse), args.Error(1)
 }
+
+func (m *MockTodoService) Purge(userID, id uint, ifMatch *int) error {
+	args := m.Called(userID, id, ifMatch)
+	return args.Error(0)
+}
+
+func (m *MockTodoService) Bulk(userID uint, mode string, operations []service.BulkOperation) (*service.BulkResult, error) {
+	args := m.Called(userID, mode, operations)
+	return args.Get(0).(*service.BulkResult), args.Error(1)
+}
+
+func (m *MockTodoService) Export(userID uint, query *dto.TodoListQuery, fn func(*dto.TodoResponse) error) error {
+	args := m.Called(userID, query, fn)
+	return args.Error(0)
+}
+
+func (m *MockTodoService) Import(userID uint, rows []service.ImportRow, opts service.ImportOptions) ([]service.ImportRowResult, error) {
+	args := m.Called(userID, rows, opts)
+	return args.Get(0).([]service.ImportRowResult), args.Error(1)
+}
@@ -0,0 +1,78 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/vinibsi/todo-api/internal/entity"
+)
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) Create(webhook *entity.Webhook) error {
+	args := m.Called(webhook)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetByID(userID, id uint) (*entity.Webhook, error) {
+	args := m.Called(userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ListByUser(userID uint) ([]entity.Webhook, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]entity.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ListActive(userID uint) ([]entity.Webhook, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]entity.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Update(webhook *entity.Webhook) error {
+	args := m.Called(webhook)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) Delete(userID, id uint) (bool, error) {
+	args := m.Called(userID, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockWebhookRepository) CreateDeliveries(deliveries []entity.WebhookDelivery) error {
+	args := m.Called(deliveries)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) ListDeliveries(webhookID uint, limit int) ([]entity.WebhookDelivery, error) {
+	args := m.Called(webhookID, limit)
+	return args.Get(0).([]entity.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetDelivery(webhookID, id uint) (*entity.WebhookDelivery, error) {
+	args := m.Called(webhookID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Redeliver(webhookID, id uint, now time.Time) (bool, error) {
+	args := m.Called(webhookID, id, now)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ClaimDue(now time.Time, limit int, lease time.Duration) ([]entity.WebhookDelivery, error) {
+	args := m.Called(now, limit, lease)
+	return args.Get(0).([]entity.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) RecordAttempt(delivery *entity.WebhookDelivery, attempt *entity.WebhookAttempt) error {
+	args := m.Called(delivery, attempt)
+	return args.Error(0)
+}
@@ -1,7 +1,7 @@
 package database
 
 import (
-	"github.com/vinibsi/todo-api/internal/entity"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
@@ -9,7 +9,23 @@ import (
 	"gorm.io/gorm/logger"
 )
 
+// Options ajusta o pool de conexões (apenas no PostgreSQL) e o log das
+// consultas. Valores zerados mantêm os padrões (sem log no SQLite, todas as
+// consultas no PostgreSQL)
+type Options struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	LogLevel        logger.LogLevel
+}
+
 func Connect(databaseUrl string) (*gorm.DB, error) {
+	return ConnectWith(databaseUrl, Options{})
+}
+
+// ConnectWith abre o banco de databaseUrl com as opções informadas
+func ConnectWith(databaseUrl string, options Options) (*gorm.DB, error) {
 	var db *gorm.DB
 	var err error
 
@@ -17,12 +33,12 @@ func Connect(databaseUrl string) (*gorm.DB, error) {
 	if databaseUrl == ":memory:" || databaseUrl == "file::memory:?cache=shared" {
 		// SQLite em memória para testes
 		db, err = gorm.Open(sqlite.Open(databaseUrl), &gorm.Config{
-			Logger: logger.Default.LogMode(logger.Silent),
+			Logger: logger.Default.LogMode(logLevel(options.LogLevel, logger.Silent)),
 		})
 	} else {
 		// PostgreSQL para produção
 		db, err = gorm.Open(postgres.Open(databaseUrl), &gorm.Config{
-			Logger: logger.Default.LogMode(logger.Info),
+			Logger: logger.Default.LogMode(logLevel(options.LogLevel, logger.Info)),
 		})
 	}
 
@@ -30,15 +46,53 @@ func Connect(databaseUrl string) (*gorm.DB, error) {
 		return nil, err
 	}
 
-	// Auto-migração
-	if err := db.AutoMigrate(&entity.Todo{}); err != nil {
-		return nil, err
+	// O SQLite em memória perde os dados quando a conexão é fechada, por isso
+	// o pool só é ajustado no PostgreSQL
+	if db.Dialector.Name() == "postgres" {
+		if err := configurePool(db, options); err != nil {
+			return nil, err
+		}
 	}
 
+	// O esquema é criado pelas migrações (ver Migrate e o comando migrate)
 	return db, nil
+}
 
+func configurePool(db *gorm.DB, options Options) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	if options.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(options.MaxOpenConns)
+	}
+	if options.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(options.MaxIdleConns)
+	}
+	if options.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(options.ConnMaxLifetime)
+	}
+	if options.ConnMaxIdleTime > 0 {
+		sqlDB.SetConnMaxIdleTime(options.ConnMaxIdleTime)
+	}
+	return nil
 }
 
+func logLevel(level, defaultLevel logger.LogLevel) logger.LogLevel {
+	if level == 0 {
+		return defaultLevel
+	}
+	return level
+}
+
+// ConnectTest abre um SQLite em memória já migrado
 func ConnectTest() (*gorm.DB, error) {
-	return Connect(":memory:")
+	db, err := Connect(":memory:")
+	if err != nil {
+		return nil, err
+	}
+	if err := Migrate(db); err != nil {
+		return nil, err
+	}
+	return db, nil
 }
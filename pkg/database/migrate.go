@@ -0,0 +1,375 @@
+package database
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Os arquivos seguem <versão>_<nome>.<up|down>[.<dialeto>].sql. O arquivo de
+// um dialeto (postgres, sqlite) tem preferência sobre o comum aos dois
+var migrationFileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)(?:\.(postgres|sqlite))?\.sql$`)
+
+// migrationLockKey identifica o advisory lock das migrações no PostgreSQL
+const migrationLockKey = 4_815_162_342
+
+var (
+	// ErrNoDownMigration indica uma migração sem script de reversão
+	ErrNoDownMigration = errors.New("migration has no down script")
+	// ErrUnknownMigration indica uma versão aplicada no banco que não existe
+	// entre as migrações (ex.: banco migrado por uma versão mais nova da API)
+	ErrUnknownMigration = errors.New("applied migration is unknown")
+)
+
+// Migration é uma versão do esquema com os scripts do dialeto em uso
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus é uma migração e o momento em que foi aplicada (nil se pendente)
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// schemaMigration é o registro de uma migração aplicada
+type schemaMigration struct {
+	Version   int `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// A tabela de controle usa tipos aceitos pelos dois dialetos
+const createSchemaMigrations = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version BIGINT PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	applied_at TIMESTAMP NOT NULL
+)`
+
+// Migrations lê as migrações embutidas no binário para o dialeto
+func Migrations(dialect string) ([]Migration, error) {
+	files, err := fs.Sub(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return LoadMigrations(files, dialect)
+}
+
+// LoadMigrations lê as migrações de fsys para o dialeto, em ordem de versão.
+// Toda versão precisa de um script up; o down é opcional
+func LoadMigrations(fsys fs.FS, dialect string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	// Scripts de cada versão, por sentido: [0] é o comum e [1] o do dialeto
+	type scripts struct {
+		name     string
+		up, down [2]*string
+	}
+	byVersion := map[int]*scripts{}
+
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, _ := strconv.Atoi(match[1])
+		name, direction, fileDialect := match[2], match[3], match[4]
+
+		current, ok := byVersion[version]
+		if !ok {
+			current = &scripts{name: name}
+			byVersion[version] = current
+		}
+		if current.name != name {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, current.name, name)
+		}
+		if fileDialect != "" && fileDialect != dialect {
+			continue
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		script := string(content)
+		slot := 0
+		if fileDialect != "" {
+			slot = 1
+		}
+		if direction == "up" {
+			current.up[slot] = &script
+		} else {
+			current.down[slot] = &script
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for version, current := range byVersion {
+		up, hasUp := pickScript(current.up)
+		if !hasUp {
+			return nil, fmt.Errorf("migration %04d_%s has no up script for %s", version, current.name, dialect)
+		}
+		down, _ := pickScript(current.down)
+		migrations = append(migrations, Migration{Version: version, Name: current.name, Up: up, Down: down})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+func pickScript(scripts [2]*string) (string, bool) {
+	for i := len(scripts) - 1; i >= 0; i-- {
+		if scripts[i] != nil {
+			return *scripts[i], true
+		}
+	}
+	return "", false
+}
+
+// Migrator aplica e reverte as migrações e registra as versões aplicadas na
+// tabela schema_migrations. Cada migração roda em uma transação junto com o
+// seu registro; no PostgreSQL um advisory lock impede que duas instâncias
+// migrem ao mesmo tempo
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator usa as migrações embutidas para o dialeto de db
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	migrations, err := Migrations(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	return NewMigratorWith(db, migrations), nil
+}
+
+// NewMigratorWith usa as migrações informadas (ex.: nos testes)
+func NewMigratorWith(db *gorm.DB, migrations []Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// Migrate aplica as migrações pendentes e prepara a busca textual
+func Migrate(db *gorm.DB) error {
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	if _, err := migrator.Up(); err != nil {
+		return err
+	}
+	return setupSearch(db)
+}
+
+// Up aplica as migrações pendentes em ordem e retorna as aplicadas
+func (m *Migrator) Up() ([]Migration, error) {
+	var applied []Migration
+	err := m.locked(func(conn *gorm.DB) error {
+		done, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+		if err := m.checkKnown(done); err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := done[migration.Version]; ok {
+				continue
+			}
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := execScript(tx, migration.Up); err != nil {
+					return err
+				}
+				return tx.Create(&schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now().UTC()}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverte as últimas steps migrações aplicadas, da mais nova para a mais antiga
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := m.locked(func(conn *gorm.DB) error {
+		done, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+		if err := m.checkKnown(done); err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := done[migration.Version]; !ok {
+				continue
+			}
+			if strings.TrimSpace(migration.Down) == "" {
+				return fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, ErrNoDownMigration)
+			}
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := execScript(tx, migration.Down); err != nil {
+					return err
+				}
+				return tx.Delete(&schemaMigration{}, migration.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status lista todas as migrações com o momento em que foram aplicadas
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	var status []MigrationStatus
+	err := m.locked(func(conn *gorm.DB) error {
+		done, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		status = make([]MigrationStatus, len(m.migrations))
+		for i, migration := range m.migrations {
+			status[i] = MigrationStatus{Migration: migration}
+			if record, ok := done[migration.Version]; ok {
+				appliedAt := record.AppliedAt
+				status[i].AppliedAt = &appliedAt
+			}
+		}
+		return nil
+	})
+	return status, err
+}
+
+// locked executa fn em uma única conexão, segurando o advisory lock no
+// PostgreSQL. O SQLite é usado apenas em memória (testes e desenvolvimento),
+// com uma instância por banco, e dispensa a trava
+func (m *Migrator) locked(fn func(conn *gorm.DB) error) error {
+	return m.db.Connection(func(conn *gorm.DB) error {
+		if conn.Dialector.Name() == "postgres" {
+			if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockKey).Error; err != nil {
+				return fmt.Errorf("acquire migration lock: %w", err)
+			}
+			defer conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockKey)
+		}
+
+		if err := conn.Exec(createSchemaMigrations).Error; err != nil {
+			return err
+		}
+		return fn(conn)
+	})
+}
+
+// checkKnown recusa bancos com versões aplicadas que este binário não conhece
+func (m *Migrator) checkKnown(done map[int]schemaMigration) error {
+	known := make(map[int]bool, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+	}
+	for version, record := range done {
+		if !known[version] {
+			return fmt.Errorf("%w: %04d_%s", ErrUnknownMigration, version, record.Name)
+		}
+	}
+	return nil
+}
+
+func appliedVersions(conn *gorm.DB) (map[int]schemaMigration, error) {
+	var records []schemaMigration
+	if err := conn.Order("version").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	done := make(map[int]schemaMigration, len(records))
+	for _, record := range records {
+		done[record.Version] = record
+	}
+	return done, nil
+}
+
+// execScript executa o script inteiro de uma vez; scripts só com comentários
+// (ex.: a variante vazia de um dialeto) são ignorados
+func execScript(tx *gorm.DB, script string) error {
+	if isBlankScript(script) {
+		return nil
+	}
+	return tx.Exec(script).Error
+}
+
+func isBlankScript(script string) bool {
+	for _, line := range strings.Split(script, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "--") {
+			return false
+		}
+	}
+	return true
+}
+
+var migrationNameCleaner = regexp.MustCompile(`[^a-z0-9]+`)
+
+// CreateMigration cria em dir os arquivos up e down de uma nova migração
+// para cada dialeto, com a próxima versão, e retorna os caminhos criados
+func CreateMigration(dir, name string) ([]string, error) {
+	name = strings.Trim(migrationNameCleaner.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return nil, errors.New("migration name must contain letters or digits")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	next := 1
+	for _, entry := range entries {
+		if match := migrationFileName.FindStringSubmatch(entry.Name()); match != nil {
+			if version, _ := strconv.Atoi(match[1]); version >= next {
+				next = version + 1
+			}
+		}
+	}
+
+	var paths []string
+	for _, dialect := range []string{"postgres", "sqlite"} {
+		for _, direction := range []string{"up", "down"} {
+			path := filepath.Join(dir, fmt.Sprintf("%04d_%s.%s.%s.sql", next, name, direction, dialect))
+			content := fmt.Sprintf("-- %04d_%s (%s, %s)\n", next, name, direction, dialect)
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				return paths, err
+			}
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,3 @@
+-- Tabela FTS5 criada por setupSearch, quando o driver tem suporte
+DROP TABLE IF EXISTS todos_fts;
+DROP TABLE IF EXISTS todos;
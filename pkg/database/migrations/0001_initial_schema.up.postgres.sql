@@ -0,0 +1,16 @@
+-- Esquema da versão que criava o banco com AutoMigrate: apenas a tabela
+-- todos. IF NOT EXISTS adota os bancos criados por ela, que já têm exatamente
+-- esta tabela; as demais mudanças vêm nas migrações seguintes
+
+CREATE TABLE IF NOT EXISTS todos (
+	id BIGSERIAL PRIMARY KEY,
+	title VARCHAR(255) NOT NULL,
+	description TEXT,
+	completed BOOLEAN DEFAULT false,
+	priority VARCHAR(20) DEFAULT 'medium',
+	due_date TIMESTAMPTZ,
+	created_at TIMESTAMPTZ,
+	updated_at TIMESTAMPTZ,
+	deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_todos_deleted_at ON todos (deleted_at);
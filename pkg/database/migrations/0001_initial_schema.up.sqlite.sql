@@ -0,0 +1,16 @@
+-- Esquema da versão que criava o banco com AutoMigrate: apenas a tabela
+-- todos. IF NOT EXISTS adota os bancos criados por ela, que já têm exatamente
+-- esta tabela; as demais mudanças vêm nas migrações seguintes
+
+CREATE TABLE IF NOT EXISTS todos (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	title TEXT NOT NULL,
+	description TEXT,
+	completed NUMERIC DEFAULT false,
+	priority TEXT DEFAULT 'medium',
+	due_date DATETIME,
+	created_at DATETIME,
+	updated_at DATETIME,
+	deleted_at DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_todos_deleted_at ON todos (deleted_at);
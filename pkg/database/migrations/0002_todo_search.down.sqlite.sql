@@ -0,0 +1,2 @@
+-- No SQLite a busca usa FTS5, que depende da tag de build do driver
+-- (sqlite_fts5); por isso a tabela é criada por setupSearch, após as migrações
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_todos_user_id;
+ALTER TABLE todos DROP COLUMN user_id;
+DROP TABLE users;
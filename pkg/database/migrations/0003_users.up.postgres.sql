@@ -0,0 +1,24 @@
+CREATE TABLE users (
+	id BIGSERIAL PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	email VARCHAR(255) NOT NULL,
+	password_hash TEXT NOT NULL,
+	created_at TIMESTAMPTZ,
+	updated_at TIMESTAMPTZ,
+	deleted_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX idx_users_email ON users (email);
+CREATE INDEX idx_users_deleted_at ON users (deleted_at);
+
+ALTER TABLE todos ADD COLUMN user_id BIGINT;
+CREATE INDEX idx_todos_user_id ON todos (user_id);
+
+-- Tarefas anteriores às contas passam para uma conta legada, criada só se
+-- houver tarefas. O hash inválido impede o login; para entregá-las a alguém,
+-- troque o e-mail e a senha da conta ou mova as tarefas
+INSERT INTO users (name, email, password_hash, created_at, updated_at)
+SELECT 'Legacy', 'legacy@todo-api.invalid', '!', NOW(), NOW()
+WHERE EXISTS (SELECT 1 FROM todos);
+UPDATE todos SET user_id = (SELECT id FROM users WHERE email = 'legacy@todo-api.invalid')
+WHERE user_id IS NULL;
+ALTER TABLE todos ALTER COLUMN user_id SET NOT NULL;
@@ -0,0 +1,23 @@
+CREATE TABLE users (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL,
+	email TEXT NOT NULL,
+	password_hash TEXT NOT NULL,
+	created_at DATETIME,
+	updated_at DATETIME,
+	deleted_at DATETIME
+);
+CREATE UNIQUE INDEX idx_users_email ON users (email);
+CREATE INDEX idx_users_deleted_at ON users (deleted_at);
+
+ALTER TABLE todos ADD COLUMN user_id INTEGER;
+CREATE INDEX idx_todos_user_id ON todos (user_id);
+
+-- Tarefas anteriores às contas passam para uma conta legada, criada só se
+-- houver tarefas. O hash inválido impede o login; para entregá-las a alguém,
+-- troque o e-mail e a senha da conta ou mova as tarefas
+INSERT INTO users (name, email, password_hash, created_at, updated_at)
+SELECT 'Legacy', 'legacy@todo-api.invalid', '!', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+WHERE EXISTS (SELECT 1 FROM todos);
+UPDATE todos SET user_id = (SELECT id FROM users WHERE email = 'legacy@todo-api.invalid')
+WHERE user_id IS NULL;
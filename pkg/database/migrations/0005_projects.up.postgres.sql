@@ -0,0 +1,15 @@
+CREATE TABLE projects (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL,
+	name VARCHAR(100) NOT NULL,
+	color VARCHAR(7) DEFAULT '#808080',
+	archived BOOLEAN DEFAULT false,
+	created_at TIMESTAMPTZ,
+	updated_at TIMESTAMPTZ,
+	deleted_at TIMESTAMPTZ
+);
+CREATE INDEX idx_projects_user_id ON projects (user_id);
+CREATE INDEX idx_projects_deleted_at ON projects (deleted_at);
+
+ALTER TABLE todos ADD COLUMN project_id BIGINT;
+CREATE INDEX idx_todos_project_id ON todos (project_id);
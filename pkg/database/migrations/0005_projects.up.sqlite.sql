@@ -0,0 +1,15 @@
+CREATE TABLE projects (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	name TEXT NOT NULL,
+	color TEXT DEFAULT '#808080',
+	archived NUMERIC DEFAULT false,
+	created_at DATETIME,
+	updated_at DATETIME,
+	deleted_at DATETIME
+);
+CREATE INDEX idx_projects_user_id ON projects (user_id);
+CREATE INDEX idx_projects_deleted_at ON projects (deleted_at);
+
+ALTER TABLE todos ADD COLUMN project_id INTEGER;
+CREATE INDEX idx_todos_project_id ON todos (project_id);
@@ -0,0 +1,15 @@
+CREATE TABLE tags (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL,
+	name VARCHAR(50) NOT NULL,
+	created_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX idx_tags_user_name ON tags (user_id, name);
+
+CREATE TABLE todo_tags (
+	todo_id BIGINT,
+	tag_id BIGINT,
+	PRIMARY KEY (todo_id, tag_id),
+	CONSTRAINT fk_todo_tags_todo FOREIGN KEY (todo_id) REFERENCES todos (id),
+	CONSTRAINT fk_todo_tags_tag FOREIGN KEY (tag_id) REFERENCES tags (id)
+);
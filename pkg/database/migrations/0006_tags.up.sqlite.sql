@@ -0,0 +1,15 @@
+CREATE TABLE tags (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	name TEXT NOT NULL,
+	created_at DATETIME
+);
+CREATE UNIQUE INDEX idx_tags_user_name ON tags (user_id, name);
+
+CREATE TABLE todo_tags (
+	todo_id INTEGER,
+	tag_id INTEGER,
+	PRIMARY KEY (todo_id, tag_id),
+	CONSTRAINT fk_todo_tags_todo FOREIGN KEY (todo_id) REFERENCES todos (id),
+	CONSTRAINT fk_todo_tags_tag FOREIGN KEY (tag_id) REFERENCES tags (id)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE todos ADD COLUMN parent_id INTEGER;
+CREATE INDEX idx_todos_parent_id ON todos (parent_id);
@@ -0,0 +1,6 @@
+ALTER TABLE todos DROP COLUMN recurrence_completed;
+ALTER TABLE todos DROP COLUMN recurrence_start;
+ALTER TABLE todos DROP COLUMN recurrence_from_completion;
+ALTER TABLE todos DROP COLUMN recurrence_exdates;
+ALTER TABLE todos DROP COLUMN recurrence_timezone;
+ALTER TABLE todos DROP COLUMN recurrence_rule;
@@ -0,0 +1,6 @@
+ALTER TABLE todos ADD COLUMN recurrence_rule VARCHAR(255);
+ALTER TABLE todos ADD COLUMN recurrence_timezone VARCHAR(64);
+ALTER TABLE todos ADD COLUMN recurrence_exdates TEXT;
+ALTER TABLE todos ADD COLUMN recurrence_from_completion BOOLEAN DEFAULT false;
+ALTER TABLE todos ADD COLUMN recurrence_start TIMESTAMPTZ;
+ALTER TABLE todos ADD COLUMN recurrence_completed BIGINT DEFAULT 0;
@@ -0,0 +1,6 @@
+ALTER TABLE todos ADD COLUMN recurrence_rule TEXT;
+ALTER TABLE todos ADD COLUMN recurrence_timezone TEXT;
+ALTER TABLE todos ADD COLUMN recurrence_exdates TEXT;
+ALTER TABLE todos ADD COLUMN recurrence_from_completion NUMERIC DEFAULT false;
+ALTER TABLE todos ADD COLUMN recurrence_start DATETIME;
+ALTER TABLE todos ADD COLUMN recurrence_completed INTEGER DEFAULT 0;
@@ -0,0 +1,19 @@
+CREATE TABLE reminders (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL,
+	todo_id BIGINT NOT NULL,
+	remind_at TIMESTAMPTZ,
+	minutes_before BIGINT,
+	fire_at TIMESTAMPTZ NOT NULL,
+	sent_at TIMESTAMPTZ,
+	failed_at TIMESTAMPTZ,
+	claimed_until TIMESTAMPTZ,
+	attempts BIGINT DEFAULT 0,
+	last_error VARCHAR(500),
+	created_at TIMESTAMPTZ,
+	updated_at TIMESTAMPTZ,
+	CONSTRAINT fk_reminders_todo FOREIGN KEY (todo_id) REFERENCES todos (id)
+);
+CREATE INDEX idx_reminders_user_id ON reminders (user_id);
+CREATE INDEX idx_reminders_todo_id ON reminders (todo_id);
+CREATE INDEX idx_reminders_fire_at ON reminders (fire_at);
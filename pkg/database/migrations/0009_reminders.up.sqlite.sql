@@ -0,0 +1,19 @@
+CREATE TABLE reminders (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	todo_id INTEGER NOT NULL,
+	remind_at DATETIME,
+	minutes_before INTEGER,
+	fire_at DATETIME NOT NULL,
+	sent_at DATETIME,
+	failed_at DATETIME,
+	claimed_until DATETIME,
+	attempts INTEGER DEFAULT 0,
+	last_error TEXT,
+	created_at DATETIME,
+	updated_at DATETIME,
+	CONSTRAINT fk_reminders_todo FOREIGN KEY (todo_id) REFERENCES todos (id)
+);
+CREATE INDEX idx_reminders_user_id ON reminders (user_id);
+CREATE INDEX idx_reminders_todo_id ON reminders (todo_id);
+CREATE INDEX idx_reminders_fire_at ON reminders (fire_at);
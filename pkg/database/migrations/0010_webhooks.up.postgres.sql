@@ -0,0 +1,42 @@
+CREATE TABLE webhooks (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL,
+	url VARCHAR(2048) NOT NULL,
+	secret VARCHAR(100) NOT NULL,
+	events VARCHAR(255) NOT NULL,
+	active BOOLEAN NOT NULL,
+	created_at TIMESTAMPTZ,
+	updated_at TIMESTAMPTZ
+);
+CREATE INDEX idx_webhooks_user_id ON webhooks (user_id);
+
+CREATE TABLE webhook_deliveries (
+	id BIGSERIAL PRIMARY KEY,
+	webhook_id BIGINT NOT NULL,
+	event VARCHAR(50) NOT NULL,
+	payload TEXT NOT NULL,
+	status VARCHAR(20) NOT NULL DEFAULT 'pending',
+	attempts BIGINT DEFAULT 0,
+	next_attempt_at TIMESTAMPTZ,
+	claimed_until TIMESTAMPTZ,
+	last_status_code BIGINT,
+	last_error VARCHAR(500),
+	delivered_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ,
+	updated_at TIMESTAMPTZ,
+	CONSTRAINT fk_webhook_deliveries_webhook FOREIGN KEY (webhook_id) REFERENCES webhooks (id)
+);
+CREATE INDEX idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id);
+CREATE INDEX idx_webhook_deliveries_status ON webhook_deliveries (status);
+CREATE INDEX idx_webhook_deliveries_next_attempt_at ON webhook_deliveries (next_attempt_at);
+
+CREATE TABLE webhook_attempts (
+	id BIGSERIAL PRIMARY KEY,
+	delivery_id BIGINT NOT NULL,
+	status_code BIGINT,
+	error VARCHAR(500),
+	duration_ms BIGINT,
+	created_at TIMESTAMPTZ,
+	CONSTRAINT fk_webhook_deliveries_attempt_log FOREIGN KEY (delivery_id) REFERENCES webhook_deliveries (id) ON DELETE CASCADE
+);
+CREATE INDEX idx_webhook_attempts_delivery_id ON webhook_attempts (delivery_id);
@@ -0,0 +1,14 @@
+CREATE TABLE todo_histories (
+	id BIGSERIAL PRIMARY KEY,
+	todo_id BIGINT NOT NULL,
+	revision BIGINT NOT NULL,
+	user_id BIGINT NOT NULL,
+	actor_id BIGINT NOT NULL,
+	operation VARCHAR(20) NOT NULL,
+	changes TEXT,
+	snapshot TEXT NOT NULL,
+	source_revision BIGINT,
+	created_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX idx_todo_histories_revision ON todo_histories (todo_id, revision);
+CREATE INDEX idx_todo_histories_user_id ON todo_histories (user_id);
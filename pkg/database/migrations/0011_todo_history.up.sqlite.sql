@@ -0,0 +1,14 @@
+CREATE TABLE todo_histories (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	todo_id INTEGER NOT NULL,
+	revision INTEGER NOT NULL,
+	user_id INTEGER NOT NULL,
+	actor_id INTEGER NOT NULL,
+	operation TEXT NOT NULL,
+	changes TEXT,
+	snapshot TEXT NOT NULL,
+	source_revision INTEGER,
+	created_at DATETIME
+);
+CREATE UNIQUE INDEX idx_todo_histories_revision ON todo_histories (todo_id, revision);
+CREATE INDEX idx_todo_histories_user_id ON todo_histories (user_id);
@@ -0,0 +1,11 @@
+CREATE TABLE calendar_feeds (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL,
+	prefix VARCHAR(16) NOT NULL,
+	token_hash VARCHAR(64) NOT NULL,
+	last_used_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ,
+	updated_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX idx_calendar_feeds_user_id ON calendar_feeds (user_id);
+CREATE UNIQUE INDEX idx_calendar_feeds_token_hash ON calendar_feeds (token_hash);
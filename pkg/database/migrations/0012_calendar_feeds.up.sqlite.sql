@@ -0,0 +1,11 @@
+CREATE TABLE calendar_feeds (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	prefix TEXT NOT NULL,
+	token_hash TEXT NOT NULL,
+	last_used_at DATETIME,
+	created_at DATETIME,
+	updated_at DATETIME
+);
+CREATE UNIQUE INDEX idx_calendar_feeds_user_id ON calendar_feeds (user_id);
+CREATE UNIQUE INDEX idx_calendar_feeds_token_hash ON calendar_feeds (token_hash);
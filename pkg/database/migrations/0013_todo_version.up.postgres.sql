@@ -0,0 +1,2 @@
+-- As tarefas existentes começam na versão 1
+ALTER TABLE todos ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,2 @@
+-- As tarefas existentes começam na versão 1
+ALTER TABLE todos ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
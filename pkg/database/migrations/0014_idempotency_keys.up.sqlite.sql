@@ -0,0 +1,16 @@
+CREATE TABLE idempotency_keys (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	idempotency_key TEXT NOT NULL,
+	request_hash TEXT NOT NULL,
+	completed NUMERIC NOT NULL DEFAULT false,
+	status_code INTEGER,
+	headers TEXT,
+	body TEXT,
+	locked_at DATETIME NOT NULL,
+	expires_at DATETIME NOT NULL,
+	created_at DATETIME,
+	updated_at DATETIME
+);
+CREATE UNIQUE INDEX idx_idempotency_keys_user_key ON idempotency_keys (user_id, idempotency_key);
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
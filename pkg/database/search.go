@@ -0,0 +1,49 @@
+package database
+
+import (
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Tabela FTS5 com conteúdo externo, sincronizada com todos por triggers
+var sqliteSearchStatements = []string{
+	`CREATE VIRTUAL TABLE todos_fts USING fts5(title, description, content='todos', content_rowid='id')`,
+	`CREATE TRIGGER todos_fts_insert AFTER INSERT ON todos BEGIN
+		INSERT INTO todos_fts(rowid, title, description) VALUES (new.id, new.title, new.description);
+	END`,
+	`CREATE TRIGGER todos_fts_delete AFTER DELETE ON todos BEGIN
+		INSERT INTO todos_fts(todos_fts, rowid, title, description) VALUES ('delete', old.id, old.title, old.description);
+	END`,
+	`CREATE TRIGGER todos_fts_update AFTER UPDATE ON todos BEGIN
+		INSERT INTO todos_fts(todos_fts, rowid, title, description) VALUES ('delete', old.id, old.title, old.description);
+		INSERT INTO todos_fts(rowid, title, description) VALUES (new.id, new.title, new.description);
+	END`,
+	`INSERT INTO todos_fts(todos_fts) VALUES ('rebuild')`,
+}
+
+// setupSearch prepara a busca textual do SQLite. No PostgreSQL a coluna
+// tsvector vem da migração 0002_todo_search; no SQLite a tabela FTS5 fica
+// fora das migrações porque depende da tag de build do driver
+func setupSearch(db *gorm.DB) error {
+	if db.Dialector.Name() != "sqlite" || db.Migrator().HasTable("todos_fts") {
+		return nil
+	}
+
+	// FTS5 só está disponível quando o driver é compilado com a tag sqlite_fts5
+	if err := db.Exec(sqliteSearchStatements[0]).Error; err != nil {
+		if strings.Contains(err.Error(), "no such module") {
+			log.Println("SQLite built without FTS5, full-text search will fall back to LIKE")
+			return nil
+		}
+		return err
+	}
+
+	for _, statement := range sqliteSearchStatements[1:] {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
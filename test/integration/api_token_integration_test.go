@@ -0,0 +1,77 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/dto"
+)
+
+type APITokenIntegrationTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+}
+
+func (suite *APITokenIntegrationTestSuite) SetupSuite() {
+	helper, err := NewTestHelper()
+	suite.Require().NoError(err)
+	suite.helper = helper
+}
+
+func (suite *APITokenIntegrationTestSuite) createToken(scopes ...string) dto.CreatedAPITokenResponse {
+	httpReq, _ := suite.helper.CreateTodoRequest("POST", "/api/v1/tokens", dto.CreateAPITokenRequest{Name: "script", Scopes: scopes})
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+
+	response, err := suite.helper.ParseSuccessResponse(recorder)
+	suite.Require().NoError(err)
+
+	data, _ := json.Marshal(response.Data)
+	var token dto.CreatedAPITokenResponse
+	suite.Require().NoError(json.Unmarshal(data, &token))
+	return token
+}
+
+func (suite *APITokenIntegrationTestSuite) withToken(method, url, token string, body interface{}) int {
+	httpReq, _ := suite.helper.CreateTodoRequest(method, url, body)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	return suite.helper.ExecuteRequest(httpReq).Code
+}
+
+func (suite *APITokenIntegrationTestSuite) TestScopes() {
+	readOnly := suite.createToken("todos:read")
+
+	assert.Equal(suite.T(), http.StatusOK, suite.withToken("GET", "/api/v1/todos", readOnly.Token, nil))
+	assert.Equal(suite.T(), http.StatusForbidden, suite.withToken("POST", "/api/v1/todos", readOnly.Token, dto.CreateTodoRequest{Title: "x"}))
+
+	// Tokens não podem gerenciar outros tokens
+	assert.Equal(suite.T(), http.StatusForbidden, suite.withToken("GET", "/api/v1/tokens", readOnly.Token, nil))
+
+	readWrite := suite.createToken("todos:read", "todos:write")
+	assert.Equal(suite.T(), http.StatusCreated, suite.withToken("POST", "/api/v1/todos", readWrite.Token, dto.CreateTodoRequest{Title: "x"}))
+}
+
+func (suite *APITokenIntegrationTestSuite) TestRevoke() {
+	token := suite.createToken("todos:read")
+	assert.Equal(suite.T(), http.StatusOK, suite.withToken("GET", "/api/v1/todos", token.Token, nil))
+
+	httpReq, _ := suite.helper.CreateTodoRequest("DELETE", fmt.Sprintf("/api/v1/tokens/%d", token.ID), nil)
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	assert.Equal(suite.T(), http.StatusOK, recorder.Code)
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, suite.withToken("GET", "/api/v1/todos", token.Token, nil))
+}
+
+func (suite *APITokenIntegrationTestSuite) TestInvalidScope() {
+	httpReq, _ := suite.helper.CreateTodoRequest("POST", "/api/v1/tokens", dto.CreateAPITokenRequest{Name: "x", Scopes: []string{"tokens:manage"}})
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	assert.Equal(suite.T(), http.StatusBadRequest, recorder.Code)
+}
+
+func TestAPITokenIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(APITokenIntegrationTestSuite))
+}
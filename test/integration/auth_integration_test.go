@@ -0,0 +1,71 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/dto"
+)
+
+type AuthIntegrationTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+}
+
+func (suite *AuthIntegrationTestSuite) SetupSuite() {
+	helper, err := NewTestHelper()
+	suite.Require().NoError(err)
+	suite.helper = helper
+}
+
+func (suite *AuthIntegrationTestSuite) TestRegisterLoginAndMe() {
+	register := dto.RegisterRequest{Name: "Ana", Email: "ana@example.com", Password: "password123"}
+	httpReq, _ := suite.helper.CreateTodoRequest("POST", "/api/v1/auth/register", register)
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	assert.Equal(suite.T(), http.StatusCreated, recorder.Code)
+
+	// E-mail duplicado
+	httpReq, _ = suite.helper.CreateTodoRequest("POST", "/api/v1/auth/register", register)
+	recorder = suite.helper.ExecuteRequest(httpReq)
+	assert.Equal(suite.T(), http.StatusConflict, recorder.Code)
+
+	// Senha errada
+	login := dto.LoginRequest{Email: "ana@example.com", Password: "wrong-password"}
+	httpReq, _ = suite.helper.CreateTodoRequest("POST", "/api/v1/auth/login", login)
+	recorder = suite.helper.ExecuteRequest(httpReq)
+	assert.Equal(suite.T(), http.StatusUnauthorized, recorder.Code)
+
+	login.Password = "password123"
+	httpReq, _ = suite.helper.CreateTodoRequest("POST", "/api/v1/auth/login", login)
+	recorder = suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+
+	response, err := suite.helper.ParseSuccessResponse(recorder)
+	suite.Require().NoError(err)
+	tokenData, _ := json.Marshal(response.Data)
+	var tokens dto.TokenResponse
+	suite.Require().NoError(json.Unmarshal(tokenData, &tokens))
+
+	httpReq, _ = suite.helper.CreateTodoRequest("GET", "/api/v1/auth/me", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+	recorder = suite.helper.ExecuteRequest(httpReq)
+	assert.Equal(suite.T(), http.StatusOK, recorder.Code)
+	assert.Contains(suite.T(), recorder.Body.String(), "ana@example.com")
+
+	// O refresh token não dá acesso às rotas protegidas
+	httpReq, _ = suite.helper.CreateTodoRequest("GET", "/api/v1/auth/me", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+tokens.RefreshToken)
+	recorder = suite.helper.ExecuteRequest(httpReq)
+	assert.Equal(suite.T(), http.StatusUnauthorized, recorder.Code)
+
+	httpReq, _ = suite.helper.CreateTodoRequest("POST", "/api/v1/auth/refresh", dto.RefreshRequest{RefreshToken: tokens.RefreshToken})
+	recorder = suite.helper.ExecuteRequest(httpReq)
+	assert.Equal(suite.T(), http.StatusOK, recorder.Code)
+}
+
+func TestAuthIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthIntegrationTestSuite))
+}
@@ -0,0 +1,150 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+)
+
+type BulkIntegrationTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+}
+
+func (suite *BulkIntegrationTestSuite) SetupSuite() {
+	helper, err := NewTestHelper()
+	suite.Require().NoError(err)
+	suite.helper = helper
+}
+
+func (suite *BulkIntegrationTestSuite) TearDownTest() {
+	suite.helper.CleanDatabase()
+}
+
+func (suite *BulkIntegrationTestSuite) bulk(req dto.BulkRequest) dto.BulkResponse {
+	httpReq, _ := suite.helper.CreateTodoRequest("POST", "/api/v1/todos/bulk", req)
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+
+	response, err := suite.helper.ParseSuccessResponse(recorder)
+	suite.Require().NoError(err)
+	data, _ := json.Marshal(response.Data)
+	var bulk dto.BulkResponse
+	suite.Require().NoError(json.Unmarshal(data, &bulk))
+	return bulk
+}
+
+func (suite *BulkIntegrationTestSuite) countTodos(title string) int64 {
+	var count int64
+	suite.helper.DB.Model(&entity.Todo{}).Where("title = ?", title).Count(&count)
+	return count
+}
+
+func (suite *BulkIntegrationTestSuite) TestAtomicSuccess() {
+	project := &entity.Project{UserID: suite.helper.UserID, Name: "Work"}
+	suite.Require().NoError(suite.helper.DB.Create(project).Error)
+	done := &entity.Todo{Title: "Finish"}
+	gone := &entity.Todo{Title: "Obsolete"}
+	moved := &entity.Todo{Title: "Move me"}
+	for _, todo := range []*entity.Todo{done, gone, moved} {
+		suite.Require().NoError(suite.helper.CreateTodo(todo))
+	}
+
+	bulk := suite.bulk(dto.BulkRequest{Operations: []dto.BulkOperation{
+		{Op: "create", Data: json.RawMessage(`{"title": "New", "priority": "high"}`)},
+		{Op: "update", ID: done.ID, Data: json.RawMessage(`{"title": "Finished"}`)},
+		{Op: "complete", ID: done.ID},
+		{Op: "delete", ID: gone.ID},
+		{Op: "move", ID: moved.ID, Data: json.RawMessage(fmt.Sprintf(`{"project_id": %d}`, project.ID))},
+	}})
+
+	assert.Equal(suite.T(), "atomic", bulk.Mode)
+	assert.True(suite.T(), bulk.Committed)
+	assert.Equal(suite.T(), 5, bulk.Succeeded)
+	suite.Require().Len(bulk.Results, 5)
+	assert.Equal(suite.T(), http.StatusCreated, bulk.Results[0].Status)
+	assert.NotZero(suite.T(), bulk.Results[0].ID)
+	assert.True(suite.T(), bulk.Results[2].Data.Completed)
+	assert.Equal(suite.T(), "Finished", bulk.Results[2].Data.Title)
+	assert.Nil(suite.T(), bulk.Results[3].Data)
+	suite.Require().NotNil(bulk.Results[4].Data.ProjectID)
+	assert.Equal(suite.T(), project.ID, *bulk.Results[4].Data.ProjectID)
+
+	assert.Equal(suite.T(), int64(1), suite.countTodos("New"))
+	assert.Zero(suite.T(), suite.countTodos("Obsolete"))
+}
+
+func (suite *BulkIntegrationTestSuite) TestAtomicFailureRollsBack() {
+	todo := &entity.Todo{Title: "Keep"}
+	suite.Require().NoError(suite.helper.CreateTodo(todo))
+
+	bulk := suite.bulk(dto.BulkRequest{Mode: "atomic", Operations: []dto.BulkOperation{
+		{Op: "create", Data: json.RawMessage(`{"title": "Temporary"}`)},
+		{Op: "delete", ID: todo.ID},
+		{Op: "complete", ID: 9999},
+		{Op: "create", Data: json.RawMessage(`{"title": "Never"}`)},
+	}})
+
+	assert.False(suite.T(), bulk.Committed)
+	assert.Zero(suite.T(), bulk.Succeeded)
+	assert.Equal(suite.T(), 4, bulk.Failed)
+	statuses := []int{bulk.Results[0].Status, bulk.Results[1].Status, bulk.Results[2].Status, bulk.Results[3].Status}
+	assert.Equal(suite.T(), []int{http.StatusFailedDependency, http.StatusFailedDependency, http.StatusNotFound, http.StatusFailedDependency}, statuses)
+	suite.Require().NotNil(bulk.Results[2].Error)
+	assert.Equal(suite.T(), http.StatusNotFound, bulk.Results[2].Error.Status)
+
+	assert.Zero(suite.T(), suite.countTodos("Temporary"))
+	assert.Equal(suite.T(), int64(1), suite.countTodos("Keep"))
+
+	// Dados inválidos também impedem o lote inteiro
+	bulk = suite.bulk(dto.BulkRequest{Operations: []dto.BulkOperation{
+		{Op: "create", Data: json.RawMessage(`{"title": "Valid"}`)},
+		{Op: "create", Data: json.RawMessage(`{"priority": "urgent"}`)},
+	}})
+	assert.False(suite.T(), bulk.Committed)
+	assert.Equal(suite.T(), http.StatusBadRequest, bulk.Results[1].Status)
+	assert.Zero(suite.T(), suite.countTodos("Valid"))
+}
+
+func (suite *BulkIntegrationTestSuite) TestBestEffort() {
+	bulk := suite.bulk(dto.BulkRequest{Mode: "best_effort", Operations: []dto.BulkOperation{
+		{Op: "create", Data: json.RawMessage(`{"title": "Saved"}`)},
+		{Op: "update", ID: 9999, Data: json.RawMessage(`{"title": "Missing"}`)},
+		{Op: "move", ID: 9999, Data: json.RawMessage(`{}`)},
+		{Op: "complete"},
+		{Op: "create", Data: json.RawMessage(`{"title": "Also saved", "project_id": 9999}`)},
+	}})
+
+	assert.True(suite.T(), bulk.Committed)
+	assert.Equal(suite.T(), 1, bulk.Succeeded)
+	assert.Equal(suite.T(), 4, bulk.Failed)
+	statuses := make([]int, len(bulk.Results))
+	for i, result := range bulk.Results {
+		statuses[i] = result.Status
+	}
+	assert.Equal(suite.T(), []int{http.StatusCreated, http.StatusNotFound, http.StatusBadRequest, http.StatusBadRequest, http.StatusBadRequest}, statuses)
+
+	assert.Equal(suite.T(), int64(1), suite.countTodos("Saved"))
+	assert.Zero(suite.T(), suite.countTodos("Also saved"))
+}
+
+func (suite *BulkIntegrationTestSuite) TestInvalidRequest() {
+	for _, body := range []interface{}{
+		dto.BulkRequest{},
+		dto.BulkRequest{Mode: "sometimes", Operations: []dto.BulkOperation{{Op: "complete", ID: 1}}},
+		dto.BulkRequest{Operations: []dto.BulkOperation{{Op: "archive", ID: 1}}},
+	} {
+		httpReq, _ := suite.helper.CreateTodoRequest("POST", "/api/v1/todos/bulk", body)
+		assert.Equal(suite.T(), http.StatusBadRequest, suite.helper.ExecuteRequest(httpReq).Code)
+	}
+}
+
+func TestBulkIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(BulkIntegrationTestSuite))
+}
@@ -0,0 +1,165 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+)
+
+type CalendarIntegrationTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+}
+
+func (suite *CalendarIntegrationTestSuite) SetupSuite() {
+	helper, err := NewTestHelper()
+	suite.Require().NoError(err)
+	suite.helper = helper
+}
+
+func (suite *CalendarIntegrationTestSuite) TearDownTest() {
+	suite.helper.CleanDatabase()
+}
+
+// rotate cria um novo link do feed e devolve o caminho público
+func (suite *CalendarIntegrationTestSuite) rotate() string {
+	httpReq, _ := suite.helper.CreateTodoRequest("POST", "/api/v1/calendar/feed", nil)
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+
+	response, err := suite.helper.ParseSuccessResponse(recorder)
+	suite.Require().NoError(err)
+	data, _ := json.Marshal(response.Data)
+	var feed dto.CreatedCalendarFeedResponse
+	suite.Require().NoError(json.Unmarshal(data, &feed))
+	suite.True(strings.HasPrefix(feed.Token, "cal_"))
+	suite.True(strings.HasPrefix(feed.Token, feed.Prefix))
+
+	link, err := url.Parse(feed.URL)
+	suite.Require().NoError(err)
+	suite.Equal("/api/v1/calendar/"+feed.Token+".ics", link.Path)
+	return link.Path
+}
+
+// fetch busca o feed sem o header Authorization, como um aplicativo de calendário
+func (suite *CalendarIntegrationTestSuite) fetch(path, ifNoneMatch string) *httptest.ResponseRecorder {
+	httpReq := httptest.NewRequest("GET", path, nil)
+	if ifNoneMatch != "" {
+		httpReq.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	return suite.helper.ExecuteRequest(httpReq)
+}
+
+func (suite *CalendarIntegrationTestSuite) TestFeed_ServesTodosWithDueDate() {
+	due := time.Date(2030, time.May, 20, 0, 0, 0, 0, time.UTC)
+	suite.Require().NoError(suite.helper.CreateTodo(&entity.Todo{Title: "Renew passport", Priority: "high", DueDate: &due}))
+	suite.Require().NoError(suite.helper.CreateTodo(&entity.Todo{Title: "Someday", Priority: "low"}))
+
+	path := suite.rotate()
+	recorder := suite.fetch(path, "")
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+	assert.Equal(suite.T(), "text/calendar; charset=utf-8", recorder.Header().Get("Content-Type"))
+
+	body := recorder.Body.String()
+	suite.True(strings.HasPrefix(body, "BEGIN:VCALENDAR\r\n"))
+	suite.Equal(1, strings.Count(body, "BEGIN:VTODO"))
+	suite.Contains(body, "SUMMARY:Renew passport\r\n")
+	suite.Contains(body, "DUE;VALUE=DATE:20300520\r\n")
+	suite.Contains(body, "PRIORITY:1\r\n")
+	suite.NotContains(body, "Someday")
+	suite.NotContains(body, "BEGIN:VEVENT")
+
+	recorder = suite.fetch(path+"?events=true", "")
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	suite.Contains(recorder.Body.String(), "DTSTART;VALUE=DATE:20300520\r\n")
+
+	var feed entity.CalendarFeed
+	suite.Require().NoError(suite.helper.DB.Where("user_id = ?", suite.helper.UserID).First(&feed).Error)
+	suite.NotNil(feed.LastUsedAt)
+}
+
+func (suite *CalendarIntegrationTestSuite) TestFeed_ETag() {
+	due := time.Date(2030, time.May, 20, 9, 0, 0, 0, time.UTC)
+	todo := &entity.Todo{Title: "Dentist", Priority: "medium", DueDate: &due}
+	suite.Require().NoError(suite.helper.CreateTodo(todo))
+	path := suite.rotate()
+
+	first := suite.fetch(path, "")
+	suite.Require().Equal(http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	suite.Require().NotEmpty(etag)
+
+	cached := suite.fetch(path, etag)
+	suite.Equal(http.StatusNotModified, cached.Code)
+	suite.Empty(cached.Body.String())
+	suite.Equal(http.StatusNotModified, suite.fetch(path, `"other", W/`+etag).Code)
+
+	// Qualquer mudança nas tarefas gera um novo ETag
+	todo.Title = "Dentist appointment"
+	suite.Require().NoError(suite.helper.DB.Save(todo).Error)
+	changed := suite.fetch(path, etag)
+	suite.Equal(http.StatusOK, changed.Code)
+	suite.NotEqual(etag, changed.Header().Get("ETag"))
+	suite.Contains(changed.Body.String(), "SUMMARY:Dentist appointment\r\n")
+}
+
+func (suite *CalendarIntegrationTestSuite) TestRotate_ForwardedProto() {
+	for proto, scheme := range map[string]string{"https": "https", "HTTPS": "https", "javascript": "http", "https://evil.example.com/#": "http"} {
+		httpReq, _ := suite.helper.CreateTodoRequest("POST", "/api/v1/calendar/feed", nil)
+		httpReq.Header.Set("X-Forwarded-Proto", proto)
+		recorder := suite.helper.ExecuteRequest(httpReq)
+		suite.Require().Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+
+		response, err := suite.helper.ParseSuccessResponse(recorder)
+		suite.Require().NoError(err)
+		data, _ := json.Marshal(response.Data)
+		var feed dto.CreatedCalendarFeedResponse
+		suite.Require().NoError(json.Unmarshal(data, &feed))
+		// Só http e https do proxy confiável são aceitos
+		assert.True(suite.T(), strings.HasPrefix(feed.URL, scheme+"://"+httpReq.Host+"/"), "%s: %s", proto, feed.URL)
+	}
+}
+
+func (suite *CalendarIntegrationTestSuite) TestRotate_InvalidatesPreviousLink() {
+	oldPath := suite.rotate()
+	newPath := suite.rotate()
+
+	suite.Equal(http.StatusNotFound, suite.fetch(oldPath, "").Code)
+	suite.Equal(http.StatusOK, suite.fetch(newPath, "").Code)
+}
+
+func (suite *CalendarIntegrationTestSuite) TestGetAndDisable() {
+	httpReq, _ := suite.helper.CreateTodoRequest("GET", "/api/v1/calendar/feed", nil)
+	suite.Equal(http.StatusNotFound, suite.helper.ExecuteRequest(httpReq).Code)
+
+	path := suite.rotate()
+
+	httpReq, _ = suite.helper.CreateTodoRequest("GET", "/api/v1/calendar/feed", nil)
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	suite.NotContains(recorder.Body.String(), "token")
+
+	httpReq, _ = suite.helper.CreateTodoRequest("DELETE", "/api/v1/calendar/feed", nil)
+	suite.Equal(http.StatusOK, suite.helper.ExecuteRequest(httpReq).Code)
+	suite.Equal(http.StatusNotFound, suite.fetch(path, "").Code)
+
+	httpReq, _ = suite.helper.CreateTodoRequest("DELETE", "/api/v1/calendar/feed", nil)
+	suite.Equal(http.StatusNotFound, suite.helper.ExecuteRequest(httpReq).Code)
+}
+
+func (suite *CalendarIntegrationTestSuite) TestFeed_UnknownToken() {
+	suite.Equal(http.StatusNotFound, suite.fetch("/api/v1/calendar/cal_unknown.ics", "").Code)
+}
+
+func TestCalendarIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(CalendarIntegrationTestSuite))
+}
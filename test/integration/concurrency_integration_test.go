@@ -0,0 +1,99 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+)
+
+type ConcurrencyIntegrationTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+}
+
+func (suite *ConcurrencyIntegrationTestSuite) SetupSuite() {
+	helper, err := NewTestHelper()
+	suite.Require().NoError(err)
+	suite.helper = helper
+}
+
+func (suite *ConcurrencyIntegrationTestSuite) TearDownTest() {
+	suite.helper.CleanDatabase()
+}
+
+func (suite *ConcurrencyIntegrationTestSuite) request(method, url, ifMatch string, body interface{}) *httptest.ResponseRecorder {
+	httpReq, _ := suite.helper.CreateTodoRequest(method, url, body)
+	if ifMatch != "" {
+		httpReq.Header.Set("If-Match", ifMatch)
+	}
+	return suite.helper.ExecuteRequest(httpReq)
+}
+
+func (suite *ConcurrencyIntegrationTestSuite) createTodo() *entity.Todo {
+	todo := &entity.Todo{Title: "Shared", Priority: "medium"}
+	suite.Require().NoError(suite.helper.CreateTodo(todo))
+	return todo
+}
+
+func (suite *ConcurrencyIntegrationTestSuite) TestGetReturnsETag() {
+	todo := suite.createTodo()
+
+	recorder := suite.request("GET", fmt.Sprintf("/api/v1/todos/%d", todo.ID), "", nil)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	suite.Equal(`"1"`, recorder.Header().Get("ETag"))
+	suite.Contains(recorder.Body.String(), `"version":1`)
+}
+
+func (suite *ConcurrencyIntegrationTestSuite) TestUpdate_IfMatch() {
+	todo := suite.createTodo()
+	url := fmt.Sprintf("/api/v1/todos/%d", todo.ID)
+	first, second := "First editor", "Second editor"
+
+	recorder := suite.request("PUT", url, `"1"`, dto.UpdateTodoRequest{Title: &first})
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+	suite.Equal(`"2"`, recorder.Header().Get("ETag"))
+
+	// O segundo editor ainda tem a versão 1 e não sobrescreve a alteração
+	recorder = suite.request("PUT", url, `"1"`, dto.UpdateTodoRequest{Title: &second})
+	suite.Equal(http.StatusPreconditionFailed, recorder.Code, recorder.Body.String())
+
+	recorder = suite.request("GET", url, "", nil)
+	suite.Contains(recorder.Body.String(), first)
+
+	// Sem If-Match ou com "*" não há pré-condição
+	suite.Equal(http.StatusOK, suite.request("PUT", url, "*", dto.UpdateTodoRequest{Title: &second}).Code)
+	suite.Equal(http.StatusOK, suite.request("PUT", url, "", dto.UpdateTodoRequest{Title: &first}).Code)
+}
+
+func (suite *ConcurrencyIntegrationTestSuite) TestCompleteAndDelete_IfMatch() {
+	todo := suite.createTodo()
+	url := fmt.Sprintf("/api/v1/todos/%d", todo.ID)
+
+	suite.Equal(http.StatusPreconditionFailed, suite.request("PATCH", url+"/complete", `"7"`, nil).Code)
+	recorder := suite.request("PATCH", url+"/complete", `"1"`, nil)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	suite.Equal(`"2"`, recorder.Header().Get("ETag"))
+
+	suite.Equal(http.StatusPreconditionFailed, suite.request("DELETE", url, `"1"`, nil).Code)
+	suite.Equal(http.StatusPreconditionFailed, suite.request("DELETE", url+"?permanent=true", `"1"`, nil).Code)
+	suite.Equal(http.StatusOK, suite.request("DELETE", url, `"2"`, nil).Code)
+}
+
+func (suite *ConcurrencyIntegrationTestSuite) TestInvalidIfMatch() {
+	todo := suite.createTodo()
+	url := fmt.Sprintf("/api/v1/todos/%d", todo.ID)
+
+	suite.Equal(http.StatusBadRequest, suite.request("DELETE", url, "1", nil).Code)
+	suite.Equal(http.StatusBadRequest, suite.request("DELETE", url, `"1", "2"`, nil).Code)
+	// ETags fracos nunca atendem ao If-Match
+	suite.Equal(http.StatusPreconditionFailed, suite.request("DELETE", url, `W/"1"`, nil).Code)
+}
+
+func TestConcurrencyIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(ConcurrencyIntegrationTestSuite))
+}
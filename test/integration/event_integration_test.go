@@ -0,0 +1,172 @@
+package integration
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/event"
+)
+
+// sseEvent é um evento lido do stream
+type sseEvent struct {
+	ID   string
+	Type string
+	Data string
+}
+
+type EventIntegrationTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	server *httptest.Server
+}
+
+func (suite *EventIntegrationTestSuite) SetupSuite() {
+	helper, err := NewTestHelper()
+	suite.Require().NoError(err)
+	suite.helper = helper
+	suite.server = httptest.NewServer(helper.Router)
+}
+
+func (suite *EventIntegrationTestSuite) TearDownSuite() {
+	suite.server.Close()
+}
+
+func (suite *EventIntegrationTestSuite) TearDownTest() {
+	suite.helper.CleanDatabase()
+}
+
+// stream abre o stream de eventos e repassa cada evento lido para o canal
+// retornado. A conexão é fechada no fim do teste
+func (suite *EventIntegrationTestSuite) stream(token, lastEventID string) <-chan sseEvent {
+	req, err := http.NewRequest("GET", suite.server.URL+"/api/v1/events", nil)
+	suite.Require().NoError(err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	suite.Require().Equal("text/event-stream", resp.Header.Get("Content-Type"))
+	suite.T().Cleanup(func() { resp.Body.Close() })
+
+	events := make(chan sseEvent, 16)
+	go func() {
+		defer close(events)
+		scanner := bufio.NewScanner(resp.Body)
+		var current sseEvent
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case line == "":
+				if current.Type != "" {
+					events <- current
+				}
+				current = sseEvent{}
+			case strings.HasPrefix(line, "id: "):
+				current.ID = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				current.Type = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				current.Data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+	return events
+}
+
+func (suite *EventIntegrationTestSuite) next(events <-chan sseEvent) sseEvent {
+	select {
+	case e, ok := <-events:
+		suite.Require().True(ok, "stream closed")
+		return e
+	case <-time.After(2 * time.Second):
+		suite.FailNow("timed out waiting for event")
+		return sseEvent{}
+	}
+}
+
+func (suite *EventIntegrationTestSuite) createTodo(token, title string) dto.TodoResponse {
+	httpReq, _ := suite.helper.CreateTodoRequest("POST", "/api/v1/todos", dto.CreateTodoRequest{Title: title})
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+
+	response, err := suite.helper.ParseSuccessResponse(recorder)
+	suite.Require().NoError(err)
+	data, _ := json.Marshal(response.Data)
+	var todo dto.TodoResponse
+	suite.Require().NoError(json.Unmarshal(data, &todo))
+	return todo
+}
+
+func (suite *EventIntegrationTestSuite) TestStreamsOwnTodoChanges() {
+	_, otherToken, err := suite.helper.RegisterUser("events-other@example.com")
+	suite.Require().NoError(err)
+
+	events := suite.stream(suite.helper.AccessToken, "")
+
+	// Tarefas de outro usuário não aparecem no stream
+	suite.createTodo(otherToken, "Not mine")
+	todo := suite.createTodo(suite.helper.AccessToken, "Mine")
+
+	created := suite.next(events)
+	assert.Equal(suite.T(), event.TodoCreated, created.Type)
+	assert.NotEmpty(suite.T(), created.ID)
+
+	var payload event.Event
+	suite.Require().NoError(json.Unmarshal([]byte(created.Data), &payload))
+	assert.Equal(suite.T(), todo.ID, payload.TodoID)
+	assert.Equal(suite.T(), "Mine", payload.Todo.Title)
+
+	httpReq, _ := suite.helper.CreateTodoRequest("PATCH", fmt.Sprintf("/api/v1/todos/%d/complete", todo.ID), nil)
+	suite.Require().Equal(http.StatusOK, suite.helper.ExecuteRequest(httpReq).Code)
+	assert.Equal(suite.T(), event.TodoCompleted, suite.next(events).Type)
+
+	httpReq, _ = suite.helper.CreateTodoRequest("DELETE", fmt.Sprintf("/api/v1/todos/%d", todo.ID), nil)
+	suite.Require().Equal(http.StatusOK, suite.helper.ExecuteRequest(httpReq).Code)
+	assert.Equal(suite.T(), event.TodoDeleted, suite.next(events).Type)
+}
+
+func (suite *EventIntegrationTestSuite) TestResumesFromLastEventID() {
+	events := suite.stream(suite.helper.AccessToken, "")
+	suite.createTodo(suite.helper.AccessToken, "First")
+	first := suite.next(events)
+
+	// Eventos publicados enquanto o cliente estava desconectado
+	suite.createTodo(suite.helper.AccessToken, "Second")
+	suite.createTodo(suite.helper.AccessToken, "Third")
+
+	resumed := suite.stream(suite.helper.AccessToken, first.ID)
+	var titles []string
+	for i := 0; i < 2; i++ {
+		var payload event.Event
+		suite.Require().NoError(json.Unmarshal([]byte(suite.next(resumed).Data), &payload))
+		titles = append(titles, payload.Todo.Title)
+	}
+	assert.Equal(suite.T(), []string{"Second", "Third"}, titles)
+
+	// Um ID desconhecido pede ao cliente que recarregue o estado
+	reset := suite.stream(suite.helper.AccessToken, "999999")
+	assert.Equal(suite.T(), "reset", suite.next(reset).Type)
+}
+
+func (suite *EventIntegrationTestSuite) TestRejectsInvalidLastEventID() {
+	httpReq, _ := suite.helper.CreateTodoRequest("GET", "/api/v1/events", nil)
+	httpReq.Header.Set("Last-Event-ID", "abc")
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.helper.ExecuteRequest(httpReq).Code)
+}
+
+func TestEventIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(EventIntegrationTestSuite))
+}
@@ -0,0 +1,156 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+)
+
+type IdempotencyIntegrationTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+}
+
+func (suite *IdempotencyIntegrationTestSuite) SetupSuite() {
+	helper, err := NewTestHelper()
+	suite.Require().NoError(err)
+	suite.helper = helper
+}
+
+func (suite *IdempotencyIntegrationTestSuite) TearDownTest() {
+	suite.helper.CleanDatabase()
+}
+
+func (suite *IdempotencyIntegrationTestSuite) create(key, title string) *httptest.ResponseRecorder {
+	httpReq, _ := suite.helper.CreateTodoRequest("POST", "/api/v1/todos", dto.CreateTodoRequest{Title: title, Priority: "low"})
+	if key != "" {
+		httpReq.Header.Set("Idempotency-Key", key)
+	}
+	return suite.helper.ExecuteRequest(httpReq)
+}
+
+func (suite *IdempotencyIntegrationTestSuite) countTodos() int64 {
+	var count int64
+	suite.helper.DB.Model(&entity.Todo{}).Count(&count)
+	return count
+}
+
+func (suite *IdempotencyIntegrationTestSuite) TestRetryReplaysResponse() {
+	first := suite.create("create-1", "Buy milk")
+	suite.Require().Equal(http.StatusCreated, first.Code, first.Body.String())
+	suite.Empty(first.Header().Get("Idempotent-Replayed"))
+
+	retry := suite.create("create-1", "Buy milk")
+	suite.Equal(http.StatusCreated, retry.Code)
+	suite.Equal("true", retry.Header().Get("Idempotent-Replayed"))
+	suite.Equal(first.Body.String(), retry.Body.String())
+	suite.Equal(first.Header().Get("ETag"), retry.Header().Get("ETag"))
+	suite.Equal(first.Header().Get("Content-Type"), retry.Header().Get("Content-Type"))
+	suite.Equal(int64(1), suite.countTodos())
+
+	// Sem o header cada requisição cria uma tarefa
+	suite.Equal(http.StatusCreated, suite.create("", "Buy milk").Code)
+	suite.Equal(http.StatusCreated, suite.create("", "Buy milk").Code)
+	suite.Equal(int64(3), suite.countTodos())
+}
+
+func (suite *IdempotencyIntegrationTestSuite) TestDifferentBodyIsRejected() {
+	suite.Require().Equal(http.StatusCreated, suite.create("create-1", "Buy milk").Code)
+
+	recorder := suite.create("create-1", "Buy bread")
+	suite.Equal(http.StatusUnprocessableEntity, recorder.Code)
+	suite.Equal(int64(1), suite.countTodos())
+}
+
+func (suite *IdempotencyIntegrationTestSuite) TestRequestInProgress() {
+	request, _ := suite.helper.CreateTodoRequest("POST", "/api/v1/todos", dto.CreateTodoRequest{Title: "Buy milk", Priority: "low"})
+	request.Header.Set("Idempotency-Key", "create-1")
+
+	// Simula a primeira requisição ainda em andamento: a resposta guardada volta a ser uma trava recente
+	first := suite.create("create-1", "Buy milk")
+	suite.Require().Equal(http.StatusCreated, first.Code)
+	suite.helper.DB.Model(&entity.IdempotencyKey{}).Where("idempotency_key = ?", "create-1").
+		Updates(map[string]interface{}{"completed": false, "locked_at": time.Now()})
+
+	recorder := suite.helper.ExecuteRequest(request)
+	suite.Equal(http.StatusConflict, recorder.Code)
+	suite.NotEmpty(recorder.Header().Get("Retry-After"))
+
+	// Uma trava abandonada é assumida pela próxima tentativa
+	suite.helper.DB.Model(&entity.IdempotencyKey{}).Where("idempotency_key = ?", "create-1").
+		Update("locked_at", time.Now().Add(-time.Hour))
+	recorder = suite.create("create-1", "Buy milk")
+	suite.Equal(http.StatusCreated, recorder.Code)
+	suite.Empty(recorder.Header().Get("Idempotent-Replayed"))
+	suite.Equal("true", suite.create("create-1", "Buy milk").Header().Get("Idempotent-Replayed"))
+}
+
+func (suite *IdempotencyIntegrationTestSuite) TestClientErrorsAreReplayed() {
+	httpReq, _ := suite.helper.CreateTodoRequest("PUT", "/api/v1/todos/999", dto.UpdateTodoRequest{})
+	httpReq.Header.Set("Idempotency-Key", "update-1")
+	suite.Equal(http.StatusNotFound, suite.helper.ExecuteRequest(httpReq).Code)
+
+	// Respostas 4xx que não dependem da credencial são guardadas: repetir a requisição devolve o mesmo 404
+	httpReq, _ = suite.helper.CreateTodoRequest("PUT", "/api/v1/todos/999", dto.UpdateTodoRequest{})
+	httpReq.Header.Set("Idempotency-Key", "update-1")
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	suite.Equal(http.StatusNotFound, recorder.Code)
+	suite.Equal("true", recorder.Header().Get("Idempotent-Replayed"))
+}
+
+func (suite *IdempotencyIntegrationTestSuite) TestForbiddenIsNotStored() {
+	httpReq, _ := suite.helper.CreateTodoRequest("POST", "/api/v1/tokens", dto.CreateAPITokenRequest{Name: "reader", Scopes: []string{"todos:read"}})
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+	response, err := suite.helper.ParseSuccessResponse(recorder)
+	suite.Require().NoError(err)
+	token := response.Data.(map[string]interface{})["token"].(string)
+
+	httpReq, _ = suite.helper.CreateTodoRequest("POST", "/api/v1/todos", dto.CreateTodoRequest{Title: "Buy milk", Priority: "low"})
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	httpReq.Header.Set("Idempotency-Key", "create-1")
+	suite.Equal(http.StatusForbidden, suite.helper.ExecuteRequest(httpReq).Code)
+
+	// Com uma credencial que pode gravar, a mesma chave executa a operação
+	recorder = suite.create("create-1", "Buy milk")
+	suite.Equal(http.StatusCreated, recorder.Code)
+	suite.Empty(recorder.Header().Get("Idempotent-Replayed"))
+	suite.Equal(int64(1), suite.countTodos())
+}
+
+func (suite *IdempotencyIntegrationTestSuite) TestBodyTooLarge() {
+	title := strings.Repeat("a", 1<<20)
+	recorder := suite.create("create-1", title)
+
+	suite.Equal(http.StatusRequestEntityTooLarge, recorder.Code)
+	problem, err := suite.helper.ParseProblem(recorder)
+	suite.Require().NoError(err)
+	suite.Contains(problem.Type, "payload-too-large")
+	suite.Zero(suite.countTodos())
+
+	// Sem o header o corpo não passa pelo middleware
+	suite.Equal(http.StatusBadRequest, suite.create("", title).Code)
+}
+
+func (suite *IdempotencyIntegrationTestSuite) TestKeysArePerUser() {
+	suite.Require().Equal(http.StatusCreated, suite.create("create-1", "Buy milk").Code)
+
+	_, token, err := suite.helper.RegisterUser("other@example.com")
+	suite.Require().NoError(err)
+	httpReq, _ := suite.helper.CreateTodoRequest("POST", "/api/v1/todos", dto.CreateTodoRequest{Title: "Buy bread", Priority: "low"})
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	httpReq.Header.Set("Idempotency-Key", "create-1")
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	suite.Equal(http.StatusCreated, recorder.Code)
+	suite.Empty(recorder.Header().Get("Idempotent-Replayed"))
+}
+
+func TestIdempotencyIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyIntegrationTestSuite))
+}
@@ -0,0 +1,204 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/event"
+	"github.com/vinibsi/todo-api/internal/live"
+)
+
+// liveMessage espelha live.ServerMessage com os campos já decodificados
+type liveMessage struct {
+	Type    string          `json:"type"`
+	ID      string          `json:"id"`
+	EventID uint64          `json:"event_id"`
+	OK      *bool           `json:"ok"`
+	Data    json.RawMessage `json:"data"`
+	Error   *live.Error     `json:"error"`
+}
+
+type LiveIntegrationTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+	server *httptest.Server
+}
+
+func (suite *LiveIntegrationTestSuite) SetupSuite() {
+	helper, err := NewTestHelper()
+	suite.Require().NoError(err)
+	suite.helper = helper
+	suite.server = httptest.NewServer(helper.Router)
+}
+
+func (suite *LiveIntegrationTestSuite) TearDownSuite() {
+	suite.server.Close()
+}
+
+func (suite *LiveIntegrationTestSuite) TearDownTest() {
+	suite.helper.CleanDatabase()
+}
+
+func (suite *LiveIntegrationTestSuite) dial(token string) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(suite.server.URL, "http") + "/api/v1/ws?access_token=" + token
+	conn, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusSwitchingProtocols, resp.StatusCode)
+	suite.T().Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func (suite *LiveIntegrationTestSuite) send(conn *websocket.Conn, message live.ClientMessage) {
+	suite.Require().NoError(conn.WriteJSON(message))
+}
+
+func (suite *LiveIntegrationTestSuite) read(conn *websocket.Conn) liveMessage {
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var message liveMessage
+	suite.Require().NoError(conn.ReadJSON(&message))
+	return message
+}
+
+// ack lê mensagens até encontrar o ack com o id informado, guardando os
+// eventos recebidos no caminho
+func (suite *LiveIntegrationTestSuite) ack(conn *websocket.Conn, id string, events *[]liveMessage) liveMessage {
+	for {
+		message := suite.read(conn)
+		if message.Type == live.TypeAck && message.ID == id {
+			return message
+		}
+		if message.Type == live.TypeEvent && events != nil {
+			*events = append(*events, message)
+		}
+	}
+}
+
+func (suite *LiveIntegrationTestSuite) TestSubscribeAndMutate() {
+	project := &entity.Project{UserID: suite.helper.UserID, Name: "Board"}
+	suite.Require().NoError(suite.helper.DB.Create(project).Error)
+	outside := &entity.Todo{UserID: suite.helper.UserID, Title: "Elsewhere"}
+	suite.Require().NoError(suite.helper.CreateTodo(outside))
+
+	watcher := suite.dial(suite.helper.AccessToken)
+	editor := suite.dial(suite.helper.AccessToken)
+
+	suite.send(watcher, live.ClientMessage{ID: "s1", Type: live.TypeSubscribe, ProjectID: &project.ID})
+	subscribed := suite.ack(watcher, "s1", nil)
+	suite.Require().True(*subscribed.OK)
+	assert.JSONEq(suite.T(), fmt.Sprintf(`{"project_ids":[%d],"todo_ids":[]}`, project.ID), string(subscribed.Data))
+
+	// Alteração enviada pelo WebSocket passa pelo serviço e é confirmada
+	data, _ := json.Marshal(dto.CreateTodoRequest{Title: "Card", ProjectID: &project.ID})
+	suite.send(editor, live.ClientMessage{ID: "c1", Type: live.TypeCreate, Data: data})
+	created := suite.ack(editor, "c1", nil)
+	suite.Require().True(*created.OK, "%+v", created.Error)
+	var todo dto.TodoResponse
+	suite.Require().NoError(json.Unmarshal(created.Data, &todo))
+	assert.Equal(suite.T(), "Card", todo.Title)
+
+	// Tarefa fora do projeto não gera evento para o inscrito
+	title := "Still elsewhere"
+	data, _ = json.Marshal(dto.UpdateTodoRequest{Title: &title})
+	suite.send(editor, live.ClientMessage{ID: "u1", Type: live.TypeUpdate, TodoID: outside.ID, Data: data})
+	suite.Require().True(*suite.ack(editor, "u1", nil).OK)
+
+	suite.send(editor, live.ClientMessage{ID: "c2", Type: live.TypeComplete, TodoID: todo.ID})
+	suite.Require().True(*suite.ack(editor, "c2", nil).OK)
+
+	// O ping garante que os eventos anteriores já chegaram ao inscrito
+	suite.send(watcher, live.ClientMessage{ID: "p1", Type: live.TypePing})
+	var events []liveMessage
+	for {
+		message := suite.read(watcher)
+		if message.Type == live.TypePong {
+			assert.Equal(suite.T(), "p1", message.ID)
+			break
+		}
+		events = append(events, message)
+	}
+
+	suite.Require().Len(events, 2)
+	var types []string
+	for _, message := range events {
+		assert.Equal(suite.T(), live.TypeEvent, message.Type)
+		assert.NotZero(suite.T(), message.EventID)
+		var e event.Event
+		suite.Require().NoError(json.Unmarshal(message.Data, &e))
+		assert.Equal(suite.T(), todo.ID, e.TodoID)
+		types = append(types, e.Type)
+	}
+	assert.Equal(suite.T(), []string{event.TodoCreated, event.TodoCompleted}, types)
+}
+
+func (suite *LiveIntegrationTestSuite) TestRejectsInvalidMessages() {
+	conn := suite.dial(suite.helper.AccessToken)
+
+	// Mesmas validações da API REST
+	suite.send(conn, live.ClientMessage{ID: "1", Type: live.TypeCreate, Data: json.RawMessage(`{"priority":"urgent"}`)})
+	nack := suite.ack(conn, "1", nil)
+	assert.False(suite.T(), *nack.OK)
+	assert.Equal(suite.T(), http.StatusBadRequest, nack.Error.Code)
+
+	suite.send(conn, live.ClientMessage{ID: "2", Type: live.TypeComplete, TodoID: 9999})
+	assert.Equal(suite.T(), http.StatusNotFound, suite.ack(conn, "2", nil).Error.Code)
+
+	missing := uint(9999)
+	suite.send(conn, live.ClientMessage{ID: "3", Type: live.TypeSubscribe, ProjectID: &missing})
+	assert.Equal(suite.T(), http.StatusNotFound, suite.ack(conn, "3", nil).Error.Code)
+
+	suite.send(conn, live.ClientMessage{ID: "4", Type: "shout"})
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.ack(conn, "4", nil).Error.Code)
+
+	suite.Require().NoError(conn.WriteMessage(websocket.TextMessage, []byte("not json")))
+	malformed := suite.read(conn)
+	assert.Equal(suite.T(), live.TypeAck, malformed.Type)
+	assert.Equal(suite.T(), http.StatusBadRequest, malformed.Error.Code)
+}
+
+func (suite *LiveIntegrationTestSuite) TestReadOnlyTokenCannotMutate() {
+	httpReq, _ := suite.helper.CreateTodoRequest("POST", "/api/v1/tokens", dto.CreateAPITokenRequest{Name: "board", Scopes: []string{"todos:read"}})
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+	response, err := suite.helper.ParseSuccessResponse(recorder)
+	suite.Require().NoError(err)
+	token := response.Data.(map[string]interface{})["token"].(string)
+
+	conn := suite.dial(token)
+	suite.send(conn, live.ClientMessage{ID: "1", Type: live.TypeCreate, Data: json.RawMessage(`{"title":"Nope"}`)})
+	assert.Equal(suite.T(), http.StatusForbidden, suite.ack(conn, "1", nil).Error.Code)
+}
+
+func (suite *LiveIntegrationTestSuite) TestRequiresAuthentication() {
+	url := "ws" + strings.TrimPrefix(suite.server.URL, "http") + "/api/v1/ws"
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	suite.Require().Error(err)
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (suite *LiveIntegrationTestSuite) TestClosesUnresponsiveClient() {
+	conn := suite.dial(suite.helper.AccessToken)
+	// Sem ler, o cliente não responde aos pings e o servidor encerra a conexão
+	conn.SetPingHandler(func(string) error { return nil })
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			assert.False(suite.T(), strings.Contains(err.Error(), "timeout"), err.Error())
+			return
+		}
+	}
+}
+
+func TestLiveIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(LiveIntegrationTestSuite))
+}
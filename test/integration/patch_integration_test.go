@@ -0,0 +1,163 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+)
+
+type PatchIntegrationTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+}
+
+func (suite *PatchIntegrationTestSuite) SetupSuite() {
+	helper, err := NewTestHelper()
+	suite.Require().NoError(err)
+	suite.helper = helper
+}
+
+func (suite *PatchIntegrationTestSuite) TearDownTest() {
+	suite.helper.CleanDatabase()
+}
+
+func (suite *PatchIntegrationTestSuite) patch(todo *entity.Todo, contentType, body string) *httptest.ResponseRecorder {
+	httpReq, _ := suite.helper.CreateTodoRequest("PATCH", fmt.Sprintf("/api/v1/todos/%d", todo.ID), json.RawMessage(body))
+	httpReq.Header.Set("Content-Type", contentType)
+	return suite.helper.ExecuteRequest(httpReq)
+}
+
+func (suite *PatchIntegrationTestSuite) mergePatch(todo *entity.Todo, body string) *httptest.ResponseRecorder {
+	return suite.patch(todo, "application/merge-patch+json", body)
+}
+
+func (suite *PatchIntegrationTestSuite) jsonPatch(todo *entity.Todo, body string) *httptest.ResponseRecorder {
+	return suite.patch(todo, "application/json-patch+json", body)
+}
+
+func (suite *PatchIntegrationTestSuite) createTodo() *entity.Todo {
+	due := time.Date(2030, time.May, 20, 9, 0, 0, 0, time.UTC)
+	todo := &entity.Todo{Title: "Renew passport", Description: "Bring photos", Priority: "medium", DueDate: &due}
+	suite.Require().NoError(suite.helper.CreateTodo(todo))
+	return todo
+}
+
+func (suite *PatchIntegrationTestSuite) reload(todo *entity.Todo) *entity.Todo {
+	var stored entity.Todo
+	suite.Require().NoError(suite.helper.DB.Preload("Tags").First(&stored, todo.ID).Error)
+	return &stored
+}
+
+func (suite *PatchIntegrationTestSuite) TestMergePatch_ClearsDueDate() {
+	todo := suite.createTodo()
+
+	recorder := suite.mergePatch(todo, `{"due_date": null, "title": "Renew passport online"}`)
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+	suite.Equal(`"2"`, recorder.Header().Get("ETag"))
+
+	stored := suite.reload(todo)
+	suite.Nil(stored.DueDate)
+	suite.Equal("Renew passport online", stored.Title)
+	suite.Equal("Bring photos", stored.Description)
+	suite.Equal("medium", stored.Priority)
+
+	// null em um campo de texto o limpa
+	recorder = suite.mergePatch(todo, `{"description": null}`)
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+	suite.Empty(suite.reload(todo).Description)
+}
+
+func (suite *PatchIntegrationTestSuite) TestJSONPatch() {
+	todo := suite.createTodo()
+
+	recorder := suite.jsonPatch(todo, `[
+		{"op": "test", "path": "/title", "value": "Renew passport"},
+		{"op": "add", "path": "/tags/-", "value": "errands"},
+		{"op": "replace", "path": "/priority", "value": "high"},
+		{"op": "remove", "path": "/due_date"}
+	]`)
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+
+	response, err := suite.helper.ParseSuccessResponse(recorder)
+	suite.Require().NoError(err)
+	data, _ := json.Marshal(response.Data)
+	var result dto.TodoResponse
+	suite.Require().NoError(json.Unmarshal(data, &result))
+	suite.Equal([]string{"errands"}, result.Tags)
+	suite.Equal("high", result.Priority)
+	suite.Nil(result.DueDate)
+}
+
+func (suite *PatchIntegrationTestSuite) TestJSONPatch_FailedTestIsAtomic() {
+	todo := suite.createTodo()
+
+	recorder := suite.jsonPatch(todo, `[
+		{"op": "replace", "path": "/priority", "value": "high"},
+		{"op": "test", "path": "/title", "value": "Something else"}
+	]`)
+	suite.Equal(http.StatusConflict, recorder.Code)
+
+	stored := suite.reload(todo)
+	suite.Equal("medium", stored.Priority)
+	suite.Equal(1, stored.Version)
+
+	suite.Equal(http.StatusConflict, suite.jsonPatch(todo, `[{"op": "replace", "path": "/missing", "value": 1}]`).Code)
+	suite.Equal(http.StatusBadRequest, suite.jsonPatch(todo, `[{"op": "jump", "path": "/title"}]`).Code)
+	suite.Equal(http.StatusBadRequest, suite.jsonPatch(todo, `{"op": "remove", "path": "/title"}`).Code)
+}
+
+func (suite *PatchIntegrationTestSuite) TestValidation() {
+	todo := suite.createTodo()
+
+	cases := []struct {
+		contentType, body string
+	}{
+		{"application/merge-patch+json", `{"title": ""}`},
+		{"application/merge-patch+json", `{"title": null}`},
+		{"application/merge-patch+json", `{"priority": "urgent"}`},
+		{"application/merge-patch+json", `{"due_date": "tomorrow"}`},
+		{"application/merge-patch+json", `{"version": 7}`},
+		{"application/merge-patch+json", `{"recurrence": {"rule": "FREQ=DAILY", "exdates": ["2030/01/01"]}}`},
+		{"application/json-patch+json", `[{"op": "remove", "path": "/priority"}]`},
+		{"application/json-patch+json", `[{"op": "add", "path": "/id", "value": 99}]`},
+	}
+	for _, tc := range cases {
+		recorder := suite.patch(todo, tc.contentType, tc.body)
+		suite.Equal(http.StatusUnprocessableEntity, recorder.Code, tc.body)
+	}
+
+	stored := suite.reload(todo)
+	suite.Equal("Renew passport", stored.Title)
+	suite.Equal(1, stored.Version)
+}
+
+func (suite *PatchIntegrationTestSuite) TestUnsupportedContentType() {
+	todo := suite.createTodo()
+
+	recorder := suite.patch(todo, "application/json", `{"title": "Other"}`)
+	suite.Equal(http.StatusUnsupportedMediaType, recorder.Code)
+	suite.Contains(recorder.Header().Get("Accept-Patch"), "application/merge-patch+json")
+	suite.Contains(recorder.Header().Get("Accept-Patch"), "application/json-patch+json")
+}
+
+func (suite *PatchIntegrationTestSuite) TestIfMatchAndNotFound() {
+	todo := suite.createTodo()
+
+	httpReq, _ := suite.helper.CreateTodoRequest("PATCH", fmt.Sprintf("/api/v1/todos/%d", todo.ID), json.RawMessage(`{"title": "Other"}`))
+	httpReq.Header.Set("Content-Type", "application/merge-patch+json")
+	httpReq.Header.Set("If-Match", `"5"`)
+	suite.Equal(http.StatusPreconditionFailed, suite.helper.ExecuteRequest(httpReq).Code)
+
+	suite.Equal(http.StatusNotFound, suite.mergePatch(&entity.Todo{ID: 999}, `{"title": "Other"}`).Code)
+}
+
+func TestPatchIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(PatchIntegrationTestSuite))
+}
@@ -0,0 +1,96 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/dto"
+)
+
+type ProblemIntegrationTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+}
+
+func (suite *ProblemIntegrationTestSuite) SetupSuite() {
+	helper, err := NewTestHelper()
+	suite.Require().NoError(err)
+	suite.helper = helper
+}
+
+func (suite *ProblemIntegrationTestSuite) TearDownTest() {
+	suite.helper.CleanDatabase()
+}
+
+func (suite *ProblemIntegrationTestSuite) problem(recorder *httptest.ResponseRecorder) *dto.Problem {
+	suite.Equal(dto.ProblemContentType, recorder.Header().Get("Content-Type"))
+	problem, err := suite.helper.ParseProblem(recorder)
+	suite.Require().NoError(err)
+	suite.Equal(recorder.Code, problem.Status)
+	return problem
+}
+
+func (suite *ProblemIntegrationTestSuite) TestUnknownRoute() {
+	httpReq, _ := suite.helper.CreateTodoRequest("GET", "/api/v1/nothing-here", nil)
+	recorder := suite.helper.ExecuteRequest(httpReq)
+
+	suite.Equal(http.StatusNotFound, recorder.Code)
+	problem := suite.problem(recorder)
+	suite.Equal("/problems/not-found", problem.Type)
+	suite.Equal("/api/v1/nothing-here", problem.Instance)
+}
+
+func (suite *ProblemIntegrationTestSuite) TestMissingToken() {
+	httpReq, _ := http.NewRequest("GET", "/api/v1/todos", nil)
+	recorder := suite.helper.ExecuteRequest(httpReq)
+
+	suite.Equal(http.StatusUnauthorized, recorder.Code)
+	suite.NotEmpty(recorder.Header().Get("WWW-Authenticate"))
+	suite.Equal("/problems/unauthorized", suite.problem(recorder).Type)
+}
+
+func (suite *ProblemIntegrationTestSuite) TestQueryValidation() {
+	httpReq, _ := suite.helper.CreateTodoRequest("GET", "/api/v1/todos?priority=urgent", nil)
+	recorder := suite.helper.ExecuteRequest(httpReq)
+
+	suite.Equal(http.StatusBadRequest, recorder.Code)
+	problem := suite.problem(recorder)
+	suite.Equal("/problems/validation-error", problem.Type)
+	suite.Contains(problem.Errors, dto.ProblemFieldError{Field: "priority", Message: "must be one of: low, medium, high"})
+}
+
+func (suite *ProblemIntegrationTestSuite) TestBulkItemProblem() {
+	httpReq, _ := suite.helper.CreateTodoRequest("POST", "/api/v1/todos/bulk", dto.BulkRequest{
+		Mode: "best_effort",
+		Operations: []dto.BulkOperation{
+			{Op: "create", Data: json.RawMessage(`{"priority": "high"}`)},
+			{Op: "complete", ID: 999},
+		},
+	})
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+
+	response, err := suite.helper.ParseSuccessResponse(recorder)
+	suite.Require().NoError(err)
+	data, _ := json.Marshal(response.Data)
+	var bulk dto.BulkResponse
+	suite.Require().NoError(json.Unmarshal(data, &bulk))
+
+	invalid := bulk.Results[0].Error
+	suite.Require().NotNil(invalid)
+	suite.Equal(http.StatusBadRequest, bulk.Results[0].Status)
+	suite.Equal("/problems/validation-error", invalid.Type)
+	suite.Equal([]dto.ProblemFieldError{{Field: "title", Message: "is required"}}, invalid.Errors)
+
+	missing := bulk.Results[1].Error
+	suite.Require().NotNil(missing)
+	suite.Equal(http.StatusNotFound, missing.Status)
+	suite.Equal("/problems/not-found", missing.Type)
+}
+
+func TestProblemIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(ProblemIntegrationTestSuite))
+}
@@ -0,0 +1,118 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/dto"
+)
+
+type ProjectIntegrationTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+}
+
+func (suite *ProjectIntegrationTestSuite) SetupSuite() {
+	helper, err := NewTestHelper()
+	suite.Require().NoError(err)
+	suite.helper = helper
+}
+
+func (suite *ProjectIntegrationTestSuite) TearDownTest() {
+	suite.helper.CleanDatabase()
+}
+
+func (suite *ProjectIntegrationTestSuite) decode(body []byte, target interface{}) {
+	var response dto.SuccessResponse
+	suite.Require().NoError(json.Unmarshal(body, &response))
+	data, _ := json.Marshal(response.Data)
+	suite.Require().NoError(json.Unmarshal(data, target))
+}
+
+func (suite *ProjectIntegrationTestSuite) createProject(name string) dto.ProjectResponse {
+	httpReq, _ := suite.helper.CreateTodoRequest("POST", "/api/v1/projects", dto.CreateProjectRequest{Name: name, Color: "#FF0000"})
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+
+	var project dto.ProjectResponse
+	suite.decode(recorder.Body.Bytes(), &project)
+	return project
+}
+
+func (suite *ProjectIntegrationTestSuite) createTodo(title string, projectID uint) dto.TodoResponse {
+	httpReq, _ := suite.helper.CreateTodoRequest("POST", "/api/v1/todos", dto.CreateTodoRequest{Title: title, ProjectID: &projectID})
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+
+	var todo dto.TodoResponse
+	suite.decode(recorder.Body.Bytes(), &todo)
+	return todo
+}
+
+func (suite *ProjectIntegrationTestSuite) TestProjectTodos() {
+	work := suite.createProject("Work")
+	home := suite.createProject("Home")
+	assert.Equal(suite.T(), "#ff0000", work.Color)
+
+	suite.createTodo("Report", work.ID)
+	suite.createTodo("Meeting", work.ID)
+	suite.createTodo("Dishes", home.ID)
+
+	httpReq, _ := suite.helper.CreateTodoRequest("GET", fmt.Sprintf("/api/v1/projects/%d/todos", work.ID), nil)
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+
+	var list dto.TodoListResponse
+	suite.decode(recorder.Body.Bytes(), &list)
+	assert.Equal(suite.T(), int64(2), *list.Total)
+
+	httpReq, _ = suite.helper.CreateTodoRequest("GET", "/api/v1/projects/9999/todos", nil)
+	recorder = suite.helper.ExecuteRequest(httpReq)
+	assert.Equal(suite.T(), http.StatusNotFound, recorder.Code)
+}
+
+func (suite *ProjectIntegrationTestSuite) TestDeleteProject_Modes() {
+	inbox := suite.createProject("Inbox mode")
+	kept := suite.createTodo("Kept", inbox.ID)
+
+	cascade := suite.createProject("Cascade mode")
+	removed := suite.createTodo("Removed", cascade.ID)
+
+	httpReq, _ := suite.helper.CreateTodoRequest("DELETE", fmt.Sprintf("/api/v1/projects/%d", inbox.ID), nil)
+	assert.Equal(suite.T(), http.StatusOK, suite.helper.ExecuteRequest(httpReq).Code)
+
+	httpReq, _ = suite.helper.CreateTodoRequest("DELETE", fmt.Sprintf("/api/v1/projects/%d?todos=cascade", cascade.ID), nil)
+	assert.Equal(suite.T(), http.StatusOK, suite.helper.ExecuteRequest(httpReq).Code)
+
+	httpReq, _ = suite.helper.CreateTodoRequest("GET", fmt.Sprintf("/api/v1/todos/%d", kept.ID), nil)
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var todo dto.TodoResponse
+	suite.decode(recorder.Body.Bytes(), &todo)
+	assert.Nil(suite.T(), todo.ProjectID)
+
+	httpReq, _ = suite.helper.CreateTodoRequest("GET", fmt.Sprintf("/api/v1/todos/%d", removed.ID), nil)
+	assert.Equal(suite.T(), http.StatusNotFound, suite.helper.ExecuteRequest(httpReq).Code)
+
+	httpReq, _ = suite.helper.CreateTodoRequest("DELETE", "/api/v1/projects/1?todos=everything", nil)
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.helper.ExecuteRequest(httpReq).Code)
+}
+
+func (suite *ProjectIntegrationTestSuite) TestArchivedProjectRejectsTodos() {
+	project := suite.createProject("Archived")
+	archived := true
+
+	httpReq, _ := suite.helper.CreateTodoRequest("PUT", fmt.Sprintf("/api/v1/projects/%d", project.ID), dto.UpdateProjectRequest{Archived: &archived})
+	assert.Equal(suite.T(), http.StatusOK, suite.helper.ExecuteRequest(httpReq).Code)
+
+	httpReq, _ = suite.helper.CreateTodoRequest("POST", "/api/v1/todos", dto.CreateTodoRequest{Title: "Late", ProjectID: &project.ID})
+	assert.Equal(suite.T(), http.StatusConflict, suite.helper.ExecuteRequest(httpReq).Code)
+}
+
+func TestProjectIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(ProjectIntegrationTestSuite))
+}
@@ -0,0 +1,84 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+)
+
+type ReminderIntegrationTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+}
+
+func (suite *ReminderIntegrationTestSuite) SetupSuite() {
+	helper, err := NewTestHelper()
+	suite.Require().NoError(err)
+	suite.helper = helper
+}
+
+func (suite *ReminderIntegrationTestSuite) TearDownTest() {
+	suite.helper.CleanDatabase()
+}
+
+func (suite *ReminderIntegrationTestSuite) TestReminders() {
+	due := time.Date(2099, time.March, 1, 9, 0, 0, 0, time.UTC)
+	todo := &entity.Todo{Title: "Dentist", DueDate: &due}
+	suite.Require().NoError(suite.helper.CreateTodo(todo))
+	url := fmt.Sprintf("/api/v1/todos/%d/reminders", todo.ID)
+
+	minutes := 60
+	httpReq, _ := suite.helper.CreateTodoRequest("POST", url, dto.CreateReminderRequest{MinutesBefore: &minutes})
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+
+	remindAt := due.Add(-24 * time.Hour)
+	httpReq, _ = suite.helper.CreateTodoRequest("POST", url, dto.CreateReminderRequest{RemindAt: &remindAt})
+	suite.Require().Equal(http.StatusCreated, suite.helper.ExecuteRequest(httpReq).Code)
+
+	// É preciso informar exatamente um dos campos
+	httpReq, _ = suite.helper.CreateTodoRequest("POST", url, dto.CreateReminderRequest{RemindAt: &remindAt, MinutesBefore: &minutes})
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.helper.ExecuteRequest(httpReq).Code)
+
+	httpReq, _ = suite.helper.CreateTodoRequest("GET", url, nil)
+	recorder = suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+
+	response, err := suite.helper.ParseSuccessResponse(recorder)
+	suite.Require().NoError(err)
+	data, _ := json.Marshal(response.Data)
+	var reminders []dto.ReminderResponse
+	suite.Require().NoError(json.Unmarshal(data, &reminders))
+
+	suite.Require().Len(reminders, 2)
+	assert.True(suite.T(), remindAt.Equal(reminders[0].FireAt))
+	assert.True(suite.T(), due.Add(-time.Hour).Equal(reminders[1].FireAt))
+
+	httpReq, _ = suite.helper.CreateTodoRequest("DELETE", fmt.Sprintf("%s/%d", url, reminders[0].ID), nil)
+	assert.Equal(suite.T(), http.StatusOK, suite.helper.ExecuteRequest(httpReq).Code)
+	httpReq, _ = suite.helper.CreateTodoRequest("DELETE", fmt.Sprintf("%s/%d", url, reminders[0].ID), nil)
+	assert.Equal(suite.T(), http.StatusNotFound, suite.helper.ExecuteRequest(httpReq).Code)
+}
+
+func (suite *ReminderIntegrationTestSuite) TestRelativeReminderRequiresDueDate() {
+	todo := &entity.Todo{Title: "Someday"}
+	suite.Require().NoError(suite.helper.CreateTodo(todo))
+
+	minutes := 10
+	httpReq, _ := suite.helper.CreateTodoRequest("POST", fmt.Sprintf("/api/v1/todos/%d/reminders", todo.ID), dto.CreateReminderRequest{MinutesBefore: &minutes})
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.helper.ExecuteRequest(httpReq).Code)
+
+	httpReq, _ = suite.helper.CreateTodoRequest("GET", "/api/v1/todos/9999/reminders", nil)
+	assert.Equal(suite.T(), http.StatusNotFound, suite.helper.ExecuteRequest(httpReq).Code)
+}
+
+func TestReminderIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(ReminderIntegrationTestSuite))
+}
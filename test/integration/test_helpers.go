@@ -5,22 +5,38 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/auth"
 	"github.com/vinibsi/todo-api/internal/controller"
 	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/event"
+	"github.com/vinibsi/todo-api/internal/live"
+	"github.com/vinibsi/todo-api/internal/middleware"
 	"github.com/vinibsi/todo-api/internal/repository"
 	"github.com/vinibsi/todo-api/internal/service"
+	"github.com/vinibsi/todo-api/internal/webhook"
 	"github.com/vinibsi/todo-api/pkg/database"
 	"gorm.io/gorm"
 )
 
 type TestHelper struct {
-	DB         *gorm.DB
-	Router     *gin.Engine
-	Controller *controller.TodoController
-	Service    service.TodoService
-	Repository repository.TodoRepository
+	DB          *gorm.DB
+	Router      *gin.Engine
+	Controller  *controller.TodoController
+	Service     service.TodoService
+	Repository  repository.TodoRepository
+	AuthService service.AuthService
+	Tokens      *auth.TokenManager
+	Webhooks    repository.WebhookRepository
+	Broker      *event.Broker
+
+	// Usuário padrão usado para autenticar as requisições
+	UserID      uint
+	AccessToken string
 }
 
 func NewTestHelper() (*TestHelper, error) {
@@ -31,36 +47,181 @@ func NewTestHelper() (*TestHelper, error) {
 	}
 
 	// Inicializa as camadas
+	tokens := auth.NewTokenManager("integration-secret", time.Hour, 24*time.Hour)
+	authSvc := service.NewAuthService(repository.NewUserRepository(db), tokens)
+	authCtrl := controller.NewAuthController(authSvc)
+
+	apiTokenSvc := service.NewAPITokenService(repository.NewAPITokenRepository(db))
+	apiTokenCtrl := controller.NewAPITokenController(apiTokenSvc)
+
+	projectRepo := repository.NewProjectRepository(db)
+	projectSvc := service.NewProjectService(projectRepo)
+
+	tagRepo := repository.NewTagRepository(db)
+	tagCtrl := controller.NewTagController(service.NewTagService(tagRepo))
+
+	webhookRepo := repository.NewWebhookRepository(db)
+	webhookCtrl := controller.NewWebhookController(service.NewWebhookService(webhookRepo))
+
+	broker := event.NewBroker(100, 0)
+	eventCtrl := controller.NewEventController(broker, time.Second)
+
 	repo := repository.NewTodoRepository(db)
-	svc := service.NewTodoService(repo)
+	svc := service.NewTodoService(repo, projectRepo, tagRepo,
+		service.WithPublisher(event.Publishers{webhook.NewDispatcher(webhookRepo), broker}),
+		service.WithHistory(repository.NewTodoHistoryRepository(db)),
+		service.WithTransactor(repository.NewTransactor(db)),
+	)
 	ctrl := controller.NewTodoController(svc)
+	projectCtrl := controller.NewProjectController(projectSvc, svc)
+	liveCtrl := controller.NewLiveController(svc, projectSvc, broker, live.Config{PingInterval: 50 * time.Millisecond}, nil)
+	calendarCtrl := controller.NewCalendarController(service.NewCalendarService(repository.NewCalendarFeedRepository(db)), svc, true)
+	reminderCtrl := controller.NewReminderController(service.NewReminderService(repository.NewReminderRepository(db), repo))
+
+	idempotent := middleware.Idempotency(repository.NewIdempotencyRepository(db), middleware.IdempotencyConfig{MaxBodyBytes: 1 << 20})
 
 	// Configura o router
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
+	router.Use(middleware.Problems(true))
+	router.NoRoute(func(ctx *gin.Context) {
+		ctx.Error(apperror.NotFound("Route not found"))
+	})
+
+	authenticated := middleware.Auth(tokens, apiTokenSvc)
+	canRead := middleware.RequireScope(auth.ScopeTodosRead)
+	canWrite := middleware.RequireScope(auth.ScopeTodosWrite)
 
 	api := router.Group("/api/v1")
-	todos := api.Group("/todos")
+	authRoutes := api.Group("/auth")
+	{
+		authRoutes.POST("/register", authCtrl.Register)
+		authRoutes.POST("/login", authCtrl.Login)
+		authRoutes.POST("/refresh", authCtrl.Refresh)
+		authRoutes.GET("/me", authenticated, authCtrl.Me)
+	}
+
+	apiTokenRoutes := api.Group("/tokens", authenticated, middleware.RequireScope(auth.ScopeTokensManage))
+	{
+		apiTokenRoutes.GET("", apiTokenCtrl.List)
+		apiTokenRoutes.POST("", apiTokenCtrl.Create)
+		apiTokenRoutes.DELETE("/:id", apiTokenCtrl.Revoke)
+	}
+
+	todos := api.Group("/todos", authenticated, idempotent)
+	{
+		todos.GET("", canRead, ctrl.GetAll)
+		todos.GET("/:id", canRead, ctrl.GetByID)
+		todos.POST("", canWrite, ctrl.Create)
+		todos.POST("/bulk", canWrite, ctrl.Bulk)
+		todos.GET("/export", canRead, ctrl.Export)
+		todos.POST("/import", canWrite, ctrl.Import)
+		todos.PUT("/:id", canWrite, ctrl.Update)
+		todos.PATCH("/:id", canWrite, ctrl.Patch)
+		todos.DELETE("/:id", canWrite, ctrl.Delete)
+		todos.PATCH("/:id/complete", canWrite, ctrl.Complete)
+		todos.GET("/:id/children", canRead, ctrl.Children)
+		todos.GET("/:id/tree", canRead, ctrl.Tree)
+		todos.GET("/:id/history", canRead, ctrl.History)
+		todos.POST("/:id/revert/:revision", canWrite, ctrl.Revert)
+		todos.POST("/:id/restore", canWrite, ctrl.Restore)
+		todos.GET("/:id/reminders", canRead, reminderCtrl.List)
+		todos.POST("/:id/reminders", canWrite, reminderCtrl.Create)
+		todos.DELETE("/:id/reminders/:reminderId", canWrite, reminderCtrl.Delete)
+	}
+
+	projects := api.Group("/projects", authenticated, idempotent)
+	{
+		projects.GET("", canRead, projectCtrl.GetAll)
+		projects.GET("/:id", canRead, projectCtrl.GetByID)
+		projects.GET("/:id/todos", canRead, projectCtrl.Todos)
+		projects.POST("", canWrite, projectCtrl.Create)
+		projects.PUT("/:id", canWrite, projectCtrl.Update)
+		projects.DELETE("/:id", canWrite, projectCtrl.Delete)
+	}
+
+	api.GET("/tags", authenticated, canRead, tagCtrl.GetAll)
+	api.GET("/trash", authenticated, canRead, ctrl.Trash)
+	api.GET("/events", authenticated, canRead, eventCtrl.Stream)
+	api.GET("/ws", middleware.QueryToken("access_token"), authenticated, canRead, liveCtrl.Connect)
+
+	calendar := api.Group("/calendar")
+	{
+		calendar.GET("/feed", authenticated, middleware.RequireScope(auth.ScopeTokensManage), calendarCtrl.Get)
+		calendar.POST("/feed", authenticated, middleware.RequireScope(auth.ScopeTokensManage), calendarCtrl.Rotate)
+		calendar.DELETE("/feed", authenticated, middleware.RequireScope(auth.ScopeTokensManage), calendarCtrl.Disable)
+		calendar.GET("/:token", calendarCtrl.Feed)
+	}
+
+	webhooks := api.Group("/webhooks", authenticated, middleware.RequireScope(auth.ScopeWebhooksManage))
 	{
-		todos.GET("", ctrl.GetAll)
-		todos.GET("/:id", ctrl.GetByID)
-		todos.POST("", ctrl.Create)
-		todos.PUT("/:id", ctrl.Update)
-		todos.DELETE("/:id", ctrl.Delete)
-		todos.PATCH("/:id/complete", ctrl.Complete)
+		webhooks.GET("", webhookCtrl.List)
+		webhooks.POST("", webhookCtrl.Create)
+		webhooks.GET("/:id", webhookCtrl.GetByID)
+		webhooks.PUT("/:id", webhookCtrl.Update)
+		webhooks.DELETE("/:id", webhookCtrl.Delete)
+		webhooks.GET("/:id/deliveries", webhookCtrl.Deliveries)
+		webhooks.GET("/:id/deliveries/:deliveryId", webhookCtrl.Delivery)
+		webhooks.POST("/:id/deliveries/:deliveryId/redeliver", webhookCtrl.Redeliver)
+	}
+
+	helper := &TestHelper{
+		DB:          db,
+		Router:      router,
+		Controller:  ctrl,
+		Service:     svc,
+		Repository:  repo,
+		AuthService: authSvc,
+		Tokens:      tokens,
+		Webhooks:    webhookRepo,
+		Broker:      broker,
+	}
+
+	userID, token, err := helper.RegisterUser("default@example.com")
+	if err != nil {
+		return nil, err
 	}
+	helper.UserID = userID
+	helper.AccessToken = token
 
-	return &TestHelper{
-		DB:         db,
-		Router:     router,
-		Controller: ctrl,
-		Service:    svc,
-		Repository: repo,
-	}, nil
+	return helper, nil
+}
+
+// RegisterUser cria um usuário e retorna o id e um access token válido
+func (h *TestHelper) RegisterUser(email string) (uint, string, error) {
+	user, err := h.AuthService.Register(&dto.RegisterRequest{Name: "Test User", Email: email, Password: "password123"})
+	if err != nil {
+		return 0, "", err
+	}
+
+	pair, err := h.Tokens.IssuePair(user.ID)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return user.ID, pair.AccessToken, nil
+}
+
+// CreateTodo grava a tarefa diretamente no banco em nome do usuário padrão
+func (h *TestHelper) CreateTodo(todo *entity.Todo) error {
+	if todo.UserID == 0 {
+		todo.UserID = h.UserID
+	}
+	return h.Repository.Create(todo)
 }
 
 func (h *TestHelper) CleanDatabase() {
+	h.DB.Exec("DELETE FROM idempotency_keys")
+	h.DB.Exec("DELETE FROM calendar_feeds")
+	h.DB.Exec("DELETE FROM todo_histories")
+	h.DB.Exec("DELETE FROM reminders")
 	h.DB.Exec("DELETE FROM todos")
+	h.DB.Exec("DELETE FROM projects")
+	h.DB.Exec("DELETE FROM todo_tags")
+	h.DB.Exec("DELETE FROM tags")
+	h.DB.Exec("DELETE FROM webhook_attempts")
+	h.DB.Exec("DELETE FROM webhook_deliveries")
+	h.DB.Exec("DELETE FROM webhooks")
 }
 
 func (h *TestHelper) CreateTodoRequest(method, url string, body interface{}) (*http.Request, error) {
@@ -78,6 +239,10 @@ func (h *TestHelper) CreateTodoRequest(method, url string, body interface{}) (*h
 		req = httptest.NewRequest(method, url, nil)
 	}
 
+	if h.AccessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+h.AccessToken)
+	}
+
 	return req, err
 }
 
@@ -93,8 +258,8 @@ func (h *TestHelper) ParseSuccessResponse(recorder *httptest.ResponseRecorder) (
 	return &response, err
 }
 
-func (h *TestHelper) ParseErrorResponse(recorder *httptest.ResponseRecorder) (*dto.ErrorResponse, error) {
-	var response dto.ErrorResponse
-	err := json.Unmarshal(recorder.Body.Bytes(), &response)
-	return &response, err
+func (h *TestHelper) ParseProblem(recorder *httptest.ResponseRecorder) (*dto.Problem, error) {
+	var problem dto.Problem
+	err := json.Unmarshal(recorder.Body.Bytes(), &problem)
+	return &problem, err
 }
@@ -0,0 +1,137 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+)
+
+type TodoHistoryIntegrationTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+}
+
+func (suite *TodoHistoryIntegrationTestSuite) SetupSuite() {
+	helper, err := NewTestHelper()
+	suite.Require().NoError(err)
+	suite.helper = helper
+}
+
+func (suite *TodoHistoryIntegrationTestSuite) TearDownTest() {
+	suite.helper.CleanDatabase()
+}
+
+func (suite *TodoHistoryIntegrationTestSuite) request(method, url string, body interface{}) (int, json.RawMessage) {
+	httpReq, _ := suite.helper.CreateTodoRequest(method, url, body)
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	if recorder.Code >= http.StatusBadRequest {
+		return recorder.Code, nil
+	}
+	response, err := suite.helper.ParseSuccessResponse(recorder)
+	suite.Require().NoError(err)
+	data, _ := json.Marshal(response.Data)
+	return recorder.Code, data
+}
+
+func (suite *TodoHistoryIntegrationTestSuite) history(todoID uint) []dto.TodoHistoryResponse {
+	code, data := suite.request("GET", fmt.Sprintf("/api/v1/todos/%d/history", todoID), nil)
+	suite.Require().Equal(http.StatusOK, code)
+	var entries []dto.TodoHistoryResponse
+	suite.Require().NoError(json.Unmarshal(data, &entries))
+	return entries
+}
+
+func (suite *TodoHistoryIntegrationTestSuite) TestHistoryAndRevert() {
+	code, data := suite.request("POST", "/api/v1/todos", dto.CreateTodoRequest{Title: "Draft"})
+	suite.Require().Equal(http.StatusCreated, code)
+	var todo dto.TodoResponse
+	suite.Require().NoError(json.Unmarshal(data, &todo))
+
+	title := "Final"
+	code, _ = suite.request("PUT", fmt.Sprintf("/api/v1/todos/%d", todo.ID), dto.UpdateTodoRequest{Title: &title})
+	suite.Require().Equal(http.StatusOK, code)
+	// Uma gravação sem mudanças avança a versão da tarefa, mas não gera revisão
+	code, data = suite.request("PUT", fmt.Sprintf("/api/v1/todos/%d", todo.ID), dto.UpdateTodoRequest{Title: &title})
+	suite.Require().Equal(http.StatusOK, code)
+	suite.Require().NoError(json.Unmarshal(data, &todo))
+	assert.Equal(suite.T(), 3, todo.Version)
+	code, _ = suite.request("PATCH", fmt.Sprintf("/api/v1/todos/%d/complete", todo.ID), nil)
+	suite.Require().Equal(http.StatusOK, code)
+
+	entries := suite.history(todo.ID)
+	suite.Require().Len(entries, 3)
+	assert.Equal(suite.T(), []string{entity.HistoryComplete, entity.HistoryUpdate, entity.HistoryCreate},
+		[]string{entries[0].Operation, entries[1].Operation, entries[2].Operation})
+	assert.Equal(suite.T(), 3, entries[0].Revision)
+	assert.JSONEq(suite.T(), `"Draft"`, string(entries[1].Changes["title"].From))
+	assert.JSONEq(suite.T(), `"Final"`, string(entries[1].Changes["title"].To))
+	assert.Len(suite.T(), entries[1].Changes, 1)
+	assert.JSONEq(suite.T(), `true`, string(entries[0].Changes["completed"].To))
+
+	code, data = suite.request("POST", fmt.Sprintf("/api/v1/todos/%d/revert/1", todo.ID), nil)
+	suite.Require().Equal(http.StatusOK, code)
+	var reverted dto.TodoResponse
+	suite.Require().NoError(json.Unmarshal(data, &reverted))
+	assert.Equal(suite.T(), "Draft", reverted.Title)
+	assert.False(suite.T(), reverted.Completed)
+
+	entries = suite.history(todo.ID)
+	suite.Require().Len(entries, 4)
+	assert.Equal(suite.T(), entity.HistoryRevert, entries[0].Operation)
+	suite.Require().NotNil(entries[0].SourceRevision)
+	assert.Equal(suite.T(), 1, *entries[0].SourceRevision)
+
+	code, _ = suite.request("POST", fmt.Sprintf("/api/v1/todos/%d/revert/99", todo.ID), nil)
+	assert.Equal(suite.T(), http.StatusNotFound, code)
+	code, _ = suite.request("POST", fmt.Sprintf("/api/v1/todos/%d/revert/0", todo.ID), nil)
+	assert.Equal(suite.T(), http.StatusBadRequest, code)
+
+	// O histórico continua disponível depois da exclusão
+	code, _ = suite.request("DELETE", fmt.Sprintf("/api/v1/todos/%d", todo.ID), nil)
+	suite.Require().Equal(http.StatusOK, code)
+	entries = suite.history(todo.ID)
+	suite.Require().Len(entries, 5)
+	assert.Equal(suite.T(), entity.HistoryDelete, entries[0].Operation)
+}
+
+func (suite *TodoHistoryIntegrationTestSuite) TestDeleteProjectRecordsTodos() {
+	code, data := suite.request("POST", "/api/v1/projects", dto.CreateProjectRequest{Name: "Work"})
+	suite.Require().Equal(http.StatusCreated, code)
+	var project dto.ProjectResponse
+	suite.Require().NoError(json.Unmarshal(data, &project))
+
+	var moved, removed dto.TodoResponse
+	for _, target := range []*dto.TodoResponse{&moved, &removed} {
+		code, data = suite.request("POST", "/api/v1/todos", dto.CreateTodoRequest{Title: "Task", ProjectID: &project.ID})
+		suite.Require().Equal(http.StatusCreated, code)
+		suite.Require().NoError(json.Unmarshal(data, target))
+	}
+	code, _ = suite.request("DELETE", fmt.Sprintf("/api/v1/todos/%d", removed.ID), nil)
+	suite.Require().Equal(http.StatusOK, code)
+
+	// A tarefa movida para a caixa de entrada ganha uma revisão; a que já
+	// estava na lixeira não muda de histórico
+	code, _ = suite.request("DELETE", fmt.Sprintf("/api/v1/projects/%d", project.ID), nil)
+	suite.Require().Equal(http.StatusOK, code)
+
+	entries := suite.history(moved.ID)
+	suite.Require().Len(entries, 2)
+	assert.Equal(suite.T(), entity.HistoryUpdate, entries[0].Operation)
+	assert.JSONEq(suite.T(), `null`, string(entries[0].Changes["project_id"].To))
+	assert.Len(suite.T(), suite.history(removed.ID), 2)
+}
+
+func (suite *TodoHistoryIntegrationTestSuite) TestHistoryNotFound() {
+	code, _ := suite.request("GET", "/api/v1/todos/9999/history", nil)
+	assert.Equal(suite.T(), http.StatusNotFound, code)
+}
+
+func TestTodoHistoryIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(TodoHistoryIntegrationTestSuite))
+}
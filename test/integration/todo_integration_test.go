@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -66,10 +68,15 @@ func (suite *TodoIntegrationTestSuite) TestCreateTodo_ValidationError() {
 
 	assert.Equal(suite.T(), http.StatusBadRequest, recorder.Code)
 
-	response, err := suite.helper.ParseErrorResponse(recorder)
+	assert.Equal(suite.T(), dto.ProblemContentType, recorder.Header().Get("Content-Type"))
+
+	problem, err := suite.helper.ParseProblem(recorder)
 	suite.Require().NoError(err)
 
-	assert.Equal(suite.T(), "Dados inválidos", response.Error)
+	assert.Equal(suite.T(), "/problems/validation-error", problem.Type)
+	assert.Equal(suite.T(), http.StatusBadRequest, problem.Status)
+	assert.Equal(suite.T(), "/api/v1/todos", problem.Instance)
+	assert.Equal(suite.T(), []dto.ProblemFieldError{{Field: "title", Message: "is required"}}, problem.Errors)
 }
 
 func (suite *TodoIntegrationTestSuite) TestGetTodoByID_Success() {
@@ -79,7 +86,7 @@ func (suite *TodoIntegrationTestSuite) TestGetTodoByID_Success() {
 		Description: "Test Description",
 		Priority:    "medium",
 	}
-	suite.helper.Repository.Create(todo)
+	suite.helper.CreateTodo(todo)
 
 	// Busca o todo criado
 	url := fmt.Sprintf("/api/v1/todos/%d", todo.ID)
@@ -114,10 +121,13 @@ func (suite *TodoIntegrationTestSuite) TestGetTodoByID_NotFound() {
 
 	assert.Equal(suite.T(), http.StatusNotFound, recorder.Code)
 
-	response, err := suite.helper.ParseErrorResponse(recorder)
+	problem, err := suite.helper.ParseProblem(recorder)
 	suite.Require().NoError(err)
 
-	assert.Equal(suite.T(), "Erro ao buscar tarefa", response.Error)
+	assert.Equal(suite.T(), "/problems/not-found", problem.Type)
+	assert.Equal(suite.T(), "Not Found", problem.Title)
+	assert.Equal(suite.T(), "todo not found", problem.Detail)
+	assert.Equal(suite.T(), "/api/v1/todos/999", problem.Instance)
 }
 
 func (suite *TodoIntegrationTestSuite) TestGetAllTodos_Success() {
@@ -129,7 +139,7 @@ func (suite *TodoIntegrationTestSuite) TestGetAllTodos_Success() {
 	}
 
 	for _, todo := range todos {
-		suite.helper.Repository.Create(todo)
+		suite.helper.CreateTodo(todo)
 	}
 
 	httpReq, err := suite.helper.CreateTodoRequest("GET", "/api/v1/todos?page=1&page_size=10", nil)
@@ -150,12 +160,328 @@ func (suite *TodoIntegrationTestSuite) TestGetAllTodos_Success() {
 	err = json.Unmarshal(listData, &listResponse)
 	suite.Require().NoError(err)
 
-	assert.Equal(suite.T(), int64(3), listResponse.Total)
+	assert.Equal(suite.T(), int64(3), *listResponse.Total)
 	assert.Len(suite.T(), listResponse.Data, 3)
 	assert.Equal(suite.T(), 1, listResponse.Page)
 	assert.Equal(suite.T(), 10, listResponse.PageSize)
 }
 
+func (suite *TodoIntegrationTestSuite) TestGetAllTodos_WithFilters() {
+	todos := []*entity.Todo{
+		{Title: "Todo 1", Priority: "high"},
+		{Title: "Todo 2", Priority: "high", Completed: true},
+		{Title: "Todo 3", Priority: "low"},
+	}
+
+	for _, todo := range todos {
+		suite.helper.CreateTodo(todo)
+	}
+
+	httpReq, err := suite.helper.CreateTodoRequest("GET", "/api/v1/todos?priority=high&completed=false", nil)
+	suite.Require().NoError(err)
+
+	recorder := suite.helper.ExecuteRequest(httpReq)
+
+	assert.Equal(suite.T(), http.StatusOK, recorder.Code)
+
+	response, err := suite.helper.ParseSuccessResponse(recorder)
+	suite.Require().NoError(err)
+
+	listData, err := json.Marshal(response.Data)
+	suite.Require().NoError(err)
+
+	var listResponse dto.TodoListResponse
+	err = json.Unmarshal(listData, &listResponse)
+	suite.Require().NoError(err)
+
+	assert.Equal(suite.T(), int64(1), *listResponse.Total)
+	assert.Equal(suite.T(), "Todo 1", listResponse.Data[0].Title)
+}
+
+func (suite *TodoIntegrationTestSuite) TestGetAllTodos_InvalidFilter() {
+	httpReq, err := suite.helper.CreateTodoRequest("GET", "/api/v1/todos?priority=urgent", nil)
+	suite.Require().NoError(err)
+
+	recorder := suite.helper.ExecuteRequest(httpReq)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, recorder.Code)
+}
+
+func (suite *TodoIntegrationTestSuite) TestGetAllTodos_InvalidSort() {
+	httpReq, err := suite.helper.CreateTodoRequest("GET", "/api/v1/todos?sort=-secret", nil)
+	suite.Require().NoError(err)
+
+	recorder := suite.helper.ExecuteRequest(httpReq)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, recorder.Code)
+
+	problem, err := suite.helper.ParseProblem(recorder)
+	suite.Require().NoError(err)
+
+	assert.Contains(suite.T(), problem.Detail, "priority")
+}
+
+func (suite *TodoIntegrationTestSuite) TestGetAllTodos_CursorPagination() {
+	for i := 1; i <= 5; i++ {
+		suite.helper.CreateTodo(&entity.Todo{Title: fmt.Sprintf("Todo %d", i), Priority: "medium"})
+	}
+
+	seen := make(map[uint]bool)
+	url := "/api/v1/todos?pagination=cursor&size=2&sort=title"
+	for {
+		httpReq, err := suite.helper.CreateTodoRequest("GET", url, nil)
+		suite.Require().NoError(err)
+
+		recorder := suite.helper.ExecuteRequest(httpReq)
+		suite.Require().Equal(http.StatusOK, recorder.Code)
+
+		response, err := suite.helper.ParseSuccessResponse(recorder)
+		suite.Require().NoError(err)
+
+		listData, _ := json.Marshal(response.Data)
+		var listResponse dto.TodoListResponse
+		suite.Require().NoError(json.Unmarshal(listData, &listResponse))
+
+		for _, todo := range listResponse.Data {
+			assert.False(suite.T(), seen[todo.ID], "todo repetido entre páginas")
+			seen[todo.ID] = true
+		}
+
+		if !listResponse.HasMore {
+			break
+		}
+		url = "/api/v1/todos?sort=title&size=2&cursor=" + listResponse.NextCursor
+	}
+
+	assert.Len(suite.T(), seen, 5)
+}
+
+func (suite *TodoIntegrationTestSuite) TestSearchTodos() {
+	suite.helper.CreateTodo(&entity.Todo{Title: "Write report", Description: "quarterly numbers", Priority: "high"})
+	suite.helper.CreateTodo(&entity.Todo{Title: "Water plants", Priority: "low"})
+
+	httpReq, err := suite.helper.CreateTodoRequest("GET", "/api/v1/todos?q=quarterly", nil)
+	suite.Require().NoError(err)
+
+	recorder := suite.helper.ExecuteRequest(httpReq)
+
+	assert.Equal(suite.T(), http.StatusOK, recorder.Code)
+
+	response, err := suite.helper.ParseSuccessResponse(recorder)
+	suite.Require().NoError(err)
+
+	listData, _ := json.Marshal(response.Data)
+	var listResponse dto.TodoListResponse
+	suite.Require().NoError(json.Unmarshal(listData, &listResponse))
+
+	suite.Require().Len(listResponse.Data, 1)
+	assert.Equal(suite.T(), "Write report", listResponse.Data[0].Title)
+	assert.Contains(suite.T(), listResponse.Data[0].Search.Snippet, "<mark>")
+}
+
+func (suite *TodoIntegrationTestSuite) TestTodoTags() {
+	for _, req := range []dto.CreateTodoRequest{
+		{Title: "Write report", Tags: []string{"Work", "urgent"}},
+		{Title: "Book flights", Tags: []string{"work ", "travel"}},
+		{Title: "Water plants"},
+	} {
+		httpReq, _ := suite.helper.CreateTodoRequest("POST", "/api/v1/todos", req)
+		suite.Require().Equal(http.StatusCreated, suite.helper.ExecuteRequest(httpReq).Code)
+	}
+
+	list := func(url string) dto.TodoListResponse {
+		httpReq, _ := suite.helper.CreateTodoRequest("GET", url, nil)
+		recorder := suite.helper.ExecuteRequest(httpReq)
+		suite.Require().Equal(http.StatusOK, recorder.Code)
+
+		response, err := suite.helper.ParseSuccessResponse(recorder)
+		suite.Require().NoError(err)
+
+		listData, _ := json.Marshal(response.Data)
+		var listResponse dto.TodoListResponse
+		suite.Require().NoError(json.Unmarshal(listData, &listResponse))
+		return listResponse
+	}
+
+	assert.Len(suite.T(), list("/api/v1/todos?tag=urgent&tag=travel").Data, 2)
+
+	all := list("/api/v1/todos?tag=work&tag=URGENT&tag_match=all")
+	suite.Require().Len(all.Data, 1)
+	assert.Equal(suite.T(), []string{"urgent", "work"}, all.Data[0].Tags)
+
+	found := list("/api/v1/todos?q=flights")
+	suite.Require().Len(found.Data, 1)
+	assert.Equal(suite.T(), []string{"travel", "work"}, found.Data[0].Tags)
+
+	httpReq, _ := suite.helper.CreateTodoRequest("GET", "/api/v1/tags", nil)
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+
+	response, err := suite.helper.ParseSuccessResponse(recorder)
+	suite.Require().NoError(err)
+	tagData, _ := json.Marshal(response.Data)
+	var tags []dto.TagResponse
+	suite.Require().NoError(json.Unmarshal(tagData, &tags))
+
+	assert.Equal(suite.T(), []dto.TagResponse{
+		{ID: tags[0].ID, Name: "travel", Count: 1},
+		{ID: tags[1].ID, Name: "urgent", Count: 1},
+		{ID: tags[2].ID, Name: "work", Count: 2},
+	}, tags)
+}
+
+func (suite *TodoIntegrationTestSuite) TestSubtasks() {
+	create := func(title string, parentID *uint) dto.TodoResponse {
+		httpReq, _ := suite.helper.CreateTodoRequest("POST", "/api/v1/todos", dto.CreateTodoRequest{Title: title, ParentID: parentID})
+		recorder := suite.helper.ExecuteRequest(httpReq)
+		suite.Require().Equal(http.StatusCreated, recorder.Code)
+
+		response, err := suite.helper.ParseSuccessResponse(recorder)
+		suite.Require().NoError(err)
+		todoData, _ := json.Marshal(response.Data)
+		var todo dto.TodoResponse
+		suite.Require().NoError(json.Unmarshal(todoData, &todo))
+		return todo
+	}
+
+	root := create("Release", nil)
+	first := create("Changelog", &root.ID)
+	second := create("Tag version", &root.ID)
+	create("Sign tag", &second.ID)
+	create("Push tag", &second.ID)
+
+	httpReq, _ := suite.helper.CreateTodoRequest("GET", fmt.Sprintf("/api/v1/todos/%d/children", second.ID), nil)
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+
+	response, err := suite.helper.ParseSuccessResponse(recorder)
+	suite.Require().NoError(err)
+	listData, _ := json.Marshal(response.Data)
+	var children dto.TodoListResponse
+	suite.Require().NoError(json.Unmarshal(listData, &children))
+	suite.Require().Len(children.Data, 2)
+
+	// A profundidade padrão é de três níveis
+	httpReq, _ = suite.helper.CreateTodoRequest("POST", "/api/v1/todos", dto.CreateTodoRequest{Title: "Fourth level", ParentID: &children.Data[0].ID})
+	assert.Equal(suite.T(), http.StatusUnprocessableEntity, suite.helper.ExecuteRequest(httpReq).Code)
+
+	// A tarefa pai não pode ser concluída com subtarefas abertas
+	httpReq, _ = suite.helper.CreateTodoRequest("PATCH", fmt.Sprintf("/api/v1/todos/%d/complete", root.ID), nil)
+	assert.Equal(suite.T(), http.StatusConflict, suite.helper.ExecuteRequest(httpReq).Code)
+
+	httpReq, _ = suite.helper.CreateTodoRequest("PATCH", fmt.Sprintf("/api/v1/todos/%d/complete", first.ID), nil)
+	suite.Require().Equal(http.StatusOK, suite.helper.ExecuteRequest(httpReq).Code)
+
+	httpReq, _ = suite.helper.CreateTodoRequest("GET", fmt.Sprintf("/api/v1/todos/%d/tree", root.ID), nil)
+	recorder = suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+
+	response, err = suite.helper.ParseSuccessResponse(recorder)
+	suite.Require().NoError(err)
+	treeData, _ := json.Marshal(response.Data)
+	var tree dto.TodoTreeResponse
+	suite.Require().NoError(json.Unmarshal(treeData, &tree))
+
+	assert.Equal(suite.T(), &dto.SubtaskProgress{Total: 2, Done: 1}, tree.Subtasks)
+	suite.Require().Len(tree.Children, 2)
+	assert.Len(suite.T(), tree.Children[1].Children, 2)
+}
+
+func (suite *TodoIntegrationTestSuite) TestRecurringTodo() {
+	due := time.Date(2099, time.January, 5, 14, 0, 0, 0, time.UTC)
+	httpReq, _ := suite.helper.CreateTodoRequest("POST", "/api/v1/todos", dto.CreateTodoRequest{
+		Title:   "Water plants",
+		DueDate: &due,
+		Recurrence: &dto.RecurrenceRequest{
+			Rule:     "freq=daily;interval=2;count=2",
+			Timezone: "America/New_York",
+			Exdates:  []string{"2099-01-07"},
+		},
+	})
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+
+	decode := func(recorder *httptest.ResponseRecorder) dto.TodoResponse {
+		response, err := suite.helper.ParseSuccessResponse(recorder)
+		suite.Require().NoError(err)
+		todoData, _ := json.Marshal(response.Data)
+		var todo dto.TodoResponse
+		suite.Require().NoError(json.Unmarshal(todoData, &todo))
+		return todo
+	}
+
+	created := decode(recorder)
+	suite.Require().NotNil(created.Recurrence)
+	assert.Equal(suite.T(), "FREQ=DAILY;INTERVAL=2;COUNT=2", created.Recurrence.Rule)
+
+	// 7 de janeiro é exceção, então a próxima ocorrência é dia 9
+	httpReq, _ = suite.helper.CreateTodoRequest("PATCH", fmt.Sprintf("/api/v1/todos/%d/complete", created.ID), nil)
+	recorder = suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+
+	rolled := decode(recorder)
+	assert.False(suite.T(), rolled.Completed)
+	assert.True(suite.T(), due.AddDate(0, 0, 4).Equal(*rolled.DueDate))
+
+	// COUNT=2: a segunda conclusão encerra a série
+	httpReq, _ = suite.helper.CreateTodoRequest("PATCH", fmt.Sprintf("/api/v1/todos/%d/complete", created.ID), nil)
+	recorder = suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	assert.True(suite.T(), decode(recorder).Completed)
+
+	httpReq, _ = suite.helper.CreateTodoRequest("POST", "/api/v1/todos", dto.CreateTodoRequest{
+		Title:      "Invalid",
+		Recurrence: &dto.RecurrenceRequest{Rule: "FREQ=DAILY", Timezone: "Nowhere/City"},
+	})
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.helper.ExecuteRequest(httpReq).Code)
+}
+
+func (suite *TodoIntegrationTestSuite) TestTodos_RequireAuthentication() {
+	httpReq := httptest.NewRequest("GET", "/api/v1/todos", nil)
+
+	recorder := suite.helper.ExecuteRequest(httpReq)
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, recorder.Code)
+
+	httpReq = httptest.NewRequest("GET", "/api/v1/todos", nil)
+	httpReq.Header.Set("Authorization", "Bearer not-a-jwt")
+
+	recorder = suite.helper.ExecuteRequest(httpReq)
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, recorder.Code)
+}
+
+func (suite *TodoIntegrationTestSuite) TestTodos_ScopedToOwner() {
+	otherID, _, err := suite.helper.RegisterUser(fmt.Sprintf("other-%d@example.com", time.Now().UnixNano()))
+	suite.Require().NoError(err)
+
+	todo := &entity.Todo{UserID: otherID, Title: "Not yours", Priority: "low"}
+	suite.helper.CreateTodo(todo)
+
+	url := fmt.Sprintf("/api/v1/todos/%d", todo.ID)
+	for _, method := range []string{"GET", "DELETE"} {
+		httpReq, err := suite.helper.CreateTodoRequest(method, url, nil)
+		suite.Require().NoError(err)
+
+		recorder := suite.helper.ExecuteRequest(httpReq)
+
+		assert.Equal(suite.T(), http.StatusNotFound, recorder.Code, method)
+	}
+
+	httpReq, err := suite.helper.CreateTodoRequest("GET", "/api/v1/todos", nil)
+	suite.Require().NoError(err)
+
+	recorder := suite.helper.ExecuteRequest(httpReq)
+
+	response, err := suite.helper.ParseSuccessResponse(recorder)
+	suite.Require().NoError(err)
+
+	listData, _ := json.Marshal(response.Data)
+	var listResponse dto.TodoListResponse
+	suite.Require().NoError(json.Unmarshal(listData, &listResponse))
+	assert.Equal(suite.T(), int64(0), *listResponse.Total)
+}
+
 func (suite *TodoIntegrationTestSuite) TestUpdateTodo_Success() {
 	// Cria um todo
 	todo := &entity.Todo{
@@ -164,7 +490,7 @@ func (suite *TodoIntegrationTestSuite) TestUpdateTodo_Success() {
 		Priority:    "low",
 		Completed:   false,
 	}
-	suite.helper.Repository.Create(todo)
+	suite.helper.CreateTodo(todo)
 
 	// Atualiza o todo
 	newTitle := "Updated Title"
@@ -188,7 +514,7 @@ func (suite *TodoIntegrationTestSuite) TestUpdateTodo_Success() {
 	assert.Equal(suite.T(), "Tarefa atualizada com sucesso", response.Message)
 
 	// Verifica se foi atualizado no banco
-	updatedTodo, err := suite.helper.Repository.GetByID(todo.ID)
+	updatedTodo, err := suite.helper.Repository.GetByID(suite.helper.UserID, todo.ID)
 	suite.Require().NoError(err)
 
 	assert.Equal(suite.T(), "Updated Title", updatedTodo.Title)
@@ -202,7 +528,7 @@ func (suite *TodoIntegrationTestSuite) TestDeleteTodo_Success() {
 		Title:    "To be deleted",
 		Priority: "medium",
 	}
-	suite.helper.Repository.Create(todo)
+	suite.helper.CreateTodo(todo)
 
 	// Deleta o todo
 	url := fmt.Sprintf("/api/v1/todos/%d", todo.ID)
@@ -219,7 +545,7 @@ func (suite *TodoIntegrationTestSuite) TestDeleteTodo_Success() {
 	assert.Equal(suite.T(), "Tarefa deletada com sucesso", response.Message)
 
 	// Verifica se foi deletado do banco (soft delete)
-	_, err = suite.helper.Repository.GetByID(todo.ID)
+	_, err = suite.helper.Repository.GetByID(suite.helper.UserID, todo.ID)
 	assert.Error(suite.T(), err) // Deve retornar erro pois foi deletado
 }
 
@@ -230,7 +556,7 @@ func (suite *TodoIntegrationTestSuite) TestCompleteTodo_Success() {
 		Priority:  "high",
 		Completed: false,
 	}
-	suite.helper.Repository.Create(todo)
+	suite.helper.CreateTodo(todo)
 
 	// Marca como concluído
 	url := fmt.Sprintf("/api/v1/todos/%d/complete", todo.ID)
@@ -247,7 +573,7 @@ func (suite *TodoIntegrationTestSuite) TestCompleteTodo_Success() {
 	assert.Equal(suite.T(), "Tarefa marcada como concluída", response.Message)
 
 	// Verifica se foi marcado como concluído no banco
-	completedTodo, err := suite.helper.Repository.GetByID(todo.ID)
+	completedTodo, err := suite.helper.Repository.GetByID(suite.helper.UserID, todo.ID)
 	suite.Require().NoError(err)
 
 	assert.True(suite.T(), completedTodo.Completed)
@@ -0,0 +1,175 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+)
+
+type TransferIntegrationTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+}
+
+func (suite *TransferIntegrationTestSuite) SetupSuite() {
+	helper, err := NewTestHelper()
+	suite.Require().NoError(err)
+	suite.helper = helper
+}
+
+func (suite *TransferIntegrationTestSuite) TearDownTest() {
+	suite.helper.CleanDatabase()
+}
+
+func (suite *TransferIntegrationTestSuite) export(query string) *httptest.ResponseRecorder {
+	httpReq, _ := suite.helper.CreateTodoRequest("GET", "/api/v1/todos/export"+query, nil)
+	return suite.helper.ExecuteRequest(httpReq)
+}
+
+func (suite *TransferIntegrationTestSuite) upload(query, filename, content string) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", filename)
+	suite.Require().NoError(err)
+	part.Write([]byte(content))
+	suite.Require().NoError(form.Close())
+
+	httpReq := httptest.NewRequest("POST", "/api/v1/todos/import"+query, &body)
+	httpReq.Header.Set("Content-Type", form.FormDataContentType())
+	httpReq.Header.Set("Authorization", "Bearer "+suite.helper.AccessToken)
+	return suite.helper.ExecuteRequest(httpReq)
+}
+
+func (suite *TransferIntegrationTestSuite) importReport(query, filename, content string) dto.ImportReport {
+	recorder := suite.upload(query, filename, content)
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+
+	response, err := suite.helper.ParseSuccessResponse(recorder)
+	suite.Require().NoError(err)
+	data, _ := json.Marshal(response.Data)
+	var report dto.ImportReport
+	suite.Require().NoError(json.Unmarshal(data, &report))
+	return report
+}
+
+func (suite *TransferIntegrationTestSuite) countTodos() int64 {
+	var count int64
+	suite.helper.DB.Model(&entity.Todo{}).Count(&count)
+	return count
+}
+
+func (suite *TransferIntegrationTestSuite) TestExport() {
+	for i := 1; i <= 3; i++ {
+		priority := "low"
+		if i == 2 {
+			priority = "high"
+		}
+		suite.Require().NoError(suite.helper.CreateTodo(&entity.Todo{Title: fmt.Sprintf("Todo %d", i), Priority: priority}))
+	}
+
+	recorder := suite.export("")
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	assert.Equal(suite.T(), "application/json", recorder.Header().Get("Content-Type"))
+	assert.Contains(suite.T(), recorder.Header().Get("Content-Disposition"), "todos.json")
+	var todos []dto.TodoResponse
+	suite.Require().NoError(json.Unmarshal(recorder.Body.Bytes(), &todos))
+	suite.Require().Len(todos, 3)
+	assert.Equal(suite.T(), "Todo 1", todos[0].Title)
+
+	recorder = suite.export("?format=csv&priority=high")
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	lines := strings.Split(strings.TrimSpace(recorder.Body.String()), "\n")
+	suite.Require().Len(lines, 2)
+	assert.Contains(suite.T(), lines[1], "Todo 2")
+
+	recorder = suite.export("?format=ndjson")
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	assert.Equal(suite.T(), 3, strings.Count(recorder.Body.String(), "\n"))
+
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.export("?format=xml").Code)
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.export("?q=todo").Code)
+}
+
+func (suite *TransferIntegrationTestSuite) TestImportCSV() {
+	content := "title,priority,due_date,tags\n" +
+		"Pay rent,high,2099-01-01T00:00:00Z,home;bills\n" +
+		",,,\n" +
+		"Bad priority,urgent,,\n" +
+		"Bad date,low,someday,\n" +
+		"Plan trip,,,travel\n"
+
+	report := suite.importReport("?dry_run=true", "todos.csv", content)
+	assert.True(suite.T(), report.DryRun)
+	assert.Equal(suite.T(), 5, report.Total)
+	assert.Equal(suite.T(), 2, report.Created)
+	assert.Equal(suite.T(), 1, report.Skipped)
+	assert.Equal(suite.T(), 2, report.Errors)
+	assert.Zero(suite.T(), report.Rows[0].ID)
+	assert.Zero(suite.T(), suite.countTodos())
+
+	report = suite.importReport("", "todos.csv", content)
+	assert.Equal(suite.T(), 2, report.Created)
+	suite.Require().Len(report.Rows, 5)
+	assert.Equal(suite.T(), "created", report.Rows[0].Status)
+	assert.NotZero(suite.T(), report.Rows[0].ID)
+	assert.Equal(suite.T(), "skipped", report.Rows[1].Status)
+	assert.Equal(suite.T(), 3, report.Rows[1].Line)
+	assert.Equal(suite.T(), "error", report.Rows[2].Status)
+	assert.Equal(suite.T(), 4, report.Rows[2].Line)
+	assert.Equal(suite.T(), http.StatusBadRequest, report.Rows[2].Error.Status)
+	assert.Equal(suite.T(), 5, report.Rows[3].Line)
+	assert.Equal(suite.T(), int64(2), suite.countTodos())
+
+	httpReq, _ := suite.helper.CreateTodoRequest("GET", fmt.Sprintf("/api/v1/todos/%d", report.Rows[0].ID), nil)
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	assert.Contains(suite.T(), recorder.Body.String(), `"tags":["bills","home"]`)
+
+	// Reimportar com skip_duplicates não duplica as tarefas
+	report = suite.importReport("?skip_duplicates=true", "todos.csv", content)
+	assert.Zero(suite.T(), report.Created)
+	assert.Equal(suite.T(), 3, report.Skipped)
+	assert.Equal(suite.T(), "duplicate title", report.Rows[0].Reason)
+	assert.Equal(suite.T(), int64(2), suite.countTodos())
+}
+
+func (suite *TransferIntegrationTestSuite) TestImportJSONRoundTrip() {
+	suite.Require().NoError(suite.helper.CreateTodo(&entity.Todo{Title: "Exported", Priority: "high"}))
+	recorder := suite.export("?format=ndjson")
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+
+	report := suite.importReport("", "backup.ndjson", recorder.Body.String()+`{"title": "Missing project", "project_id": 9999}`+"\n")
+	assert.Equal(suite.T(), 1, report.Created)
+	assert.Equal(suite.T(), 1, report.Errors)
+	assert.Equal(suite.T(), 2, report.Rows[1].Line)
+	assert.Equal(suite.T(), http.StatusBadRequest, report.Rows[1].Error.Status)
+	assert.Equal(suite.T(), int64(2), suite.countTodos())
+
+	// Com drop_references os ids de outro ambiente são ignorados
+	report = suite.importReport("?drop_references=true", "backup.ndjson",
+		`{"title": "From staging", "project_id": 9999, "parent_id": 8888}`+"\n")
+	suite.Require().Equal(1, report.Created, report.Rows)
+	var imported entity.Todo
+	suite.Require().NoError(suite.helper.DB.First(&imported, report.Rows[0].ID).Error)
+	assert.Nil(suite.T(), imported.ProjectID)
+	assert.Nil(suite.T(), imported.ParentID)
+}
+
+func (suite *TransferIntegrationTestSuite) TestImportInvalidFile() {
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.upload("", "todos.txt", "title\nx\n").Code)
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.upload("", "todos.csv", "name\nx\n").Code)
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.upload("?format=json", "todos.txt", "[{").Code)
+}
+
+func TestTransferIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(TransferIntegrationTestSuite))
+}
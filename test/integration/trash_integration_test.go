@@ -0,0 +1,109 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+)
+
+type TrashIntegrationTestSuite struct {
+	suite.Suite
+	helper *TestHelper
+}
+
+func (suite *TrashIntegrationTestSuite) SetupSuite() {
+	helper, err := NewTestHelper()
+	suite.Require().NoError(err)
+	suite.helper = helper
+}
+
+func (suite *TrashIntegrationTestSuite) TearDownTest() {
+	suite.helper.CleanDatabase()
+}
+
+func (suite *TrashIntegrationTestSuite) execute(method, url string) int {
+	httpReq, _ := suite.helper.CreateTodoRequest(method, url, nil)
+	return suite.helper.ExecuteRequest(httpReq).Code
+}
+
+func (suite *TrashIntegrationTestSuite) trash() dto.TrashListResponse {
+	httpReq, _ := suite.helper.CreateTodoRequest("GET", "/api/v1/trash", nil)
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+
+	response, err := suite.helper.ParseSuccessResponse(recorder)
+	suite.Require().NoError(err)
+	data, _ := json.Marshal(response.Data)
+	var trash dto.TrashListResponse
+	suite.Require().NoError(json.Unmarshal(data, &trash))
+	return trash
+}
+
+func (suite *TrashIntegrationTestSuite) TestTrashAndRestore() {
+	parent := &entity.Todo{Title: "Parent"}
+	suite.Require().NoError(suite.helper.CreateTodo(parent))
+	child := &entity.Todo{Title: "Child", ParentID: &parent.ID}
+	suite.Require().NoError(suite.helper.CreateTodo(child))
+
+	suite.Require().Equal(http.StatusOK, suite.execute("DELETE", fmt.Sprintf("/api/v1/todos/%d", parent.ID)))
+	assert.Equal(suite.T(), http.StatusNotFound, suite.execute("GET", fmt.Sprintf("/api/v1/todos/%d", child.ID)))
+
+	trash := suite.trash()
+	suite.Require().Len(trash.Data, 1)
+	assert.Equal(suite.T(), parent.ID, trash.Data[0].ID)
+	assert.False(suite.T(), trash.Data[0].DeletedAt.IsZero())
+
+	// A subtarefa só volta junto com a tarefa pai
+	assert.Equal(suite.T(), http.StatusConflict, suite.execute("POST", fmt.Sprintf("/api/v1/todos/%d/restore", child.ID)))
+	suite.Require().Equal(http.StatusOK, suite.execute("POST", fmt.Sprintf("/api/v1/todos/%d/restore", parent.ID)))
+	assert.Equal(suite.T(), http.StatusOK, suite.execute("GET", fmt.Sprintf("/api/v1/todos/%d", child.ID)))
+	assert.Empty(suite.T(), suite.trash().Data)
+
+	assert.Equal(suite.T(), http.StatusNotFound, suite.execute("POST", fmt.Sprintf("/api/v1/todos/%d/restore", parent.ID)))
+}
+
+func (suite *TrashIntegrationTestSuite) TestRestoreIntoInboxWhenProjectRemoved() {
+	project := &entity.Project{UserID: suite.helper.UserID, Name: "Old project"}
+	suite.Require().NoError(suite.helper.DB.Create(project).Error)
+	todo := &entity.Todo{Title: "Task", ProjectID: &project.ID}
+	suite.Require().NoError(suite.helper.CreateTodo(todo))
+
+	suite.Require().Equal(http.StatusOK, suite.execute("DELETE", fmt.Sprintf("/api/v1/projects/%d?todos=cascade", project.ID)))
+
+	httpReq, _ := suite.helper.CreateTodoRequest("POST", fmt.Sprintf("/api/v1/todos/%d/restore", todo.ID), nil)
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+
+	response, err := suite.helper.ParseSuccessResponse(recorder)
+	suite.Require().NoError(err)
+	data, _ := json.Marshal(response.Data)
+	var restored dto.TodoResponse
+	suite.Require().NoError(json.Unmarshal(data, &restored))
+	assert.Nil(suite.T(), restored.ProjectID)
+}
+
+func (suite *TrashIntegrationTestSuite) TestPermanentDelete() {
+	todo := &entity.Todo{Title: "Gone"}
+	suite.Require().NoError(suite.helper.CreateTodo(todo))
+	url := fmt.Sprintf("/api/v1/todos/%d", todo.ID)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.execute("DELETE", url+"?permanent=maybe"))
+	suite.Require().Equal(http.StatusOK, suite.execute("DELETE", url))
+	// Também apaga o que já está na lixeira
+	suite.Require().Equal(http.StatusOK, suite.execute("DELETE", url+"?permanent=true"))
+
+	assert.Empty(suite.T(), suite.trash().Data)
+	assert.Equal(suite.T(), http.StatusNotFound, suite.execute("POST", url+"/restore"))
+	assert.Equal(suite.T(), http.StatusNotFound, suite.execute("GET", url+"/history"))
+	assert.Equal(suite.T(), http.StatusNotFound, suite.execute("DELETE", url+"?permanent=true"))
+}
+
+func TestTrashIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(TrashIntegrationTestSuite))
+}
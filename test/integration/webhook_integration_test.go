@@ -0,0 +1,255 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/webhook"
+)
+
+// receiver é um endpoint de webhook que confere a assinatura de cada entrega
+type receiver struct {
+	mu       sync.Mutex
+	secret   string
+	status   int
+	payloads []webhook.Payload
+	invalid  int
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	body, _ := io.ReadAll(req.Body)
+	timestamp, _ := strconv.ParseInt(req.Header.Get(webhook.HeaderTimestamp), 10, 64)
+	if !webhook.Verify(r.secret, timestamp, body, req.Header.Get(webhook.HeaderSignature)) {
+		r.invalid++
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var payload webhook.Payload
+	json.Unmarshal(body, &payload)
+	r.payloads = append(r.payloads, payload)
+	w.WriteHeader(r.status)
+}
+
+type WebhookIntegrationTestSuite struct {
+	suite.Suite
+	helper   *TestHelper
+	receiver *receiver
+	server   *httptest.Server
+}
+
+func (suite *WebhookIntegrationTestSuite) SetupSuite() {
+	helper, err := NewTestHelper()
+	suite.Require().NoError(err)
+	suite.helper = helper
+}
+
+func (suite *WebhookIntegrationTestSuite) SetupTest() {
+	suite.receiver = &receiver{secret: "integration-webhook-secret", status: http.StatusOK}
+	suite.server = httptest.NewServer(suite.receiver)
+}
+
+func (suite *WebhookIntegrationTestSuite) TearDownTest() {
+	suite.server.Close()
+	suite.helper.CleanDatabase()
+}
+
+// receiverURL é o endereço cadastrado nos webhooks. A API recusa endereços
+// de loopback como o do servidor de teste, então o cadastro usa um nome
+// público e o cliente do worker o conecta ao servidor
+func (suite *WebhookIntegrationTestSuite) receiverURL() string {
+	return "http://hooks.example.com/receiver"
+}
+
+func (suite *WebhookIntegrationTestSuite) createWebhook(events ...string) dto.CreatedWebhookResponse {
+	httpReq, _ := suite.helper.CreateTodoRequest("POST", "/api/v1/webhooks", dto.CreateWebhookRequest{
+		URL:    suite.receiverURL(),
+		Secret: suite.receiver.secret,
+		Events: events,
+	})
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+
+	var created dto.CreatedWebhookResponse
+	suite.decode(recorder, &created)
+	return created
+}
+
+func (suite *WebhookIntegrationTestSuite) decode(recorder *httptest.ResponseRecorder, target interface{}) {
+	response, err := suite.helper.ParseSuccessResponse(recorder)
+	suite.Require().NoError(err)
+	data, _ := json.Marshal(response.Data)
+	suite.Require().NoError(json.Unmarshal(data, target))
+}
+
+func (suite *WebhookIntegrationTestSuite) worker(now time.Time) *webhook.Worker {
+	address := suite.server.Listener.Addr().String()
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, address)
+		},
+	}}
+	return webhook.NewWorker(suite.helper.Webhooks, webhook.Config{
+		MaxAttempts: 2,
+		Client:      client,
+		Now:         func() time.Time { return now },
+	})
+}
+
+func (suite *WebhookIntegrationTestSuite) TestDeliversTodoLifecycleEvents() {
+	created := suite.createWebhook("todo.created", "todo.completed", "todo.deleted")
+	assert.Equal(suite.T(), suite.receiver.secret, created.Secret)
+
+	httpReq, _ := suite.helper.CreateTodoRequest("POST", "/api/v1/todos", dto.CreateTodoRequest{Title: "Ship it"})
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+	var todo dto.TodoResponse
+	suite.decode(recorder, &todo)
+
+	// todo.updated não foi inscrito
+	title := "Ship it today"
+	httpReq, _ = suite.helper.CreateTodoRequest("PUT", fmt.Sprintf("/api/v1/todos/%d", todo.ID), dto.UpdateTodoRequest{Title: &title})
+	suite.Require().Equal(http.StatusOK, suite.helper.ExecuteRequest(httpReq).Code)
+	httpReq, _ = suite.helper.CreateTodoRequest("PATCH", fmt.Sprintf("/api/v1/todos/%d/complete", todo.ID), nil)
+	suite.Require().Equal(http.StatusOK, suite.helper.ExecuteRequest(httpReq).Code)
+	httpReq, _ = suite.helper.CreateTodoRequest("DELETE", fmt.Sprintf("/api/v1/todos/%d", todo.ID), nil)
+	suite.Require().Equal(http.StatusOK, suite.helper.ExecuteRequest(httpReq).Code)
+
+	sent, err := suite.worker(time.Now().Add(time.Second)).RunOnce(context.Background())
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 3, sent)
+
+	suite.Require().Len(suite.receiver.payloads, 3)
+	assert.Zero(suite.T(), suite.receiver.invalid)
+	types := []string{suite.receiver.payloads[0].Type, suite.receiver.payloads[1].Type, suite.receiver.payloads[2].Type}
+	assert.ElementsMatch(suite.T(), []string{"todo.created", "todo.completed", "todo.deleted"}, types)
+	for _, payload := range suite.receiver.payloads {
+		assert.Equal(suite.T(), todo.ID, payload.Data.ID)
+		assert.NotEmpty(suite.T(), payload.ID)
+	}
+
+	httpReq, _ = suite.helper.CreateTodoRequest("GET", fmt.Sprintf("/api/v1/webhooks/%d/deliveries", created.ID), nil)
+	recorder = suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var deliveries []dto.WebhookDeliveryResponse
+	suite.decode(recorder, &deliveries)
+	suite.Require().Len(deliveries, 3)
+	for _, delivery := range deliveries {
+		assert.Equal(suite.T(), entity.DeliverySucceeded, delivery.Status)
+		assert.Equal(suite.T(), http.StatusOK, delivery.LastStatusCode)
+	}
+}
+
+func (suite *WebhookIntegrationTestSuite) TestFailedDeliveryCanBeRedelivered() {
+	created := suite.createWebhook("todo.created")
+	suite.receiver.status = http.StatusInternalServerError
+
+	httpReq, _ := suite.helper.CreateTodoRequest("POST", "/api/v1/todos", dto.CreateTodoRequest{Title: "Flaky"})
+	suite.Require().Equal(http.StatusCreated, suite.helper.ExecuteRequest(httpReq).Code)
+
+	now := time.Now().Add(time.Second)
+	sent, err := suite.worker(now).RunOnce(context.Background())
+	suite.Require().NoError(err)
+	assert.Zero(suite.T(), sent)
+
+	// Antes do fim da espera a entrega não é tentada de novo
+	sent, _ = suite.worker(now.Add(10 * time.Second)).RunOnce(context.Background())
+	assert.Zero(suite.T(), sent)
+	suite.Require().Len(suite.receiver.payloads, 1)
+
+	// Segunda e última tentativa (MaxAttempts = 2)
+	sent, _ = suite.worker(now.Add(time.Minute)).RunOnce(context.Background())
+	assert.Zero(suite.T(), sent)
+	suite.Require().Len(suite.receiver.payloads, 2)
+
+	deliveriesURL := fmt.Sprintf("/api/v1/webhooks/%d/deliveries", created.ID)
+	httpReq, _ = suite.helper.CreateTodoRequest("GET", deliveriesURL, nil)
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	var deliveries []dto.WebhookDeliveryResponse
+	suite.decode(recorder, &deliveries)
+	suite.Require().Len(deliveries, 1)
+	assert.Equal(suite.T(), entity.DeliveryFailed, deliveries[0].Status)
+	assert.Equal(suite.T(), 2, deliveries[0].Attempts)
+
+	suite.receiver.status = http.StatusNoContent
+	httpReq, _ = suite.helper.CreateTodoRequest("POST", fmt.Sprintf("%s/%d/redeliver", deliveriesURL, deliveries[0].ID), nil)
+	suite.Require().Equal(http.StatusAccepted, suite.helper.ExecuteRequest(httpReq).Code)
+
+	sent, err = suite.worker(time.Now().Add(time.Second)).RunOnce(context.Background())
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 1, sent)
+	// O corpo reenviado é o mesmo da entrega original
+	suite.Require().Len(suite.receiver.payloads, 3)
+	assert.Equal(suite.T(), suite.receiver.payloads[0].ID, suite.receiver.payloads[2].ID)
+
+	httpReq, _ = suite.helper.CreateTodoRequest("GET", fmt.Sprintf("%s/%d", deliveriesURL, deliveries[0].ID), nil)
+	recorder = suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var delivery dto.WebhookDeliveryResponse
+	suite.decode(recorder, &delivery)
+	assert.Equal(suite.T(), entity.DeliverySucceeded, delivery.Status)
+	suite.Require().Len(delivery.History, 3)
+	assert.Equal(suite.T(), http.StatusInternalServerError, delivery.History[0].StatusCode)
+	assert.Equal(suite.T(), http.StatusNoContent, delivery.History[2].StatusCode)
+	assert.Equal(suite.T(), "todo.created", delivery.Event)
+}
+
+func (suite *WebhookIntegrationTestSuite) TestValidationAndOwnership() {
+	httpReq, _ := suite.helper.CreateTodoRequest("POST", "/api/v1/webhooks", dto.CreateWebhookRequest{
+		URL:    suite.receiverURL(),
+		Events: []string{"todo.archived"},
+	})
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.helper.ExecuteRequest(httpReq).Code)
+
+	// Endereços da rede interna são recusados
+	for _, target := range []string{suite.server.URL, "http://localhost:8080/hook", "http://169.254.169.254/latest", "http://[::1]/hook", "http://10.0.0.5/hook"} {
+		httpReq, _ = suite.helper.CreateTodoRequest("POST", "/api/v1/webhooks", dto.CreateWebhookRequest{
+			URL:    target,
+			Events: []string{"todo.created"},
+		})
+		assert.Equal(suite.T(), http.StatusBadRequest, suite.helper.ExecuteRequest(httpReq).Code, target)
+	}
+
+	httpReq, _ = suite.helper.CreateTodoRequest("POST", "/api/v1/webhooks", dto.CreateWebhookRequest{
+		URL:    "ftp://example.com/hook",
+		Events: []string{"todo.created"},
+	})
+	assert.Equal(suite.T(), http.StatusBadRequest, suite.helper.ExecuteRequest(httpReq).Code)
+
+	// Sem segredo informado, um é gerado
+	httpReq, _ = suite.helper.CreateTodoRequest("POST", "/api/v1/webhooks", dto.CreateWebhookRequest{
+		URL:    suite.receiverURL(),
+		Events: []string{"todo.created"},
+	})
+	recorder := suite.helper.ExecuteRequest(httpReq)
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+	var created dto.CreatedWebhookResponse
+	suite.decode(recorder, &created)
+	assert.Regexp(suite.T(), "^whsec_", created.Secret)
+
+	_, otherToken, err := suite.helper.RegisterUser("webhook-other@example.com")
+	suite.Require().NoError(err)
+	httpReq, _ = suite.helper.CreateTodoRequest("GET", fmt.Sprintf("/api/v1/webhooks/%d", created.ID), nil)
+	httpReq.Header.Set("Authorization", "Bearer "+otherToken)
+	assert.Equal(suite.T(), http.StatusNotFound, suite.helper.ExecuteRequest(httpReq).Code)
+}
+
+func TestWebhookIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookIntegrationTestSuite))
+}
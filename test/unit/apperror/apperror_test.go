@@ -0,0 +1,97 @@
+package apperror_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/apperror"
+)
+
+type AppErrorTestSuite struct {
+	suite.Suite
+	validate *validator.Validate
+}
+
+type recurrenceInput struct {
+	Timezone string `json:"timezone" binding:"required"`
+}
+
+type todoInput struct {
+	Title      string           `json:"title" binding:"required,max=5"`
+	Priority   string           `json:"priority" binding:"omitempty,oneof=low high"`
+	Tags       []string         `json:"tags" binding:"omitempty,max=2,dive,min=1"`
+	Recurrence *recurrenceInput `json:"recurrence"`
+	Limit      int              `form:"limit" binding:"omitempty,min=1"`
+}
+
+func (suite *AppErrorTestSuite) SetupSuite() {
+	suite.validate = validator.New()
+	suite.validate.SetTagName("binding")
+	suite.validate.RegisterTagNameFunc(apperror.FieldName)
+}
+
+func (suite *AppErrorTestSuite) TestKindOf() {
+	wrapped := fmt.Errorf("%w: while saving", apperror.NotFound("todo not found"))
+	suite.Equal(apperror.KindNotFound, apperror.KindOf(wrapped))
+	suite.Equal(http.StatusNotFound, apperror.KindOf(wrapped).Status())
+	suite.Equal("not-found", apperror.KindOf(wrapped).Slug())
+
+	suite.Equal(apperror.KindInternal, apperror.KindOf(errors.New("connection refused")))
+	suite.Equal(http.StatusInternalServerError, apperror.KindInternal.Status())
+
+	// O erro mais externo decide o tipo, mas a causa continua na cadeia
+	cause := apperror.NotFound("project not found")
+	err := apperror.Wrap(apperror.KindValidation, cause)
+	suite.Equal(apperror.KindValidation, apperror.KindOf(err))
+	suite.ErrorIs(err, cause)
+	suite.Equal("project not found", err.Error())
+}
+
+func (suite *AppErrorTestSuite) TestInvalidInput_ValidationErrors() {
+	err := suite.validate.Struct(todoInput{
+		Title:      "too long",
+		Priority:   "urgent",
+		Tags:       []string{"ok", ""},
+		Recurrence: &recurrenceInput{},
+	})
+	suite.Require().Error(err)
+
+	invalid := apperror.InvalidInput(err)
+	suite.Equal(apperror.KindValidation, invalid.Kind)
+	suite.Equal([]apperror.FieldError{
+		{Field: "title", Message: "must have at most 5 characters"},
+		{Field: "priority", Message: "must be one of: low, high"},
+		{Field: "tags[1]", Message: "must have at least 1 character"},
+		{Field: "recurrence.timezone", Message: "is required"},
+	}, invalid.Fields)
+
+	err = suite.validate.Struct(todoInput{Title: "ok", Limit: -1})
+	suite.Equal([]apperror.FieldError{{Field: "limit", Message: "must be at least 1"}}, apperror.InvalidInput(err).Fields)
+}
+
+func (suite *AppErrorTestSuite) TestInvalidInput_DecodeErrors() {
+	var input todoInput
+
+	err := json.Unmarshal([]byte(`{"recurrence": {"timezone": 3}}`), &input)
+	invalid := apperror.InvalidInput(err)
+	suite.Equal([]apperror.FieldError{{Field: "recurrence.timezone", Message: "must be of type string"}}, invalid.Fields)
+	suite.ErrorIs(invalid, err)
+
+	err = json.Unmarshal([]byte(`{"title": `), &input)
+	invalid = apperror.InvalidInput(err)
+	suite.Equal(apperror.KindValidation, invalid.Kind)
+	suite.Empty(invalid.Fields)
+
+	err = binding.JSON.BindBody([]byte(`{"title": "ok", "tags": "a"}`), &input)
+	suite.Equal([]apperror.FieldError{{Field: "tags", Message: "must be of type array"}}, apperror.InvalidInput(err).Fields)
+}
+
+func TestAppErrorTestSuite(t *testing.T) {
+	suite.Run(t, new(AppErrorTestSuite))
+}
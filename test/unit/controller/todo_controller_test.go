@@ -14,6 +14,8 @@ import (
 	"github.com/stretchr/testify/suite"
 	"github.com/vinibsi/todo-api/internal/controller"
 	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/middleware"
+	"github.com/vinibsi/todo-api/internal/service"
 	"github.com/vinibsi/todo-api/mocks"
 )
 
@@ -30,6 +32,7 @@ func (suite *TodoControllerTestSuite) SetupTest() {
 	suite.todoController = controller.NewTodoController(suite.mockService)
 
 	suite.router = gin.New()
+	suite.router.Use(middleware.Problems(false))
 	api := suite.router.Group("/api/v1")
 	todos := api.Group("/todos")
 	{
@@ -59,7 +62,7 @@ func (suite *TodoControllerTestSuite) TestCreate_Success() {
 		UpdatedAt:   time.Now(),
 	}
 
-	suite.mockService.On("Create", mock.AnythingOfType("*dto.CreateTodoRequest")).Return(expectedResponse, nil)
+	suite.mockService.On("Create", mock.Anything, mock.AnythingOfType("*dto.CreateTodoRequest")).Return(expectedResponse, nil)
 
 	jsonData, _ := json.Marshal(req)
 	request := httptest.NewRequest(http.MethodPost, "/api/v1/todos", bytes.NewBuffer(jsonData))
@@ -77,6 +80,53 @@ func (suite *TodoControllerTestSuite) TestCreate_Success() {
 	suite.mockService.AssertExpectations(suite.T())
 }
 
+func (suite *TodoControllerTestSuite) TestGetByID_NotFoundProblem() {
+	suite.mockService.On("GetByID", mock.Anything, uint(7)).Return((*dto.TodoResponse)(nil), service.ErrTodoNotFound)
+
+	recorder := httptest.NewRecorder()
+	suite.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/todos/7", nil))
+
+	assert.Equal(suite.T(), http.StatusNotFound, recorder.Code)
+	assert.Equal(suite.T(), dto.ProblemContentType, recorder.Header().Get("Content-Type"))
+
+	var problem dto.Problem
+	suite.Require().NoError(json.Unmarshal(recorder.Body.Bytes(), &problem))
+	assert.Equal(suite.T(), dto.Problem{
+		Type:     "/problems/not-found",
+		Title:    "Not Found",
+		Status:   http.StatusNotFound,
+		Detail:   "todo not found",
+		Instance: "/api/v1/todos/7",
+	}, problem)
+}
+
+func (suite *TodoControllerTestSuite) TestCreate_ValidationProblem() {
+	body := `{"title": "", "priority": "urgent", "tags": ["a", 1]}`
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/todos", bytes.NewBufferString(body))
+	request.Header.Set("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	suite.router.ServeHTTP(recorder, request)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, recorder.Code)
+	var problem dto.Problem
+	suite.Require().NoError(json.Unmarshal(recorder.Body.Bytes(), &problem))
+	assert.Equal(suite.T(), "/problems/validation-error", problem.Type)
+	assert.Equal(suite.T(), []dto.ProblemFieldError{{Field: "tags[1]", Message: "must be of type string"}}, problem.Errors)
+
+	request = httptest.NewRequest(http.MethodPost, "/api/v1/todos", bytes.NewBufferString(`{"title": "", "priority": "urgent"}`))
+	request.Header.Set("Content-Type", "application/json")
+	recorder = httptest.NewRecorder()
+	suite.router.ServeHTTP(recorder, request)
+
+	suite.Require().NoError(json.Unmarshal(recorder.Body.Bytes(), &problem))
+	assert.Equal(suite.T(), []dto.ProblemFieldError{
+		{Field: "title", Message: "is required"},
+		{Field: "priority", Message: "must be one of: low, medium, high"},
+	}, problem.Errors)
+	suite.mockService.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
 func TestTodoControllerTestSuite(t *testing.T) {
 	suite.Run(t, new(TodoControllerTestSuite))
 }
@@ -0,0 +1,249 @@
+package database_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/pkg/database"
+	"gorm.io/gorm"
+)
+
+type MigrateTestSuite struct {
+	suite.Suite
+	db *gorm.DB
+}
+
+func (suite *MigrateTestSuite) SetupTest() {
+	db, err := database.Connect(":memory:")
+	suite.Require().NoError(err)
+
+	// Cada conexão do SQLite em memória é um banco diferente
+	sqlDB, err := db.DB()
+	suite.Require().NoError(err)
+	sqlDB.SetMaxOpenConns(1)
+	suite.db = db
+}
+
+func (suite *MigrateTestSuite) TearDownTest() {
+	sqlDB, _ := suite.db.DB()
+	sqlDB.Close()
+}
+
+func (suite *MigrateTestSuite) hasTable(name string) bool {
+	return suite.db.Migrator().HasTable(name)
+}
+
+func (suite *MigrateTestSuite) customMigrations() []database.Migration {
+	return []database.Migration{
+		{Version: 1, Name: "create_notes", Up: "CREATE TABLE notes (id INTEGER PRIMARY KEY)", Down: "DROP TABLE notes"},
+		{Version: 2, Name: "create_labels", Up: "CREATE TABLE labels (id INTEGER PRIMARY KEY)", Down: "DROP TABLE labels"},
+	}
+}
+
+func (suite *MigrateTestSuite) TestLoadMigrations_DialectOverridesCommon() {
+	files := fstest.MapFS{
+		"0001_init.up.sql":             {Data: []byte("CREATE TABLE a (id INT)")},
+		"0001_init.up.sqlite.sql":      {Data: []byte("CREATE TABLE a (id INTEGER)")},
+		"0001_init.down.sql":           {Data: []byte("DROP TABLE a")},
+		"0002_more.up.postgres.sql":    {Data: []byte("SELECT 'postgres'")},
+		"0002_more.up.sqlite.sql":      {Data: []byte("SELECT 'sqlite'")},
+		"README.md":                    {Data: []byte("ignored")},
+		"0003_later.up.postgres.sql":   {Data: []byte("SELECT 3")},
+		"0003_later.up.sql":            {Data: []byte("SELECT 'common'")},
+		"0003_later.down.postgres.sql": {Data: []byte("SELECT -3")},
+	}
+
+	migrations, err := database.LoadMigrations(files, "sqlite")
+
+	suite.Require().NoError(err)
+	suite.Require().Len(migrations, 3)
+	assert.Equal(suite.T(), database.Migration{Version: 1, Name: "init", Up: "CREATE TABLE a (id INTEGER)", Down: "DROP TABLE a"}, migrations[0])
+	assert.Equal(suite.T(), "SELECT 'sqlite'", migrations[1].Up)
+	assert.Equal(suite.T(), "SELECT 'common'", migrations[2].Up)
+	assert.Empty(suite.T(), migrations[2].Down)
+}
+
+func (suite *MigrateTestSuite) TestLoadMigrations_MissingUpScript() {
+	files := fstest.MapFS{
+		"0001_init.up.postgres.sql": {Data: []byte("SELECT 1")},
+		"0001_init.down.sql":        {Data: []byte("SELECT 1")},
+	}
+
+	_, err := database.LoadMigrations(files, "sqlite")
+
+	assert.ErrorContains(suite.T(), err, "0001_init has no up script for sqlite")
+}
+
+func (suite *MigrateTestSuite) TestUpStatusDown() {
+	migrator := database.NewMigratorWith(suite.db, suite.customMigrations())
+
+	applied, err := migrator.Up()
+	suite.Require().NoError(err)
+	assert.Len(suite.T(), applied, 2)
+	assert.True(suite.T(), suite.hasTable("notes"))
+	assert.True(suite.T(), suite.hasTable("labels"))
+
+	applied, err = migrator.Up()
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), applied)
+
+	reverted, err := migrator.Down(1)
+	suite.Require().NoError(err)
+	suite.Require().Len(reverted, 1)
+	assert.Equal(suite.T(), 2, reverted[0].Version)
+	assert.False(suite.T(), suite.hasTable("labels"))
+	assert.True(suite.T(), suite.hasTable("notes"))
+
+	status, err := migrator.Status()
+	suite.Require().NoError(err)
+	suite.Require().Len(status, 2)
+	assert.NotNil(suite.T(), status[0].AppliedAt)
+	assert.Nil(suite.T(), status[1].AppliedAt)
+}
+
+func (suite *MigrateTestSuite) TestUp_FailedMigrationIsRolledBack() {
+	migrations := append(suite.customMigrations(), database.Migration{
+		Version: 3,
+		Name:    "broken",
+		Up:      "CREATE TABLE broken (id INTEGER PRIMARY KEY); INSERT INTO missing VALUES (1)",
+	})
+	migrator := database.NewMigratorWith(suite.db, migrations)
+
+	applied, err := migrator.Up()
+
+	assert.ErrorContains(suite.T(), err, "migration 0003_broken")
+	assert.Len(suite.T(), applied, 2)
+	assert.False(suite.T(), suite.hasTable("broken"))
+
+	status, err := migrator.Status()
+	suite.Require().NoError(err)
+	assert.Nil(suite.T(), status[2].AppliedAt)
+}
+
+func (suite *MigrateTestSuite) TestDown_WithoutDownScript() {
+	migrator := database.NewMigratorWith(suite.db, []database.Migration{
+		{Version: 1, Name: "irreversible", Up: "CREATE TABLE notes (id INTEGER PRIMARY KEY)"},
+	})
+	_, err := migrator.Up()
+	suite.Require().NoError(err)
+
+	_, err = migrator.Down(1)
+
+	assert.ErrorIs(suite.T(), err, database.ErrNoDownMigration)
+	assert.True(suite.T(), suite.hasTable("notes"))
+}
+
+func (suite *MigrateTestSuite) TestUp_UnknownAppliedVersion() {
+	_, err := database.NewMigratorWith(suite.db, suite.customMigrations()).Up()
+	suite.Require().NoError(err)
+
+	// Binário mais antigo, que conhece apenas a primeira migração
+	_, err = database.NewMigratorWith(suite.db, suite.customMigrations()[:1]).Up()
+
+	assert.ErrorIs(suite.T(), err, database.ErrUnknownMigration)
+}
+
+func (suite *MigrateTestSuite) TestEmbeddedMigrations_RoundTrip() {
+	migrator, err := database.NewMigrator(suite.db)
+	suite.Require().NoError(err)
+
+	applied, err := migrator.Up()
+	suite.Require().NoError(err)
+	suite.Require().NotEmpty(applied)
+	for _, table := range []string{"users", "todos", "projects", "tags", "todo_tags", "webhooks"} {
+		assert.True(suite.T(), suite.hasTable(table), table)
+	}
+
+	// Sem tarefas antigas a conta legada não é criada
+	var users int64
+	suite.Require().NoError(suite.db.Table("users").Count(&users).Error)
+	assert.Zero(suite.T(), users)
+
+	reverted, err := migrator.Down(len(applied))
+	suite.Require().NoError(err)
+	assert.Len(suite.T(), reverted, len(applied))
+	assert.False(suite.T(), suite.hasTable("todos"))
+
+	_, err = migrator.Up()
+	suite.Require().NoError(err)
+	assert.True(suite.T(), suite.hasTable("todos"))
+}
+
+// baselineTodo é a tarefa da versão que criava o banco com AutoMigrate
+type baselineTodo struct {
+	ID          uint   `gorm:"primaryKey"`
+	Title       string `gorm:"not null;size:255"`
+	Description string `gorm:"type:text"`
+	Completed   bool   `gorm:"default:false"`
+	Priority    string `gorm:"default:medium;size:20"`
+	DueDate     *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+}
+
+func (baselineTodo) TableName() string {
+	return "todos"
+}
+
+func (suite *MigrateTestSuite) TestMigrate_UpgradesAutoMigrateBaseline() {
+	suite.Require().NoError(suite.db.AutoMigrate(&baselineTodo{}))
+	suite.Require().NoError(suite.db.Create(&baselineTodo{Title: "Existing", Priority: "high"}).Error)
+
+	suite.Require().NoError(database.Migrate(suite.db))
+
+	var row struct {
+		Title    string
+		Priority string
+		Version  int
+		UserID   *uint
+	}
+	suite.Require().NoError(suite.db.Table("todos").Select("title, priority, version, user_id").Take(&row).Error)
+	assert.Equal(suite.T(), "Existing", row.Title)
+	assert.Equal(suite.T(), "high", row.Priority)
+	assert.Equal(suite.T(), 1, row.Version)
+
+	// A tarefa sem dono passa para a conta legada
+	var legacy struct {
+		ID           uint
+		PasswordHash string
+	}
+	suite.Require().NoError(suite.db.Table("users").Select("id, password_hash").
+		Where("email = ?", "legacy@todo-api.invalid").Take(&legacy).Error)
+	suite.Require().NotNil(row.UserID)
+	assert.Equal(suite.T(), legacy.ID, *row.UserID)
+	assert.Equal(suite.T(), "!", legacy.PasswordHash)
+	for _, column := range []string{"user_id", "project_id", "parent_id", "recurrence_rule"} {
+		assert.True(suite.T(), suite.db.Migrator().HasColumn("todos", column), column)
+	}
+}
+
+func (suite *MigrateTestSuite) TestCreateMigration_NextVersion() {
+	dir := suite.T().TempDir()
+	suite.Require().NoError(os.WriteFile(filepath.Join(dir, "0007_existing.up.sql"), []byte("SELECT 1"), 0o644))
+
+	paths, err := database.CreateMigration(dir, "Add Todo Color")
+
+	suite.Require().NoError(err)
+	assert.ElementsMatch(suite.T(), []string{
+		filepath.Join(dir, "0008_add_todo_color.up.postgres.sql"),
+		filepath.Join(dir, "0008_add_todo_color.down.postgres.sql"),
+		filepath.Join(dir, "0008_add_todo_color.up.sqlite.sql"),
+		filepath.Join(dir, "0008_add_todo_color.down.sqlite.sql"),
+	}, paths)
+
+	// Os arquivos criados são válidos, mas ainda vazios
+	migrations, err := database.LoadMigrations(os.DirFS(dir), "sqlite")
+	suite.Require().NoError(err)
+	suite.Require().Len(migrations, 2)
+	assert.Equal(suite.T(), "add_todo_color", migrations[1].Name)
+}
+
+func TestMigrateTestSuite(t *testing.T) {
+	suite.Run(t, new(MigrateTestSuite))
+}
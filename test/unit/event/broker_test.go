@@ -0,0 +1,126 @@
+package event_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/event"
+)
+
+type BrokerTestSuite struct {
+	suite.Suite
+}
+
+func publish(broker event.Publisher, userID, todoID uint) {
+	broker.Publish(event.Event{Type: event.TodoUpdated, UserID: userID, TodoID: todoID})
+}
+
+func ids(messages []event.Message) []uint64 {
+	result := make([]uint64, len(messages))
+	for i, message := range messages {
+		result[i] = message.ID
+	}
+	return result
+}
+
+func (suite *BrokerTestSuite) TestDeliversOnlyToOwner() {
+	broker := event.NewBroker(10, 10)
+	alice, _, _ := broker.Subscribe(1, 0)
+	bob, _, _ := broker.Subscribe(2, 0)
+
+	publish(broker, 1, 100)
+
+	message := <-alice.C
+	assert.Equal(suite.T(), uint64(1), message.ID)
+	assert.Equal(suite.T(), uint(100), message.Event.TodoID)
+	assert.Len(suite.T(), bob.C, 0)
+}
+
+func (suite *BrokerTestSuite) TestReplaysMissedEvents() {
+	broker := event.NewBroker(10, 10)
+	publish(broker, 1, 1) // id 1
+	publish(broker, 2, 2) // id 2
+	publish(broker, 1, 3) // id 3
+	publish(broker, 1, 4) // id 4
+
+	_, missed, complete := broker.Subscribe(1, 1)
+	assert.True(suite.T(), complete)
+	assert.Equal(suite.T(), []uint64{3, 4}, ids(missed))
+
+	_, missed, complete = broker.Subscribe(1, 4)
+	assert.True(suite.T(), complete)
+	assert.Empty(suite.T(), missed)
+}
+
+func (suite *BrokerTestSuite) TestReplayBufferIsBounded() {
+	broker := event.NewBroker(3, 10)
+	for i := uint(1); i <= 5; i++ {
+		publish(broker, 1, i)
+	}
+
+	// Os eventos 1 e 2 já saíram do buffer
+	_, missed, complete := broker.Subscribe(1, 1)
+	assert.False(suite.T(), complete)
+	assert.Equal(suite.T(), []uint64{3, 4, 5}, ids(missed))
+
+	_, missed, complete = broker.Subscribe(1, 2)
+	assert.True(suite.T(), complete)
+	assert.Equal(suite.T(), []uint64{3, 4, 5}, ids(missed))
+
+	// ID de outro processo (o broker reiniciou)
+	_, missed, complete = broker.Subscribe(1, 99)
+	assert.False(suite.T(), complete)
+	assert.Empty(suite.T(), missed)
+}
+
+func (suite *BrokerTestSuite) TestDropsSlowConsumer() {
+	broker := event.NewBroker(10, 2)
+	slow, _, _ := broker.Subscribe(1, 0)
+
+	publish(broker, 1, 1)
+	publish(broker, 1, 2)
+	publish(broker, 1, 3)
+
+	var received []uint64
+	for message := range slow.C {
+		received = append(received, message.ID)
+	}
+	assert.Equal(suite.T(), []uint64{1, 2}, received)
+	assert.True(suite.T(), slow.Dropped())
+
+	// Unsubscribe depois do descarte não causa pânico
+	broker.Unsubscribe(slow)
+}
+
+func (suite *BrokerTestSuite) TestCloseEndsSubscriptions() {
+	broker := event.NewBroker(10, 10)
+	sub, _, _ := broker.Subscribe(1, 0)
+
+	broker.Close()
+	_, open := <-sub.C
+	assert.False(suite.T(), open)
+	assert.False(suite.T(), sub.Dropped())
+
+	publish(broker, 1, 1)
+	late, _, _ := broker.Subscribe(1, 0)
+	_, open = <-late.C
+	assert.False(suite.T(), open)
+}
+
+func (suite *BrokerTestSuite) TestPublishersFanOut() {
+	first := event.NewBroker(10, 10)
+	second := event.NewBroker(10, 10)
+	a, _, _ := first.Subscribe(1, 0)
+	b, _, _ := second.Subscribe(1, 0)
+
+	publish(event.Publishers{first, second}, 1, 7)
+
+	assert.Equal(suite.T(), uint(7), (<-a.C).Event.TodoID)
+	assert.Equal(suite.T(), uint(7), (<-b.C).Event.TodoID)
+	event.Discard.Publish(event.Event{})
+}
+
+func TestBrokerTestSuite(t *testing.T) {
+	suite.Run(t, new(BrokerTestSuite))
+}
@@ -0,0 +1,301 @@
+package ical_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/ical"
+)
+
+// component é um bloco BEGIN/END lido pelo parser do teste
+type component struct {
+	name       string
+	properties []property
+	children   []*component
+}
+
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+func (c *component) get(name string) (property, bool) {
+	for _, prop := range c.properties {
+		if prop.name == name {
+			return prop, true
+		}
+	}
+	return property{}, false
+}
+
+func (c *component) childrenNamed(name string) []*component {
+	var result []*component
+	for _, child := range c.children {
+		if child.name == name {
+			result = append(result, child)
+		}
+	}
+	return result
+}
+
+// parse é um leitor RFC 5545 mínimo e independente do gerador: exige CRLF,
+// desfaz a quebra de linhas e monta a árvore de componentes
+func parse(data string) (*component, error) {
+	if !strings.HasSuffix(data, "\r\n") {
+		return nil, errors.New("missing final CRLF")
+	}
+	physical := strings.Split(strings.TrimSuffix(data, "\r\n"), "\r\n")
+
+	var lines []string
+	for _, line := range physical {
+		if strings.ContainsAny(line, "\r\n") {
+			return nil, errors.New("bare line break")
+		}
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			if len(lines) == 0 {
+				return nil, errors.New("continuation without content line")
+			}
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	var stack []*component
+	var root *component
+	for _, line := range lines {
+		prop, err := parseLine(line)
+		if err != nil {
+			return nil, err
+		}
+		switch prop.name {
+		case "BEGIN":
+			comp := &component{name: prop.value}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, comp)
+			} else if root != nil {
+				return nil, errors.New("multiple root components")
+			} else {
+				root = comp
+			}
+			stack = append(stack, comp)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].name != prop.value {
+				return nil, errors.New("unbalanced END:" + prop.value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, errors.New("property outside component")
+			}
+			current := stack[len(stack)-1]
+			current.properties = append(current.properties, prop)
+		}
+	}
+	if root == nil || len(stack) != 0 {
+		return nil, errors.New("unterminated calendar")
+	}
+	return root, nil
+}
+
+func parseLine(line string) (property, error) {
+	colon := strings.Index(line, ":")
+	if colon <= 0 {
+		return property{}, errors.New("invalid content line: " + line)
+	}
+	parts := strings.Split(line[:colon], ";")
+	prop := property{name: parts[0], params: map[string]string{}, value: line[colon+1:]}
+	for _, param := range parts[1:] {
+		key, value, ok := strings.Cut(param, "=")
+		if !ok {
+			return property{}, errors.New("invalid parameter: " + param)
+		}
+		prop.params[key] = value
+	}
+	return prop, nil
+}
+
+// unescape desfaz o escape de valores TEXT
+func unescape(value string) string {
+	var out strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+1 < len(value) {
+			i++
+			switch value[i] {
+			case 'n', 'N':
+				out.WriteByte('\n')
+			default:
+				out.WriteByte(value[i])
+			}
+			continue
+		}
+		out.WriteByte(value[i])
+	}
+	return out.String()
+}
+
+// splitList separa valores de lista por vírgulas não escapadas
+func splitList(value string) []string {
+	var items []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' {
+			i++
+			continue
+		}
+		if value[i] == ',' {
+			items = append(items, unescape(value[start:i]))
+			start = i + 1
+		}
+	}
+	return append(items, unescape(value[start:]))
+}
+
+type ICalTestSuite struct {
+	suite.Suite
+	updated time.Time
+}
+
+func (suite *ICalTestSuite) SetupTest() {
+	suite.updated = time.Date(2025, time.February, 10, 14, 30, 0, 0, time.UTC)
+}
+
+func (suite *ICalTestSuite) todos() []dto.TodoResponse {
+	timed := time.Date(2025, time.March, 3, 9, 15, 0, 0, time.UTC)
+	allDay := time.Date(2025, time.March, 4, 0, 0, 0, 0, time.UTC)
+	// Meia-noite em São Paulo (03:00 UTC) conta como dia inteiro no fuso da repetição
+	local := time.Date(2025, time.March, 5, 3, 0, 0, 0, time.UTC)
+	created := time.Date(2025, time.January, 1, 8, 0, 0, 0, time.UTC)
+
+	return []dto.TodoResponse{
+		{ID: 1, Title: "Relatório; mensal, com vírgulas \\ barra", Priority: "high", DueDate: &timed,
+			Description: strings.Repeat("Descrição longa com acentuação — ", 8) + "\nsegunda linha",
+			Tags:        []string{"work", "a,b"}, CreatedAt: created, UpdatedAt: suite.updated},
+		{ID: 2, Title: "Pagar contas", Priority: "medium", DueDate: &allDay, Completed: true,
+			Tags: []string{}, CreatedAt: created, UpdatedAt: suite.updated},
+		{ID: 3, Title: "Reunião", Priority: "low", DueDate: &local, Tags: []string{},
+			Recurrence: &dto.RecurrenceResponse{Rule: "FREQ=WEEKLY", Timezone: "America/Sao_Paulo"},
+			CreatedAt:  created, UpdatedAt: suite.updated},
+		{ID: 4, Title: "Sem prazo", Priority: "low", Tags: []string{}, CreatedAt: created, UpdatedAt: suite.updated},
+	}
+}
+
+func (suite *ICalTestSuite) write(opts ical.Options, todos []dto.TodoResponse) string {
+	var out bytes.Buffer
+	writer := ical.NewWriter(&out, opts)
+	for i := range todos {
+		suite.Require().NoError(writer.Write(&todos[i]))
+	}
+	suite.Require().NoError(writer.Close())
+	return out.String()
+}
+
+func (suite *ICalTestSuite) TestRoundTrip() {
+	todos := suite.todos()
+	output := suite.write(ical.Options{Name: "Minhas tarefas"}, todos)
+
+	for _, line := range strings.Split(strings.TrimSuffix(output, "\r\n"), "\r\n") {
+		assert.LessOrEqual(suite.T(), len(line), 75, line)
+	}
+
+	calendar, err := parse(output)
+	suite.Require().NoError(err)
+	suite.Equal("VCALENDAR", calendar.name)
+	version, _ := calendar.get("VERSION")
+	suite.Equal("2.0", version.value)
+	prodID, _ := calendar.get("PRODID")
+	suite.Equal(ical.ProdID, prodID.value)
+	name, _ := calendar.get("X-WR-CALNAME")
+	suite.Equal("Minhas tarefas", unescape(name.value))
+
+	vtodos := calendar.childrenNamed("VTODO")
+	suite.Require().Len(vtodos, 3, "tarefas sem prazo ficam fora do feed")
+	suite.Empty(calendar.childrenNamed("VEVENT"))
+
+	first := vtodos[0]
+	uid, _ := first.get("UID")
+	suite.Equal("todo-1@todo-api", uid.value)
+	summary, _ := first.get("SUMMARY")
+	suite.Equal(todos[0].Title, unescape(summary.value))
+	description, _ := first.get("DESCRIPTION")
+	suite.Equal(todos[0].Description, unescape(description.value))
+	due, _ := first.get("DUE")
+	suite.Equal("20250303T091500Z", due.value)
+	suite.Empty(due.params)
+	priority, _ := first.get("PRIORITY")
+	suite.Equal("1", priority.value)
+	status, _ := first.get("STATUS")
+	suite.Equal("NEEDS-ACTION", status.value)
+	_, completed := first.get("COMPLETED")
+	suite.False(completed)
+	categories, _ := first.get("CATEGORIES")
+	suite.Equal([]string{"work", "a,b"}, splitList(categories.value))
+	stamp, _ := first.get("DTSTAMP")
+	suite.Equal("20250210T143000Z", stamp.value)
+
+	second := vtodos[1]
+	due, _ = second.get("DUE")
+	suite.Equal("20250304", due.value)
+	suite.Equal("DATE", due.params["VALUE"])
+	status, _ = second.get("STATUS")
+	suite.Equal("COMPLETED", status.value)
+	completedAt, ok := second.get("COMPLETED")
+	suite.True(ok)
+	suite.Equal("20250210T143000Z", completedAt.value)
+	priority, _ = second.get("PRIORITY")
+	suite.Equal("5", priority.value)
+
+	third := vtodos[2]
+	due, _ = third.get("DUE")
+	suite.Equal("20250305", due.value)
+	suite.Equal("DATE", due.params["VALUE"])
+	priority, _ = third.get("PRIORITY")
+	suite.Equal("9", priority.value)
+}
+
+func (suite *ICalTestSuite) TestEventsOnlyForOpenAllDayTodos() {
+	output := suite.write(ical.Options{Events: true}, suite.todos())
+
+	calendar, err := parse(output)
+	suite.Require().NoError(err)
+	events := calendar.childrenNamed("VEVENT")
+	suite.Require().Len(events, 1)
+
+	event := events[0]
+	uid, _ := event.get("UID")
+	suite.Equal("todo-3-due@todo-api", uid.value)
+	start, _ := event.get("DTSTART")
+	suite.Equal("20250305", start.value)
+	suite.Equal("DATE", start.params["VALUE"])
+	end, _ := event.get("DTEND")
+	suite.Equal("20250306", end.value)
+	summary, _ := event.get("SUMMARY")
+	suite.Equal("Reunião", unescape(summary.value))
+}
+
+func (suite *ICalTestSuite) TestEmptyCalendar() {
+	output := suite.write(ical.Options{}, nil)
+
+	calendar, err := parse(output)
+	suite.Require().NoError(err)
+	suite.Empty(calendar.children)
+	_, named := calendar.get("X-WR-CALNAME")
+	suite.False(named)
+}
+
+func (suite *ICalTestSuite) TestOutputIsStable() {
+	todos := suite.todos()
+	suite.Equal(suite.write(ical.Options{}, todos), suite.write(ical.Options{}, todos))
+}
+
+func TestICalTestSuite(t *testing.T) {
+	suite.Run(t, new(ICalTestSuite))
+}
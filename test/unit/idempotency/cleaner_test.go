@@ -0,0 +1,64 @@
+package idempotency_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/idempotency"
+	"github.com/vinibsi/todo-api/mocks"
+)
+
+type CleanerTestSuite struct {
+	suite.Suite
+	repo    *mocks.MockIdempotencyRepository
+	now     time.Time
+	cleaner *idempotency.Cleaner
+}
+
+func (suite *CleanerTestSuite) SetupTest() {
+	suite.repo = new(mocks.MockIdempotencyRepository)
+	suite.now = time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	suite.cleaner = idempotency.NewCleaner(suite.repo, idempotency.Config{
+		BatchSize: 2,
+		Now:       func() time.Time { return suite.now },
+	})
+}
+
+func (suite *CleanerTestSuite) TestRunOnce_DeletesInBatches() {
+	suite.repo.On("DeleteExpired", suite.now, 2).Return(2, nil).Twice()
+	suite.repo.On("DeleteExpired", suite.now, 2).Return(0, nil).Once()
+
+	deleted, err := suite.cleaner.RunOnce(context.Background())
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 4, deleted)
+	suite.repo.AssertExpectations(suite.T())
+}
+
+func (suite *CleanerTestSuite) TestRunOnce_StopsOnError() {
+	suite.repo.On("DeleteExpired", suite.now, 2).Return(0, errors.New("database down")).Once()
+
+	deleted, err := suite.cleaner.RunOnce(context.Background())
+
+	assert.Error(suite.T(), err)
+	assert.Zero(suite.T(), deleted)
+	suite.repo.AssertNumberOfCalls(suite.T(), "DeleteExpired", 1)
+}
+
+func (suite *CleanerTestSuite) TestRunOnce_CanceledContext() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := suite.cleaner.RunOnce(ctx)
+
+	assert.ErrorIs(suite.T(), err, context.Canceled)
+	suite.repo.AssertNotCalled(suite.T(), "DeleteExpired")
+}
+
+func TestCleanerTestSuite(t *testing.T) {
+	suite.Run(t, new(CleanerTestSuite))
+}
@@ -0,0 +1,112 @@
+package jsonpatch_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/jsonpatch"
+)
+
+type JSONPatchTestSuite struct {
+	suite.Suite
+}
+
+// Exemplos do apêndice A da RFC 7396
+func (suite *JSONPatchTestSuite) TestMergePatch() {
+	cases := []struct{ target, patch, result string }{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, tc := range cases {
+		result, err := jsonpatch.MergePatch([]byte(tc.target), []byte(tc.patch))
+		suite.Require().NoError(err, tc.patch)
+		suite.JSONEq(tc.result, string(result), tc.patch)
+	}
+
+	_, err := jsonpatch.MergePatch([]byte(`{}`), []byte(`{"a":`))
+	suite.ErrorIs(err, jsonpatch.ErrInvalidPatch)
+}
+
+// Exemplos do apêndice A da RFC 6902
+func (suite *JSONPatchTestSuite) TestApply() {
+	cases := []struct{ doc, patch, result string }{
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{`{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{`{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{`{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			`[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			`{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{`{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{`{"baz":"qux","foo":["a",2,"c"]}`,
+			`[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`,
+			`{"baz":"qux","foo":["a",2,"c"]}`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, `{"foo":"bar","child":{"grandchild":{}}}`},
+		{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`},
+		{`{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":10}]`, `{"/":9,"~1":10}`},
+		{`{"foo":null}`, `[{"op":"test","path":"/foo","value":null}]`, `{"foo":null}`},
+		{`{"foo":"bar"}`, `[{"op":"replace","path":"/foo","value":null}]`, `{"foo":null}`},
+		{`{"foo":{"bar":[1]}}`, `[{"op":"copy","from":"/foo","path":"/baz"},{"op":"add","path":"/baz/bar/-","value":2}]`,
+			`{"foo":{"bar":[1]},"baz":{"bar":[1,2]}}`},
+	}
+
+	for _, tc := range cases {
+		result, err := jsonpatch.Apply([]byte(tc.doc), []byte(tc.patch))
+		suite.Require().NoError(err, tc.patch)
+		suite.JSONEq(tc.result, string(result), tc.patch)
+	}
+}
+
+func (suite *JSONPatchTestSuite) TestApply_Errors() {
+	cases := []struct {
+		doc, patch string
+		err        error
+	}{
+		{`{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":"bar"}]`, jsonpatch.ErrTestFailed},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]`, jsonpatch.ErrPathNotFound},
+		{`{"foo":"bar"}`, `[{"op":"replace","path":"/baz","value":1}]`, jsonpatch.ErrPathNotFound},
+		{`{"foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, jsonpatch.ErrPathNotFound},
+		{`{"foo":[1]}`, `[{"op":"add","path":"/foo/5","value":2}]`, jsonpatch.ErrPathNotFound},
+		{`{"foo":[1]}`, `[{"op":"remove","path":"/foo/01"}]`, jsonpatch.ErrInvalidPatch},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz"}]`, jsonpatch.ErrInvalidPatch},
+		{`{"foo":"bar"}`, `[{"op":"add","value":1}]`, jsonpatch.ErrInvalidPatch},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"baz","value":1}]`, jsonpatch.ErrInvalidPatch},
+		{`{"foo":"bar"}`, `[{"op":"merge","path":"/foo","value":1}]`, jsonpatch.ErrInvalidPatch},
+		{`{"foo":{"bar":1}}`, `[{"op":"move","from":"/foo","path":"/foo/bar/baz"}]`, jsonpatch.ErrInvalidPatch},
+		{`{"foo":"bar"}`, `{"op":"add","path":"/baz","value":1}`, jsonpatch.ErrInvalidPatch},
+	}
+
+	for _, tc := range cases {
+		_, err := jsonpatch.Apply([]byte(tc.doc), []byte(tc.patch))
+		suite.ErrorIs(err, tc.err, tc.patch)
+	}
+}
+
+func (suite *JSONPatchTestSuite) TestApply_IsAtomic() {
+	doc := []byte(`{"title":"Draft","tags":["a"]}`)
+	patch := `[{"op":"replace","path":"/title","value":"Final"},{"op":"add","path":"/tags/-","value":"b"},{"op":"test","path":"/title","value":"Draft"}]`
+
+	result, err := jsonpatch.Apply(doc, []byte(patch))
+	suite.ErrorIs(err, jsonpatch.ErrTestFailed)
+	suite.Nil(result)
+	suite.JSONEq(`{"title":"Draft","tags":["a"]}`, string(doc))
+}
+
+func TestJSONPatchTestSuite(t *testing.T) {
+	suite.Run(t, new(JSONPatchTestSuite))
+}
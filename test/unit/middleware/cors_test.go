@@ -0,0 +1,110 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/middleware"
+)
+
+type CORSTestSuite struct {
+	suite.Suite
+}
+
+func (suite *CORSTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *CORSTestSuite) serve(config middleware.CORSConfig, request *http.Request) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Use(middleware.CORS(config))
+	router.GET("/todos", func(ctx *gin.Context) {
+		ctx.Header("ETag", `"1"`)
+		ctx.Status(http.StatusOK)
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func (suite *CORSTestSuite) config() middleware.CORSConfig {
+	return middleware.CORSConfig{
+		AllowedOrigins: []string{"https://app.example.com"},
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Authorization", "Content-Type"},
+		ExposedHeaders: []string{"ETag"},
+		MaxAge:         time.Hour,
+	}
+}
+
+func (suite *CORSTestSuite) request(method, origin string) *http.Request {
+	request := httptest.NewRequest(method, "/todos", nil)
+	if origin != "" {
+		request.Header.Set("Origin", origin)
+	}
+	return request
+}
+
+func (suite *CORSTestSuite) TestAllowedOrigin() {
+	recorder := suite.serve(suite.config(), suite.request(http.MethodGet, "https://app.example.com"))
+
+	assert.Equal(suite.T(), http.StatusOK, recorder.Code)
+	assert.Equal(suite.T(), "https://app.example.com", recorder.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(suite.T(), "ETag", recorder.Header().Get("Access-Control-Expose-Headers"))
+	assert.Equal(suite.T(), "Origin", recorder.Header().Get("Vary"))
+	assert.Empty(suite.T(), recorder.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+func (suite *CORSTestSuite) TestOtherOrigin() {
+	recorder := suite.serve(suite.config(), suite.request(http.MethodGet, "https://evil.example.com"))
+
+	assert.Equal(suite.T(), http.StatusOK, recorder.Code)
+	assert.Empty(suite.T(), recorder.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func (suite *CORSTestSuite) TestPreflight() {
+	request := suite.request(http.MethodOptions, "https://app.example.com")
+	request.Header.Set("Access-Control-Request-Method", "POST")
+
+	recorder := suite.serve(suite.config(), request)
+
+	assert.Equal(suite.T(), http.StatusNoContent, recorder.Code)
+	assert.Equal(suite.T(), "https://app.example.com", recorder.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(suite.T(), "GET, POST", recorder.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(suite.T(), "Authorization, Content-Type", recorder.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(suite.T(), "3600", recorder.Header().Get("Access-Control-Max-Age"))
+}
+
+func (suite *CORSTestSuite) TestWildcard() {
+	config := suite.config()
+	config.AllowedOrigins = []string{"*"}
+
+	recorder := suite.serve(config, suite.request(http.MethodGet, "https://any.example.com"))
+	assert.Equal(suite.T(), "*", recorder.Header().Get("Access-Control-Allow-Origin"))
+
+	// Com credenciais a origem é repetida no lugar de "*"
+	config.AllowCredentials = true
+	recorder = suite.serve(config, suite.request(http.MethodGet, "https://any.example.com"))
+	assert.Equal(suite.T(), "https://any.example.com", recorder.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(suite.T(), "true", recorder.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+func (suite *CORSTestSuite) TestDisabledWithoutOrigins() {
+	config := suite.config()
+	config.AllowedOrigins = nil
+
+	recorder := suite.serve(config, suite.request(http.MethodGet, "https://app.example.com"))
+
+	assert.Empty(suite.T(), recorder.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(suite.T(), recorder.Header().Get("Vary"))
+}
+
+func TestCORSTestSuite(t *testing.T) {
+	suite.Run(t, new(CORSTestSuite))
+}
@@ -0,0 +1,94 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/apperror"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/middleware"
+)
+
+type ProblemsTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ProblemsTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *ProblemsTestSuite) serve(development bool, handler gin.HandlerFunc) (*httptest.ResponseRecorder, dto.Problem) {
+	router := gin.New()
+	router.Use(middleware.Problems(development))
+	router.GET("/things/:id", handler)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/things/1?full=true", nil))
+
+	var problem dto.Problem
+	if recorder.Body.Len() > 0 {
+		suite.Require().NoError(json.Unmarshal(recorder.Body.Bytes(), &problem))
+	}
+	return recorder, problem
+}
+
+func (suite *ProblemsTestSuite) TestDomainError() {
+	recorder, problem := suite.serve(false, func(ctx *gin.Context) {
+		ctx.Error(apperror.Validation("The request has invalid fields", apperror.FieldError{Field: "title", Message: "is required"}))
+	})
+
+	suite.Equal(http.StatusBadRequest, recorder.Code)
+	suite.Equal(dto.ProblemContentType, recorder.Header().Get("Content-Type"))
+	suite.Equal(dto.Problem{
+		Type:     "/problems/validation-error",
+		Title:    "Bad Request",
+		Status:   http.StatusBadRequest,
+		Detail:   "The request has invalid fields",
+		Instance: "/things/1",
+		Errors:   []dto.ProblemFieldError{{Field: "title", Message: "is required"}},
+	}, problem)
+}
+
+func (suite *ProblemsTestSuite) TestInternalErrorDetail() {
+	failing := func(ctx *gin.Context) {
+		ctx.Error(errors.New("pq: connection refused"))
+	}
+
+	recorder, problem := suite.serve(false, failing)
+	suite.Equal(http.StatusInternalServerError, recorder.Code)
+	suite.Equal("/problems/internal-error", problem.Type)
+	suite.Equal("An unexpected error occurred", problem.Detail)
+
+	_, problem = suite.serve(true, failing)
+	suite.Equal("pq: connection refused", problem.Detail)
+}
+
+func (suite *ProblemsTestSuite) TestWrittenResponseIsKept() {
+	recorder, _ := suite.serve(false, func(ctx *gin.Context) {
+		ctx.Error(errors.New("logged only"))
+		ctx.JSON(http.StatusAccepted, gin.H{"ok": true})
+	})
+
+	suite.Equal(http.StatusAccepted, recorder.Code)
+	suite.JSONEq(`{"ok": true}`, recorder.Body.String())
+}
+
+func (suite *ProblemsTestSuite) TestHandlerContentTypeIsReplaced() {
+	recorder, problem := suite.serve(false, func(ctx *gin.Context) {
+		ctx.Header("Content-Type", "text/csv")
+		ctx.Error(apperror.NotFound("thing not found"))
+	})
+
+	suite.Equal(http.StatusNotFound, recorder.Code)
+	suite.Equal(dto.ProblemContentType, recorder.Header().Get("Content-Type"))
+	suite.Equal("thing not found", problem.Detail)
+}
+
+func TestProblemsTestSuite(t *testing.T) {
+	suite.Run(t, new(ProblemsTestSuite))
+}
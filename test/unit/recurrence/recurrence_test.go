@@ -0,0 +1,168 @@
+package recurrence_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/recurrence"
+)
+
+type RecurrenceTestSuite struct {
+	suite.Suite
+	newYork *time.Location
+}
+
+func (suite *RecurrenceTestSuite) SetupSuite() {
+	location, err := time.LoadLocation("America/New_York")
+	suite.Require().NoError(err)
+	suite.newYork = location
+}
+
+func (suite *RecurrenceTestSuite) schedule(rule string, exdates ...string) *recurrence.Schedule {
+	schedule, err := recurrence.NewSchedule(rule, "America/New_York", exdates, false)
+	suite.Require().NoError(err)
+	return schedule
+}
+
+// occurrences lista as próximas n ocorrências a partir do início da série
+func (suite *RecurrenceTestSuite) occurrences(schedule *recurrence.Schedule, start time.Time, n int) []time.Time {
+	var result []time.Time
+	after := start
+	for len(result) < n {
+		next, ok := schedule.Next(start, after)
+		if !ok {
+			break
+		}
+		result = append(result, next)
+		after = next
+	}
+	return result
+}
+
+func (suite *RecurrenceTestSuite) at(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, suite.newYork)
+}
+
+func (suite *RecurrenceTestSuite) TestParse() {
+	rule, err := recurrence.Parse("RRULE:freq=weekly;byday=we,mo,we;interval=2;count=4")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=4", rule.String())
+
+	for _, invalid := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=WEEKLY;BYMONTHDAY=3",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;COUNT=2;UNTIL=20250101",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;BYHOUR=9",
+	} {
+		_, err := recurrence.Parse(invalid)
+		assert.ErrorIs(suite.T(), err, recurrence.ErrInvalidRule, invalid)
+	}
+}
+
+func (suite *RecurrenceTestSuite) TestDaily_KeepsLocalTimeAcrossDST() {
+	// O horário de verão começa em 9 de março e termina em 2 de novembro de 2025
+	start := suite.at(2025, time.March, 8, 9, 0)
+	next := suite.occurrences(suite.schedule("FREQ=DAILY"), start, 2)
+
+	assert.Equal(suite.T(), suite.at(2025, time.March, 9, 9, 0), next[0])
+	assert.Equal(suite.T(), 23*time.Hour, next[0].Sub(start))
+	assert.Equal(suite.T(), 9, next[1].Hour())
+
+	start = suite.at(2025, time.November, 1, 9, 0)
+	next = suite.occurrences(suite.schedule("FREQ=DAILY"), start, 1)
+	assert.Equal(suite.T(), 25*time.Hour, next[0].Sub(start))
+}
+
+func (suite *RecurrenceTestSuite) TestDaily_NonexistentTimeDoesNotDrift() {
+	// 02:30 não existe em 9 de março; os dias seguintes voltam para 02:30
+	start := suite.at(2025, time.March, 8, 2, 30)
+	next := suite.occurrences(suite.schedule("FREQ=DAILY"), start, 2)
+
+	assert.Equal(suite.T(), 3, next[0].Hour())
+	assert.Equal(suite.T(), suite.at(2025, time.March, 10, 2, 30), next[1])
+}
+
+func (suite *RecurrenceTestSuite) TestWeekly_ByDayWithInterval() {
+	// Segunda-feira, 27 de outubro; a semana de 3 de novembro é pulada
+	start := suite.at(2025, time.October, 27, 9, 0)
+	next := suite.occurrences(suite.schedule("FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE"), start, 3)
+
+	assert.Equal(suite.T(), []time.Time{
+		suite.at(2025, time.October, 29, 9, 0),
+		suite.at(2025, time.November, 10, 9, 0),
+		suite.at(2025, time.November, 12, 9, 0),
+	}, next)
+}
+
+func (suite *RecurrenceTestSuite) TestMonthly_SkipsShortMonths() {
+	start := suite.at(2025, time.January, 31, 18, 0)
+	next := suite.occurrences(suite.schedule("FREQ=MONTHLY"), start, 2)
+
+	assert.Equal(suite.T(), []time.Time{
+		suite.at(2025, time.March, 31, 18, 0),
+		suite.at(2025, time.May, 31, 18, 0),
+	}, next)
+
+	// Um dia que nunca acontece termina a série em vez de travar
+	_, ok := suite.schedule("FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30").Next(suite.at(2025, time.February, 1, 9, 0), suite.at(2025, time.February, 1, 9, 0))
+	assert.False(suite.T(), ok)
+}
+
+func (suite *RecurrenceTestSuite) TestMonthly_ByMonthDayInSameMonth() {
+	start := suite.at(2025, time.January, 5, 9, 0)
+	next := suite.occurrences(suite.schedule("FREQ=MONTHLY;BYMONTHDAY=20"), start, 1)
+
+	assert.Equal(suite.T(), suite.at(2025, time.January, 20, 9, 0), next[0])
+}
+
+func (suite *RecurrenceTestSuite) TestUntilAndExdates() {
+	start := suite.at(2025, time.March, 1, 9, 0)
+	schedule := suite.schedule("FREQ=DAILY;UNTIL=20250304", "2025-03-03")
+
+	assert.Equal(suite.T(), []time.Time{
+		suite.at(2025, time.March, 2, 9, 0),
+		suite.at(2025, time.March, 4, 9, 0),
+	}, suite.occurrences(schedule, start, 10))
+}
+
+func (suite *RecurrenceTestSuite) TestCatchUpSkipsPastOccurrences() {
+	start := suite.at(2025, time.January, 1, 9, 0)
+	next, ok := suite.schedule("FREQ=WEEKLY").Next(start, suite.at(2025, time.March, 10, 12, 0))
+
+	suite.Require().True(ok)
+	assert.Equal(suite.T(), suite.at(2025, time.March, 12, 9, 0), next)
+}
+
+func (suite *RecurrenceTestSuite) TestFromCompletion() {
+	schedule, err := recurrence.NewSchedule("FREQ=DAILY;INTERVAL=3", "America/New_York", nil, true)
+	suite.Require().NoError(err)
+
+	start := suite.at(2025, time.March, 1, 8, 0)
+	completedAt := suite.at(2025, time.March, 7, 22, 15)
+	next, ok := schedule.Next(start, completedAt)
+
+	suite.Require().True(ok)
+	assert.Equal(suite.T(), suite.at(2025, time.March, 10, 8, 0), next)
+
+	_, err = recurrence.NewSchedule("FREQ=WEEKLY", "UTC", nil, true)
+	assert.ErrorIs(suite.T(), err, recurrence.ErrInvalidRule)
+}
+
+func (suite *RecurrenceTestSuite) TestNewSchedule_Invalid() {
+	_, err := recurrence.NewSchedule("FREQ=DAILY", "Mars/Olympus", nil, false)
+	assert.ErrorIs(suite.T(), err, recurrence.ErrInvalidRule)
+
+	_, err = recurrence.NewSchedule("FREQ=DAILY", "", []string{"03/10/2025"}, false)
+	assert.ErrorIs(suite.T(), err, recurrence.ErrInvalidRule)
+}
+
+func TestRecurrenceTestSuite(t *testing.T) {
+	suite.Run(t, new(RecurrenceTestSuite))
+}
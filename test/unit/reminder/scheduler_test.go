@@ -0,0 +1,142 @@
+package reminder_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/reminder"
+	"github.com/vinibsi/todo-api/internal/repository"
+	"github.com/vinibsi/todo-api/mocks"
+)
+
+// fakeNotifier guarda as notificações recebidas e falha quando err é definido
+type fakeNotifier struct {
+	mu            sync.Mutex
+	err           error
+	notifications []reminder.Notification
+}
+
+func (n *fakeNotifier) Notify(_ context.Context, notification reminder.Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.notifications = append(n.notifications, notification)
+	return n.err
+}
+
+type SchedulerTestSuite struct {
+	suite.Suite
+	repo     *mocks.MockReminderRepository
+	notifier *fakeNotifier
+	now      time.Time
+}
+
+func (suite *SchedulerTestSuite) SetupTest() {
+	suite.repo = new(mocks.MockReminderRepository)
+	suite.notifier = &fakeNotifier{}
+	suite.now = time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func (suite *SchedulerTestSuite) scheduler() *reminder.Scheduler {
+	return reminder.NewScheduler(suite.repo, suite.notifier, reminder.Config{
+		Interval: 10 * time.Millisecond,
+		Now:      func() time.Time { return suite.now },
+	})
+}
+
+// claim é a reserva devolvida por ClaimDue e renewed a renovada antes do envio
+func (suite *SchedulerTestSuite) claim() (claimed, renewed time.Time) {
+	return suite.now.Add(time.Minute), suite.now.Add(2 * time.Minute)
+}
+
+func (suite *SchedulerTestSuite) TestRunOnce_DeliversAndMarksSent() {
+	due := suite.now.Add(time.Hour)
+	claimed, renewed := suite.claim()
+	suite.repo.On("ClaimDue", suite.now, 100, 2*time.Minute).Return([]entity.Reminder{
+		{ID: 1, UserID: 7, TodoID: 3, FireAt: suite.now, ClaimedUntil: &claimed, Todo: &entity.Todo{ID: 3, Title: "Pay rent", DueDate: &due}},
+	}, nil)
+	suite.repo.On("RenewClaim", uint(1), claimed, renewed).Return(nil)
+	suite.repo.On("MarkSent", uint(1), renewed, suite.now).Return(nil)
+
+	sent, err := suite.scheduler().RunOnce(context.Background())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, sent)
+	suite.Require().Len(suite.notifier.notifications, 1)
+	assert.Equal(suite.T(), "Pay rent", suite.notifier.notifications[0].Title)
+	suite.repo.AssertExpectations(suite.T())
+}
+
+func (suite *SchedulerTestSuite) TestRunOnce_RetriesWithBackoff() {
+	suite.notifier.err = errors.New("connection refused")
+	claimed, renewed := suite.claim()
+	suite.repo.On("ClaimDue", suite.now, 100, 2*time.Minute).Return([]entity.Reminder{
+		{ID: 1, Attempts: 2, ClaimedUntil: &claimed},
+		{ID: 2, Attempts: 4, ClaimedUntil: &claimed},
+	}, nil)
+	suite.repo.On("RenewClaim", mock.Anything, claimed, renewed).Return(nil)
+
+	retryAt := suite.now.Add(4 * time.Minute)
+	suite.repo.On("MarkFailed", uint(1), renewed, "connection refused", suite.now, &retryAt).Return(nil)
+	// Quinta tentativa: o lembrete é abandonado
+	suite.repo.On("MarkFailed", uint(2), renewed, "connection refused", suite.now, (*time.Time)(nil)).Return(nil)
+
+	sent, err := suite.scheduler().RunOnce(context.Background())
+
+	assert.NoError(suite.T(), err)
+	assert.Zero(suite.T(), sent)
+	suite.repo.AssertExpectations(suite.T())
+	suite.repo.AssertNotCalled(suite.T(), "MarkSent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *SchedulerTestSuite) TestRunOnce_SkipsLostClaims() {
+	claimed, renewed := suite.claim()
+	suite.repo.On("ClaimDue", suite.now, 100, 2*time.Minute).Return([]entity.Reminder{
+		{ID: 1, ClaimedUntil: &claimed},
+		{ID: 2, ClaimedUntil: &claimed},
+	}, nil)
+	// A reserva do primeiro expirou e outra instância o reservou
+	suite.repo.On("RenewClaim", uint(1), claimed, renewed).Return(repository.ErrClaimLost)
+	suite.repo.On("RenewClaim", uint(2), claimed, renewed).Return(nil)
+	suite.repo.On("MarkSent", uint(2), renewed, suite.now).Return(nil)
+
+	sent, err := suite.scheduler().RunOnce(context.Background())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, sent)
+	suite.Require().Len(suite.notifier.notifications, 1)
+	assert.Equal(suite.T(), uint(2), suite.notifier.notifications[0].ReminderID)
+	suite.repo.AssertExpectations(suite.T())
+}
+
+func (suite *SchedulerTestSuite) TestStartAndStop() {
+	suite.repo.On("ClaimDue", suite.now, 100, 2*time.Minute).Return([]entity.Reminder{}, nil)
+
+	scheduler := suite.scheduler()
+	scheduler.Start(context.Background())
+	time.Sleep(30 * time.Millisecond)
+
+	stopped := make(chan struct{})
+	go func() {
+		scheduler.Stop()
+		scheduler.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		suite.Fail("scheduler did not stop")
+	}
+	suite.repo.AssertCalled(suite.T(), "ClaimDue", suite.now, 100, 2*time.Minute)
+}
+
+func TestSchedulerTestSuite(t *testing.T) {
+	suite.Run(t, new(SchedulerTestSuite))
+}
@@ -0,0 +1,131 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/repository"
+	"github.com/vinibsi/todo-api/pkg/database"
+	"gorm.io/gorm"
+)
+
+type IdempotencyRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo repository.IdempotencyRepository
+	now  time.Time
+}
+
+func (suite *IdempotencyRepositoryTestSuite) SetupSuite() {
+	db, err := database.ConnectTest()
+	suite.Require().NoError(err)
+
+	suite.db = db
+	suite.repo = repository.NewIdempotencyRepository(db)
+	suite.now = time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func (suite *IdempotencyRepositoryTestSuite) TearDownTest() {
+	suite.db.Exec("DELETE FROM idempotency_keys")
+}
+
+func (suite *IdempotencyRepositoryTestSuite) record(userID uint, key, hash string, lockedAt time.Time) *entity.IdempotencyKey {
+	return &entity.IdempotencyKey{
+		UserID:      userID,
+		Key:         key,
+		RequestHash: hash,
+		LockedAt:    lockedAt,
+		ExpiresAt:   lockedAt.Add(24 * time.Hour),
+	}
+}
+
+func (suite *IdempotencyRepositoryTestSuite) TestAcquire_OncePerUserAndKey() {
+	first, acquired, err := suite.repo.Acquire(suite.record(1, "key-1", "hash", suite.now), suite.now.Add(-time.Minute))
+	suite.Require().NoError(err)
+	suite.True(acquired)
+	suite.NotZero(first.ID)
+
+	second, acquired, err := suite.repo.Acquire(suite.record(1, "key-1", "hash", suite.now), suite.now.Add(-time.Minute))
+	suite.Require().NoError(err)
+	suite.False(acquired)
+	suite.Equal(first.ID, second.ID)
+
+	// A mesma chave de outro usuário é independente
+	_, acquired, err = suite.repo.Acquire(suite.record(2, "key-1", "hash", suite.now), suite.now.Add(-time.Minute))
+	suite.Require().NoError(err)
+	suite.True(acquired)
+}
+
+func (suite *IdempotencyRepositoryTestSuite) TestAcquire_StaleLock() {
+	first, _, err := suite.repo.Acquire(suite.record(1, "key-1", "hash", suite.now), suite.now.Add(-time.Minute))
+	suite.Require().NoError(err)
+
+	later := suite.now.Add(2 * time.Minute)
+
+	// Outra requisição com a mesma chave nunca assume a trava
+	_, acquired, err := suite.repo.Acquire(suite.record(1, "key-1", "other", later), later.Add(-time.Minute))
+	suite.Require().NoError(err)
+	suite.False(acquired)
+
+	taken, acquired, err := suite.repo.Acquire(suite.record(1, "key-1", "hash", later), later.Add(-time.Minute))
+	suite.Require().NoError(err)
+	suite.True(acquired)
+	suite.Equal(first.ID, taken.ID)
+
+	// A trava acabou de ser renovada
+	_, acquired, err = suite.repo.Acquire(suite.record(1, "key-1", "hash", later), later.Add(-time.Minute))
+	suite.Require().NoError(err)
+	suite.False(acquired)
+}
+
+func (suite *IdempotencyRepositoryTestSuite) TestCompleteAndRelease() {
+	record, _, err := suite.repo.Acquire(suite.record(1, "key-1", "hash", suite.now), suite.now.Add(-time.Minute))
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.repo.Complete(record.ID, 201, `{"Location":"/v1/todos/1"}`, `{"data":{}}`))
+
+	// Respostas guardadas não são liberadas nem assumidas
+	suite.Require().NoError(suite.repo.Release(record.ID))
+	later := suite.now.Add(time.Hour)
+	stored, acquired, err := suite.repo.Acquire(suite.record(1, "key-1", "hash", later), later.Add(-time.Minute))
+	suite.Require().NoError(err)
+	suite.False(acquired)
+	suite.True(stored.Completed)
+	suite.Equal(201, stored.StatusCode)
+	suite.Equal(`{"data":{}}`, stored.Body)
+
+	other, _, err := suite.repo.Acquire(suite.record(1, "key-2", "hash", suite.now), suite.now.Add(-time.Minute))
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.repo.Release(other.ID))
+	_, acquired, err = suite.repo.Acquire(suite.record(1, "key-2", "hash", suite.now), suite.now.Add(-time.Minute))
+	suite.Require().NoError(err)
+	suite.True(acquired)
+}
+
+func (suite *IdempotencyRepositoryTestSuite) TestExpiredKeys() {
+	record, _, err := suite.repo.Acquire(suite.record(1, "key-1", "hash", suite.now), suite.now.Add(-time.Minute))
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.repo.Complete(record.ID, 200, "{}", "{}"))
+	_, _, err = suite.repo.Acquire(suite.record(1, "key-2", "hash", suite.now), suite.now.Add(-time.Minute))
+	suite.Require().NoError(err)
+
+	// Depois do prazo a chave pode ser usada em outra requisição
+	expired := suite.now.Add(25 * time.Hour)
+	_, acquired, err := suite.repo.Acquire(suite.record(1, "key-1", "other", expired), expired.Add(-time.Minute))
+	suite.Require().NoError(err)
+	suite.True(acquired)
+
+	deleted, err := suite.repo.DeleteExpired(expired, 10)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 1, deleted)
+
+	var count int64
+	suite.db.Model(&entity.IdempotencyKey{}).Count(&count)
+	assert.Equal(suite.T(), int64(1), count)
+}
+
+func TestIdempotencyRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyRepositoryTestSuite))
+}
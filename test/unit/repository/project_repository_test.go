@@ -0,0 +1,94 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/repository"
+	"github.com/vinibsi/todo-api/pkg/database"
+	"gorm.io/gorm"
+)
+
+type ProjectRepositoryTestSuite struct {
+	suite.Suite
+	db       *gorm.DB
+	repo     repository.ProjectRepository
+	todoRepo repository.TodoRepository
+}
+
+func (suite *ProjectRepositoryTestSuite) SetupSuite() {
+	db, err := database.ConnectTest()
+	suite.Require().NoError(err)
+
+	suite.db = db
+	suite.repo = repository.NewProjectRepository(db)
+	suite.todoRepo = repository.NewTodoRepository(db)
+}
+
+func (suite *ProjectRepositoryTestSuite) TearDownTest() {
+	suite.db.Exec("DELETE FROM todos")
+	suite.db.Exec("DELETE FROM projects")
+}
+
+func (suite *ProjectRepositoryTestSuite) createProjectWithTodo() (*entity.Project, *entity.Todo) {
+	project := &entity.Project{UserID: 1, Name: "Work"}
+	suite.Require().NoError(suite.repo.Create(project))
+
+	todo := &entity.Todo{UserID: 1, ProjectID: &project.ID, Title: "Report", Priority: "high"}
+	suite.Require().NoError(suite.todoRepo.Create(todo))
+
+	return project, todo
+}
+
+func (suite *ProjectRepositoryTestSuite) TestList_HidesArchived() {
+	suite.repo.Create(&entity.Project{UserID: 1, Name: "Active"})
+	suite.repo.Create(&entity.Project{UserID: 1, Name: "Old", Archived: true})
+	suite.repo.Create(&entity.Project{UserID: 2, Name: "Someone else's"})
+
+	projects, err := suite.repo.List(1, false)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), projects, 1)
+
+	projects, err = suite.repo.List(1, true)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), projects, 2)
+}
+
+func (suite *ProjectRepositoryTestSuite) TestDelete_MovesTodosToInbox() {
+	project, todo := suite.createProjectWithTodo()
+
+	err := suite.repo.Delete(1, project.ID, false)
+	assert.NoError(suite.T(), err)
+
+	found, err := suite.todoRepo.GetByID(1, todo.ID)
+	suite.Require().NoError(err)
+	assert.Nil(suite.T(), found.ProjectID)
+}
+
+func (suite *ProjectRepositoryTestSuite) TestDelete_Cascade() {
+	project, todo := suite.createProjectWithTodo()
+
+	err := suite.repo.Delete(1, project.ID, true)
+	assert.NoError(suite.T(), err)
+
+	_, err = suite.todoRepo.GetByID(1, todo.ID)
+	assert.ErrorIs(suite.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (suite *ProjectRepositoryTestSuite) TestDelete_OtherUser() {
+	project, todo := suite.createProjectWithTodo()
+
+	err := suite.repo.Delete(2, project.ID, true)
+	assert.ErrorIs(suite.T(), err, gorm.ErrRecordNotFound)
+
+	// A transação é desfeita e nada é alterado
+	found, err := suite.todoRepo.GetByID(1, todo.ID)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), project.ID, *found.ProjectID)
+}
+
+func TestProjectRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(ProjectRepositoryTestSuite))
+}
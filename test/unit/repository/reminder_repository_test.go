@@ -0,0 +1,133 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/repository"
+	"github.com/vinibsi/todo-api/pkg/database"
+	"gorm.io/gorm"
+)
+
+type ReminderRepositoryTestSuite struct {
+	suite.Suite
+	db       *gorm.DB
+	repo     repository.ReminderRepository
+	todoRepo repository.TodoRepository
+	now      time.Time
+}
+
+func (suite *ReminderRepositoryTestSuite) SetupSuite() {
+	db, err := database.ConnectTest()
+	suite.Require().NoError(err)
+
+	suite.db = db
+	suite.repo = repository.NewReminderRepository(db)
+	suite.todoRepo = repository.NewTodoRepository(db)
+	suite.now = time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func (suite *ReminderRepositoryTestSuite) TearDownTest() {
+	suite.db.Exec("DELETE FROM reminders")
+	suite.db.Exec("DELETE FROM todos")
+}
+
+func (suite *ReminderRepositoryTestSuite) createReminder(todo *entity.Todo, fireAt time.Time) *entity.Reminder {
+	if todo.ID == 0 {
+		suite.Require().NoError(suite.todoRepo.Create(todo))
+	}
+	reminder := &entity.Reminder{UserID: todo.UserID, TodoID: todo.ID, RemindAt: &fireAt, FireAt: fireAt}
+	suite.Require().NoError(suite.repo.Create(reminder))
+	return reminder
+}
+
+func (suite *ReminderRepositoryTestSuite) TestClaimDue() {
+	due := suite.createReminder(&entity.Todo{UserID: 1, Title: "Due"}, suite.now.Add(-time.Minute))
+	suite.createReminder(&entity.Todo{UserID: 1, Title: "Future"}, suite.now.Add(time.Hour))
+	suite.createReminder(&entity.Todo{UserID: 1, Title: "Done", Completed: true}, suite.now.Add(-time.Minute))
+
+	claimed, err := suite.repo.ClaimDue(suite.now, 10, time.Minute)
+	suite.Require().NoError(err)
+	suite.Require().Len(claimed, 1)
+	assert.Equal(suite.T(), due.ID, claimed[0].ID)
+	assert.Equal(suite.T(), "Due", claimed[0].Todo.Title)
+	stale := *claimed[0].ClaimedUntil
+
+	// Enquanto a reserva vale, outra instância não recebe o mesmo lembrete
+	claimed, err = suite.repo.ClaimDue(suite.now.Add(30*time.Second), 10, time.Minute)
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), claimed)
+
+	// Reserva expirada: o lembrete volta a ser elegível
+	claimed, err = suite.repo.ClaimDue(suite.now.Add(2*time.Minute), 10, time.Minute)
+	suite.Require().NoError(err)
+	suite.Require().Len(claimed, 1)
+
+	// A instância que perdeu a reserva não renova nem marca o lembrete
+	assert.ErrorIs(suite.T(), suite.repo.RenewClaim(due.ID, stale, suite.now.Add(5*time.Minute)), repository.ErrClaimLost)
+	assert.ErrorIs(suite.T(), suite.repo.MarkSent(due.ID, stale, suite.now), repository.ErrClaimLost)
+
+	renewed := suite.now.Add(10 * time.Minute)
+	suite.Require().NoError(suite.repo.RenewClaim(due.ID, *claimed[0].ClaimedUntil, renewed))
+	claimed, err = suite.repo.ClaimDue(suite.now.Add(5*time.Minute), 10, time.Minute)
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), claimed)
+
+	suite.Require().NoError(suite.repo.MarkSent(due.ID, renewed, suite.now))
+	claimed, err = suite.repo.ClaimDue(suite.now.Add(time.Hour), 10, time.Minute)
+	suite.Require().NoError(err)
+	assert.Len(suite.T(), claimed, 1)
+	assert.Equal(suite.T(), "Future", claimed[0].Todo.Title)
+}
+
+func (suite *ReminderRepositoryTestSuite) TestMarkFailed() {
+	reminder := suite.createReminder(&entity.Todo{UserID: 1, Title: "Flaky"}, suite.now.Add(-time.Minute))
+
+	claimed, _ := suite.repo.ClaimDue(suite.now, 10, time.Minute)
+	suite.Require().Len(claimed, 1)
+	retryAt := suite.now.Add(2 * time.Minute)
+	suite.Require().NoError(suite.repo.MarkFailed(reminder.ID, *claimed[0].ClaimedUntil, "timeout", suite.now, &retryAt))
+
+	claimed, _ = suite.repo.ClaimDue(suite.now.Add(time.Minute), 10, time.Minute)
+	assert.Empty(suite.T(), claimed)
+	claimed, _ = suite.repo.ClaimDue(retryAt.Add(time.Second), 10, time.Minute)
+	suite.Require().Len(claimed, 1)
+	assert.Equal(suite.T(), 1, claimed[0].Attempts)
+	assert.Equal(suite.T(), "timeout", claimed[0].LastError)
+
+	// Sem nova tentativa o lembrete é abandonado
+	suite.Require().NoError(suite.repo.MarkFailed(reminder.ID, *claimed[0].ClaimedUntil, "timeout", suite.now, nil))
+	claimed, _ = suite.repo.ClaimDue(suite.now.Add(time.Hour), 10, time.Minute)
+	assert.Empty(suite.T(), claimed)
+}
+
+func (suite *ReminderRepositoryTestSuite) TestRelativeReminderFollowsDueDate() {
+	due := suite.now.Add(24 * time.Hour)
+	todo := &entity.Todo{UserID: 1, Title: "Recurring", DueDate: &due}
+	suite.Require().NoError(suite.todoRepo.Create(todo))
+
+	minutes := 30
+	reminder := &entity.Reminder{UserID: 1, TodoID: todo.ID, MinutesBefore: &minutes, FireAt: due.Add(-30 * time.Minute)}
+	suite.Require().NoError(suite.repo.Create(reminder))
+	claimed, err := suite.repo.ClaimDue(due, 10, time.Minute)
+	suite.Require().NoError(err)
+	suite.Require().Len(claimed, 1)
+	suite.Require().NoError(suite.repo.MarkSent(reminder.ID, *claimed[0].ClaimedUntil, suite.now))
+
+	next := due.Add(7 * 24 * time.Hour)
+	todo.DueDate = &next
+	suite.Require().NoError(suite.todoRepo.Update(todo))
+
+	reminders, err := suite.repo.ListByTodo(1, todo.ID)
+	suite.Require().NoError(err)
+	suite.Require().Len(reminders, 1)
+	assert.True(suite.T(), next.Add(-30*time.Minute).Equal(reminders[0].FireAt))
+	assert.Nil(suite.T(), reminders[0].SentAt)
+}
+
+func TestReminderRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(ReminderRepositoryTestSuite))
+}
@@ -0,0 +1,119 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/repository"
+	"github.com/vinibsi/todo-api/pkg/database"
+	"gorm.io/gorm"
+)
+
+type TagRepositoryTestSuite struct {
+	suite.Suite
+	db       *gorm.DB
+	repo     repository.TagRepository
+	todoRepo repository.TodoRepository
+}
+
+func (suite *TagRepositoryTestSuite) SetupSuite() {
+	db, err := database.ConnectTest()
+	suite.Require().NoError(err)
+
+	suite.db = db
+	suite.repo = repository.NewTagRepository(db)
+	suite.todoRepo = repository.NewTodoRepository(db)
+}
+
+func (suite *TagRepositoryTestSuite) TearDownTest() {
+	suite.db.Exec("DELETE FROM todo_tags")
+	suite.db.Exec("DELETE FROM todos")
+	suite.db.Exec("DELETE FROM tags")
+}
+
+func (suite *TagRepositoryTestSuite) createTodo(title string, tags ...string) *entity.Todo {
+	resolved, err := suite.repo.FindOrCreate(1, tags)
+	suite.Require().NoError(err)
+
+	todo := &entity.Todo{UserID: 1, Title: title, Priority: "medium", Tags: resolved}
+	suite.Require().NoError(suite.todoRepo.Create(todo))
+	return todo
+}
+
+func (suite *TagRepositoryTestSuite) titles(query repository.TodoQuery) []string {
+	query.UserID = 1
+	query.Limit = 10
+	todos, _, err := suite.todoRepo.GetAll(query)
+	suite.Require().NoError(err)
+
+	titles := make([]string, len(todos))
+	for i, todo := range todos {
+		titles[i] = todo.Title
+	}
+	return titles
+}
+
+func (suite *TagRepositoryTestSuite) TestFindOrCreate_UniquePerOwner() {
+	first, err := suite.repo.FindOrCreate(1, []string{"work", "urgent"})
+	suite.Require().NoError(err)
+
+	second, err := suite.repo.FindOrCreate(1, []string{"urgent"})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), first[0].ID, second[0].ID)
+
+	other, err := suite.repo.FindOrCreate(2, []string{"urgent"})
+	suite.Require().NoError(err)
+	assert.NotEqual(suite.T(), first[0].ID, other[0].ID)
+}
+
+func (suite *TagRepositoryTestSuite) TestGetAll_FilterByTags() {
+	suite.createTodo("Both", "work", "urgent")
+	suite.createTodo("Work only", "work")
+	suite.createTodo("Untagged")
+
+	matchAny := suite.titles(repository.TodoQuery{Tags: []string{"work", "urgent"}, Sort: []repository.SortField{{Field: "title"}}})
+	assert.Equal(suite.T(), []string{"Both", "Work only"}, matchAny)
+
+	matchAll := suite.titles(repository.TodoQuery{Tags: []string{"work", "urgent"}, TagMatch: repository.TagMatchAll})
+	assert.Equal(suite.T(), []string{"Both"}, matchAll)
+}
+
+func (suite *TagRepositoryTestSuite) TestUpdate_ReplacesTags() {
+	todo := suite.createTodo("Report", "work", "urgent")
+
+	todo.Tags = todo.Tags[:1]
+	suite.Require().NoError(suite.todoRepo.Update(todo))
+
+	found, err := suite.todoRepo.GetByID(1, todo.ID)
+	suite.Require().NoError(err)
+	assert.Len(suite.T(), found.Tags, 1)
+
+	found.Tags = nil
+	suite.Require().NoError(suite.todoRepo.Update(found))
+
+	found, err = suite.todoRepo.GetByID(1, todo.ID)
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), found.Tags)
+}
+
+func (suite *TagRepositoryTestSuite) TestListWithUsage() {
+	suite.createTodo("Report", "work", "urgent")
+	deleted := suite.createTodo("Meeting", "work")
+	suite.createTodo("Call", "work")
+	suite.Require().NoError(suite.todoRepo.Delete(1, deleted.ID, deleted.Version))
+
+	usage, err := suite.repo.ListWithUsage(1)
+	suite.Require().NoError(err)
+	suite.Require().Len(usage, 2)
+
+	assert.Equal(suite.T(), "urgent", usage[0].Name)
+	assert.Equal(suite.T(), int64(1), usage[0].Count)
+	assert.Equal(suite.T(), "work", usage[1].Name)
+	assert.Equal(suite.T(), int64(2), usage[1].Count)
+}
+
+func TestTagRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(TagRepositoryTestSuite))
+}
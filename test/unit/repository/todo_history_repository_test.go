@@ -0,0 +1,72 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/repository"
+	"github.com/vinibsi/todo-api/pkg/database"
+	"gorm.io/gorm"
+)
+
+type TodoHistoryRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo repository.TodoHistoryRepository
+}
+
+func (suite *TodoHistoryRepositoryTestSuite) SetupSuite() {
+	db, err := database.ConnectTest()
+	suite.Require().NoError(err)
+
+	suite.db = db
+	suite.repo = repository.NewTodoHistoryRepository(db)
+}
+
+func (suite *TodoHistoryRepositoryTestSuite) TearDownTest() {
+	suite.db.Exec("DELETE FROM todo_histories")
+}
+
+func (suite *TodoHistoryRepositoryTestSuite) record(userID, todoID uint, operation string) *entity.TodoHistory {
+	entry := &entity.TodoHistory{TodoID: todoID, UserID: userID, ActorID: userID, Operation: operation, Snapshot: "{}"}
+	suite.Require().NoError(suite.repo.Record(entry))
+	return entry
+}
+
+func (suite *TodoHistoryRepositoryTestSuite) TestRecordNumbersRevisionsPerTodo() {
+	assert.Equal(suite.T(), 1, suite.record(1, 10, entity.HistoryCreate).Revision)
+	assert.Equal(suite.T(), 2, suite.record(1, 10, entity.HistoryUpdate).Revision)
+	assert.Equal(suite.T(), 1, suite.record(1, 11, entity.HistoryCreate).Revision)
+	assert.Equal(suite.T(), 3, suite.record(1, 10, entity.HistoryDelete).Revision)
+
+	entries, err := suite.repo.List(1, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(entries, 3)
+	assert.Equal(suite.T(), []string{entity.HistoryDelete, entity.HistoryUpdate, entity.HistoryCreate},
+		[]string{entries[0].Operation, entries[1].Operation, entries[2].Operation})
+
+	// O histórico só é visível para o dono da tarefa
+	entries, err = suite.repo.List(2, 10)
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), entries)
+}
+
+func (suite *TodoHistoryRepositoryTestSuite) TestGetRevision() {
+	suite.record(1, 10, entity.HistoryCreate)
+	suite.record(1, 10, entity.HistoryUpdate)
+
+	entry, err := suite.repo.GetRevision(1, 10, 2)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), entity.HistoryUpdate, entry.Operation)
+
+	_, err = suite.repo.GetRevision(1, 10, 3)
+	assert.ErrorIs(suite.T(), err, gorm.ErrRecordNotFound)
+	_, err = suite.repo.GetRevision(2, 10, 1)
+	assert.ErrorIs(suite.T(), err, gorm.ErrRecordNotFound)
+}
+
+func TestTodoHistoryRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(TodoHistoryRepositoryTestSuite))
+}
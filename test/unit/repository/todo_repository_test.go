@@ -2,6 +2,7 @@ package repository_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -55,7 +56,7 @@ func (suite *TodoRepositoryTestSuite) TestGetByID() {
 	suite.repo.Create(todo)
 
 	// Busca o todo
-	found, err := suite.repo.GetByID(todo.ID)
+	found, err := suite.repo.GetByID(todo.UserID, todo.ID)
 
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), todo.Title, found.Title)
@@ -76,13 +77,200 @@ func (suite *TodoRepositoryTestSuite) TestGetAll() {
 	}
 
 	// Busca todos
-	result, total, err := suite.repo.GetAll(10, 0)
+	result, total, err := suite.repo.GetAll(repository.TodoQuery{Limit: 10})
 
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), int64(3), total)
 	assert.Len(suite.T(), result, 3)
 }
 
+func (suite *TodoRepositoryTestSuite) TestOwnership() {
+	mine := &entity.Todo{UserID: 1, Title: "Mine", Priority: "low"}
+	theirs := &entity.Todo{UserID: 2, Title: "Theirs", Priority: "low"}
+	suite.repo.Create(mine)
+	suite.repo.Create(theirs)
+
+	// Tarefas de outro usuário se comportam como inexistentes
+	_, err := suite.repo.GetByID(1, theirs.ID)
+	assert.ErrorIs(suite.T(), err, gorm.ErrRecordNotFound)
+
+	result, total, err := suite.repo.GetAll(repository.TodoQuery{UserID: 1, Limit: 10})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), total)
+	assert.Equal(suite.T(), "Mine", result[0].Title)
+
+	_, total, err = suite.repo.Search("theirs", repository.TodoQuery{UserID: 1, Limit: 10})
+	assert.NoError(suite.T(), err)
+	assert.Zero(suite.T(), total)
+
+	// Remoção e atualização com o dono errado não afetam a tarefa
+	assert.ErrorIs(suite.T(), suite.repo.Delete(1, theirs.ID, theirs.Version), repository.ErrStaleVersion)
+	theirs.UserID = 1
+	theirs.Title = "Hijacked"
+	assert.ErrorIs(suite.T(), suite.repo.Update(theirs), repository.ErrStaleVersion)
+
+	found, err := suite.repo.GetByID(2, theirs.ID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Theirs", found.Title)
+}
+
+func (suite *TodoRepositoryTestSuite) TestGetAll_WithFilters() {
+	now := time.Now()
+	yesterday := now.Add(-24 * time.Hour)
+	nextWeek := now.Add(7 * 24 * time.Hour)
+
+	todos := []*entity.Todo{
+		{Title: "Overdue", Priority: "high", DueDate: &yesterday},
+		{Title: "Done late", Priority: "high", DueDate: &yesterday, Completed: true},
+		{Title: "Upcoming", Priority: "low", DueDate: &nextWeek},
+		{Title: "No due date", Priority: "high"},
+	}
+	for _, todo := range todos {
+		suite.repo.Create(todo)
+	}
+
+	completed := false
+	result, total, err := suite.repo.GetAll(repository.TodoQuery{Completed: &completed, Priority: "high", Limit: 10})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), total)
+	assert.Len(suite.T(), result, 2)
+
+	result, total, err = suite.repo.GetAll(repository.TodoQuery{Overdue: true, Limit: 10})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), total)
+	assert.Equal(suite.T(), "Overdue", result[0].Title)
+
+	result, total, err = suite.repo.GetAll(repository.TodoQuery{DueAfter: &now, Limit: 10})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), total)
+	assert.Equal(suite.T(), "Upcoming", result[0].Title)
+
+	// O total reflete os filtros, não apenas a página retornada
+	result, total, err = suite.repo.GetAll(repository.TodoQuery{DueBefore: &now, Limit: 1})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), total)
+	assert.Len(suite.T(), result, 1)
+}
+
+func (suite *TodoRepositoryTestSuite) TestGetAll_SortByPriority() {
+	todos := []*entity.Todo{
+		{Title: "B", Priority: "low"},
+		{Title: "A", Priority: "high"},
+		{Title: "C", Priority: "medium"},
+		{Title: "D", Priority: "high"},
+	}
+	for _, todo := range todos {
+		suite.repo.Create(todo)
+	}
+
+	sortFields, err := repository.ParseTodoSort("-priority,title")
+	suite.Require().NoError(err)
+
+	result, _, err := suite.repo.GetAll(repository.TodoQuery{Sort: sortFields, Limit: 10})
+
+	assert.NoError(suite.T(), err)
+	titles := make([]string, len(result))
+	for i, todo := range result {
+		titles[i] = todo.Title
+	}
+	assert.Equal(suite.T(), []string{"A", "D", "C", "B"}, titles)
+}
+
+func (suite *TodoRepositoryTestSuite) TestGetAll_KeysetPagination() {
+	now := time.Now()
+	tomorrow := now.Add(24 * time.Hour)
+
+	todos := []*entity.Todo{
+		{Title: "A", Priority: "high", DueDate: &tomorrow},
+		{Title: "B", Priority: "high"},
+		{Title: "C", Priority: "low", DueDate: &now},
+		{Title: "D", Priority: "high", DueDate: &now},
+		{Title: "E", Priority: "medium"},
+	}
+	for _, todo := range todos {
+		suite.repo.Create(todo)
+	}
+
+	sortFields, err := repository.ParseTodoSort("-priority,due_date")
+	suite.Require().NoError(err)
+
+	var titles []string
+	var cursor *repository.TodoCursor
+	for page := 0; page < 5; page++ {
+		result, _, err := suite.repo.GetAll(repository.TodoQuery{Sort: sortFields, After: cursor, SkipCount: true, Limit: 2})
+		suite.Require().NoError(err)
+		if len(result) == 0 {
+			break
+		}
+
+		for _, todo := range result {
+			titles = append(titles, todo.Title)
+		}
+
+		cursor, err = repository.DecodeTodoCursor(repository.EncodeTodoCursor(sortFields, &result[len(result)-1]), sortFields)
+		suite.Require().NoError(err)
+	}
+
+	assert.Equal(suite.T(), []string{"D", "A", "B", "E", "C"}, titles)
+}
+
+func (suite *TodoRepositoryTestSuite) TestDecodeTodoCursor_SortMismatch() {
+	todo := &entity.Todo{ID: 1, Title: "A", Priority: "high", CreatedAt: time.Now()}
+	titleSort, _ := repository.ParseTodoSort("title")
+	prioritySort, _ := repository.ParseTodoSort("priority")
+
+	_, err := repository.DecodeTodoCursor(repository.EncodeTodoCursor(titleSort, todo), prioritySort)
+	assert.ErrorIs(suite.T(), err, repository.ErrInvalidCursor)
+
+	_, err = repository.DecodeTodoCursor("not-a-cursor", titleSort)
+	assert.ErrorIs(suite.T(), err, repository.ErrInvalidCursor)
+}
+
+func (suite *TodoRepositoryTestSuite) TestParseTodoSort_Invalid() {
+	_, err := repository.ParseTodoSort("priority,password")
+	assert.ErrorContains(suite.T(), err, "allowed fields")
+
+	_, err = repository.ParseTodoSort("title,-title")
+	assert.Error(suite.T(), err)
+}
+
+func (suite *TodoRepositoryTestSuite) TestSearch() {
+	todos := []*entity.Todo{
+		{Title: "Buy groceries", Description: "milk, eggs and bread", Priority: "low"},
+		{Title: "Milk the budget", Description: "review expenses", Priority: "high"},
+		{Title: "Call mom", Description: "about the weekend", Priority: "medium"},
+	}
+	for _, todo := range todos {
+		suite.repo.Create(todo)
+	}
+
+	results, total, err := suite.repo.Search("milk", repository.TodoQuery{Limit: 10})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), total)
+	suite.Require().Len(results, 2)
+	// Ocorrências no título pesam mais que na descrição
+	assert.Equal(suite.T(), "Milk the budget", results[0].Title)
+	assert.Greater(suite.T(), results[0].Rank, results[1].Rank)
+	assert.Contains(suite.T(), results[1].Snippet, "<mark>")
+
+	// A busca respeita os demais filtros
+	results, total, err = suite.repo.Search("milk", repository.TodoQuery{Priority: "low", Limit: 10})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), total)
+	assert.Equal(suite.T(), "Buy groceries", results[0].Title)
+
+	// Operadores e pontuação não quebram a consulta
+	_, _, err = suite.repo.Search(`milk" OR -(`, repository.TodoQuery{Limit: 10})
+	assert.NoError(suite.T(), err)
+
+	// Tarefas removidas não aparecem
+	suite.repo.Delete(todos[1].UserID, todos[1].ID, todos[1].Version)
+	_, total, err = suite.repo.Search("milk", repository.TodoQuery{Limit: 10})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), total)
+}
+
 func (suite *TodoRepositoryTestSuite) TestUpdate() {
 	// Cria um todo
 	todo := &entity.Todo{
@@ -100,11 +288,38 @@ func (suite *TodoRepositoryTestSuite) TestUpdate() {
 	assert.NoError(suite.T(), err)
 
 	// Verifica a atualização
-	updated, _ := suite.repo.GetByID(todo.ID)
+	updated, _ := suite.repo.GetByID(todo.UserID, todo.ID)
 	assert.Equal(suite.T(), "Updated Title", updated.Title)
 	assert.True(suite.T(), updated.Completed)
 }
 
+func (suite *TodoRepositoryTestSuite) TestUpdate_StaleVersion() {
+	todo := &entity.Todo{UserID: 1, Title: "Original", Priority: "low"}
+	suite.Require().NoError(suite.repo.Create(todo))
+	assert.Equal(suite.T(), 1, todo.Version)
+
+	// Dois editores leem a mesma versão; só a primeira gravação vale
+	first, err := suite.repo.GetByID(1, todo.ID)
+	suite.Require().NoError(err)
+	second, err := suite.repo.GetByID(1, todo.ID)
+	suite.Require().NoError(err)
+
+	first.Title = "First"
+	suite.Require().NoError(suite.repo.Update(first))
+	assert.Equal(suite.T(), 2, first.Version)
+
+	second.Title = "Second"
+	assert.ErrorIs(suite.T(), suite.repo.Update(second), repository.ErrStaleVersion)
+	assert.Equal(suite.T(), 1, second.Version)
+	assert.ErrorIs(suite.T(), suite.repo.Delete(1, todo.ID, second.Version), repository.ErrStaleVersion)
+	assert.ErrorIs(suite.T(), suite.repo.Purge(1, todo.ID, second.Version), repository.ErrStaleVersion)
+
+	found, err := suite.repo.GetByID(1, todo.ID)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "First", found.Title)
+	assert.Equal(suite.T(), 2, found.Version)
+}
+
 func (suite *TodoRepositoryTestSuite) TestDelete() {
 	// Cria um todo
 	todo := &entity.Todo{
@@ -114,14 +329,166 @@ func (suite *TodoRepositoryTestSuite) TestDelete() {
 	suite.repo.Create(todo)
 
 	// Deleta o todo
-	err := suite.repo.Delete(todo.ID)
+	err := suite.repo.Delete(todo.UserID, todo.ID, todo.Version)
 	assert.NoError(suite.T(), err)
 
 	// Verifica se foi deletado (soft delete)
-	_, err = suite.repo.GetByID(todo.ID)
+	_, err = suite.repo.GetByID(todo.UserID, todo.ID)
 	assert.Error(suite.T(), err)
 }
 
+func (suite *TodoRepositoryTestSuite) TestSubtasks() {
+	root := &entity.Todo{UserID: 1, Title: "Release", Priority: "high"}
+	suite.Require().NoError(suite.repo.Create(root))
+
+	done := &entity.Todo{UserID: 1, ParentID: &root.ID, Title: "Changelog", Completed: true}
+	open := &entity.Todo{UserID: 1, ParentID: &root.ID, Title: "Tag version"}
+	suite.Require().NoError(suite.repo.Create(done))
+	suite.Require().NoError(suite.repo.Create(open))
+
+	grandchild := &entity.Todo{UserID: 1, ParentID: &open.ID, Title: "Sign tag"}
+	suite.Require().NoError(suite.repo.Create(grandchild))
+
+	found, err := suite.repo.GetByID(1, root.ID)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 2, found.SubtasksTotal)
+	assert.Equal(suite.T(), 1, found.SubtasksDone)
+
+	descendants, err := suite.repo.Descendants(1, root.ID)
+	suite.Require().NoError(err)
+	assert.Len(suite.T(), descendants, 3)
+
+	children, _, err := suite.repo.GetAll(repository.TodoQuery{UserID: 1, ParentID: &root.ID, Limit: 10})
+	suite.Require().NoError(err)
+	assert.Len(suite.T(), children, 2)
+
+	// Remover a tarefa raiz remove toda a hierarquia
+	suite.Require().NoError(suite.repo.Delete(1, root.ID, root.Version))
+	_, err = suite.repo.GetByID(1, grandchild.ID)
+	assert.ErrorIs(suite.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (suite *TodoRepositoryTestSuite) TestTrashAndRestore() {
+	parent := &entity.Todo{UserID: 1, Title: "Parent"}
+	suite.Require().NoError(suite.repo.Create(parent))
+	child := &entity.Todo{UserID: 1, Title: "Child", ParentID: &parent.ID}
+	suite.Require().NoError(suite.repo.Create(child))
+	early := &entity.Todo{UserID: 1, Title: "Removed first", ParentID: &parent.ID}
+	suite.Require().NoError(suite.repo.Create(early))
+
+	suite.Require().NoError(suite.repo.Delete(1, early.ID, early.Version))
+	suite.Require().NoError(suite.repo.Delete(1, parent.ID, parent.Version))
+
+	// A subtarefa removida junto com a tarefa pai não aparece sozinha na lixeira
+	trash, total, err := suite.repo.ListDeleted(1, 10, 0)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), int64(2), total)
+	suite.Require().Len(trash, 2)
+	assert.Equal(suite.T(), "Parent", trash[0].Title)
+	assert.Equal(suite.T(), "Removed first", trash[1].Title)
+
+	_, total, _ = suite.repo.ListDeleted(2, 10, 0)
+	assert.Zero(suite.T(), total)
+
+	suite.Require().NoError(suite.repo.Restore(1, parent.ID))
+	_, err = suite.repo.GetByID(1, child.ID)
+	assert.NoError(suite.T(), err)
+	_, err = suite.repo.GetByID(1, early.ID)
+	assert.ErrorIs(suite.T(), err, gorm.ErrRecordNotFound)
+
+	deleted, err := suite.repo.GetDeleted(1, early.ID)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "Removed first", deleted.Title)
+	assert.ErrorIs(suite.T(), suite.repo.Restore(1, parent.ID), gorm.ErrRecordNotFound)
+}
+
+func (suite *TodoRepositoryTestSuite) TestPurge() {
+	parent := &entity.Todo{UserID: 1, Title: "Parent"}
+	suite.Require().NoError(suite.repo.Create(parent))
+	child := &entity.Todo{UserID: 1, Title: "Child", ParentID: &parent.ID}
+	suite.Require().NoError(suite.repo.Create(child))
+	suite.Require().NoError(suite.repo.Delete(1, child.ID, child.Version))
+
+	assert.ErrorIs(suite.T(), suite.repo.Purge(2, parent.ID, parent.Version), gorm.ErrRecordNotFound)
+	suite.Require().NoError(suite.repo.Purge(1, parent.ID, parent.Version))
+
+	var count int64
+	suite.db.Unscoped().Model(&entity.Todo{}).Count(&count)
+	assert.Zero(suite.T(), count)
+}
+
+func (suite *TodoRepositoryTestSuite) TestRestoreChildThenPurgeParent() {
+	parent := &entity.Todo{UserID: 1, Title: "Parent"}
+	suite.Require().NoError(suite.repo.Create(parent))
+	child := &entity.Todo{UserID: 1, Title: "Child", ParentID: &parent.ID}
+	suite.Require().NoError(suite.repo.Create(child))
+	suite.Require().NoError(suite.repo.Delete(1, parent.ID, parent.Version))
+
+	// A subtarefa não volta enquanto a tarefa pai está na lixeira
+	assert.ErrorIs(suite.T(), suite.repo.Restore(1, child.ID), repository.ErrParentDeleted)
+	_, err := suite.repo.GetDeleted(1, child.ID)
+	suite.Require().NoError(err)
+
+	// Bancos que já têm uma subtarefa ativa sob a tarefa pai na lixeira não a
+	// perdem na limpeza: ela passa para a raiz
+	suite.db.Unscoped().Model(child).Update("deleted_at", nil)
+	purged, err := suite.repo.PurgeDeletedBefore(time.Now().Add(time.Hour), 10)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 1, purged)
+
+	stored, err := suite.repo.GetByID(1, child.ID)
+	suite.Require().NoError(err)
+	assert.Nil(suite.T(), stored.ParentID)
+}
+
+func (suite *TodoRepositoryTestSuite) TestPurge_TrashedParentKeepsActiveChildren() {
+	parent := &entity.Todo{UserID: 1, Title: "Parent"}
+	suite.Require().NoError(suite.repo.Create(parent))
+	active := &entity.Todo{UserID: 1, Title: "Active", ParentID: &parent.ID}
+	suite.Require().NoError(suite.repo.Create(active))
+	trashed := &entity.Todo{UserID: 1, Title: "Trashed", ParentID: &parent.ID}
+	suite.Require().NoError(suite.repo.Create(trashed))
+	suite.Require().NoError(suite.repo.Delete(1, parent.ID, parent.Version))
+	suite.db.Unscoped().Model(active).Update("deleted_at", nil)
+
+	deleted, err := suite.repo.GetDeleted(1, parent.ID)
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.repo.Purge(1, parent.ID, deleted.Version))
+
+	_, err = suite.repo.GetDeleted(1, trashed.ID)
+	assert.ErrorIs(suite.T(), err, gorm.ErrRecordNotFound)
+	stored, err := suite.repo.GetByID(1, active.ID)
+	suite.Require().NoError(err)
+	assert.Nil(suite.T(), stored.ParentID)
+}
+
+func (suite *TodoRepositoryTestSuite) TestPurgeDeletedBefore() {
+	old := &entity.Todo{UserID: 1, Title: "Old"}
+	recent := &entity.Todo{UserID: 1, Title: "Recent"}
+	active := &entity.Todo{UserID: 1, Title: "Active"}
+	for _, todo := range []*entity.Todo{old, recent, active} {
+		suite.Require().NoError(suite.repo.Create(todo))
+	}
+	now := time.Now()
+	suite.db.Unscoped().Model(old).Update("deleted_at", now.Add(-40*24*time.Hour))
+	suite.db.Unscoped().Model(recent).Update("deleted_at", now.Add(-time.Hour))
+
+	purged, err := suite.repo.PurgeDeletedBefore(now.Add(-30*24*time.Hour), 10)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 1, purged)
+
+	_, err = suite.repo.GetDeleted(1, old.ID)
+	assert.ErrorIs(suite.T(), err, gorm.ErrRecordNotFound)
+	_, err = suite.repo.GetDeleted(1, recent.ID)
+	assert.NoError(suite.T(), err)
+	_, err = suite.repo.GetByID(1, active.ID)
+	assert.NoError(suite.T(), err)
+
+	purged, err = suite.repo.PurgeDeletedBefore(now.Add(-30*24*time.Hour), 10)
+	suite.Require().NoError(err)
+	assert.Zero(suite.T(), purged)
+}
+
 func TestTodoRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(TodoRepositoryTestSuite))
 }
@@ -0,0 +1,53 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/repository"
+	"github.com/vinibsi/todo-api/pkg/database"
+	"gorm.io/gorm"
+)
+
+type UserRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo repository.UserRepository
+}
+
+func (suite *UserRepositoryTestSuite) SetupSuite() {
+	db, err := database.ConnectTest()
+	suite.Require().NoError(err)
+
+	suite.db = db
+	suite.repo = repository.NewUserRepository(db)
+}
+
+func (suite *UserRepositoryTestSuite) TearDownTest() {
+	suite.db.Exec("DELETE FROM users")
+}
+
+func (suite *UserRepositoryTestSuite) TestCreateAndGetByEmail() {
+	user := &entity.User{Name: "Ana", Email: "ana@example.com", PasswordHash: "hash"}
+
+	err := suite.repo.Create(user)
+	assert.NoError(suite.T(), err)
+	assert.NotZero(suite.T(), user.ID)
+
+	found, err := suite.repo.GetByEmail("ana@example.com")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), user.ID, found.ID)
+}
+
+func (suite *UserRepositoryTestSuite) TestCreate_DuplicateEmail() {
+	suite.repo.Create(&entity.User{Name: "Ana", Email: "ana@example.com", PasswordHash: "hash"})
+
+	err := suite.repo.Create(&entity.User{Name: "Other", Email: "ana@example.com", PasswordHash: "hash"})
+	assert.Error(suite.T(), err)
+}
+
+func TestUserRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(UserRepositoryTestSuite))
+}
@@ -0,0 +1,143 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/repository"
+	"github.com/vinibsi/todo-api/pkg/database"
+	"gorm.io/gorm"
+)
+
+type WebhookRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo repository.WebhookRepository
+	now  time.Time
+}
+
+func (suite *WebhookRepositoryTestSuite) SetupSuite() {
+	db, err := database.ConnectTest()
+	suite.Require().NoError(err)
+
+	suite.db = db
+	suite.repo = repository.NewWebhookRepository(db)
+	suite.now = time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func (suite *WebhookRepositoryTestSuite) TearDownTest() {
+	suite.db.Exec("DELETE FROM webhook_attempts")
+	suite.db.Exec("DELETE FROM webhook_deliveries")
+	suite.db.Exec("DELETE FROM webhooks")
+}
+
+func (suite *WebhookRepositoryTestSuite) createWebhook(active bool) *entity.Webhook {
+	webhook := &entity.Webhook{UserID: 1, URL: "https://example.com/hook", Secret: "secret-secret-secret", Events: "todo.created", Active: active}
+	suite.Require().NoError(suite.repo.Create(webhook))
+	return webhook
+}
+
+func (suite *WebhookRepositoryTestSuite) createDelivery(webhook *entity.Webhook, nextAttemptAt time.Time) *entity.WebhookDelivery {
+	deliveries := []entity.WebhookDelivery{{
+		WebhookID:     webhook.ID,
+		Event:         "todo.created",
+		Payload:       `{"type":"todo.created"}`,
+		Status:        entity.DeliveryPending,
+		NextAttemptAt: &nextAttemptAt,
+	}}
+	suite.Require().NoError(suite.repo.CreateDeliveries(deliveries))
+	delivery, err := suite.repo.GetDelivery(webhook.ID, deliveries[0].ID)
+	suite.Require().NoError(err)
+	return delivery
+}
+
+func (suite *WebhookRepositoryTestSuite) TestClaimDue() {
+	active := suite.createWebhook(true)
+	inactive := suite.createWebhook(false)
+
+	due := suite.createDelivery(active, suite.now.Add(-time.Minute))
+	suite.createDelivery(active, suite.now.Add(time.Hour))
+	suite.createDelivery(inactive, suite.now.Add(-time.Minute))
+
+	claimed, err := suite.repo.ClaimDue(suite.now, 10, time.Minute)
+	suite.Require().NoError(err)
+	suite.Require().Len(claimed, 1)
+	assert.Equal(suite.T(), due.ID, claimed[0].ID)
+	suite.Require().NotNil(claimed[0].Webhook)
+	assert.Equal(suite.T(), "secret-secret-secret", claimed[0].Webhook.Secret)
+
+	// Enquanto a reserva vale, outra instância não recebe a mesma entrega
+	claimed, err = suite.repo.ClaimDue(suite.now.Add(30*time.Second), 10, time.Minute)
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), claimed)
+
+	// Reserva expirada: a entrega volta a ser elegível
+	claimed, err = suite.repo.ClaimDue(suite.now.Add(2*time.Minute), 10, time.Minute)
+	suite.Require().NoError(err)
+	assert.Len(suite.T(), claimed, 1)
+}
+
+func (suite *WebhookRepositoryTestSuite) TestRecordAttemptAndRedeliver() {
+	webhook := suite.createWebhook(true)
+	delivery := suite.createDelivery(webhook, suite.now)
+
+	delivery.Status = entity.DeliveryFailed
+	delivery.Attempts = 1
+	delivery.NextAttemptAt = nil
+	delivery.LastStatusCode = 500
+	delivery.LastError = "webhook responded with status 500"
+	attempt := &entity.WebhookAttempt{StatusCode: 500, Error: "boom\xff"}
+	suite.Require().NoError(suite.repo.RecordAttempt(delivery, attempt))
+
+	stored, err := suite.repo.GetDelivery(webhook.ID, delivery.ID)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), entity.DeliveryFailed, stored.Status)
+	assert.Equal(suite.T(), 1, stored.Attempts)
+	suite.Require().Len(stored.AttemptLog, 1)
+	assert.Equal(suite.T(), "boom", stored.AttemptLog[0].Error)
+
+	// Entregas com falha não são reservadas até serem reenviadas
+	claimed, err := suite.repo.ClaimDue(suite.now, 10, time.Minute)
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), claimed)
+
+	found, err := suite.repo.Redeliver(webhook.ID, delivery.ID, suite.now)
+	suite.Require().NoError(err)
+	assert.True(suite.T(), found)
+
+	claimed, err = suite.repo.ClaimDue(suite.now, 10, time.Minute)
+	suite.Require().NoError(err)
+	suite.Require().Len(claimed, 1)
+	assert.Equal(suite.T(), 0, claimed[0].Attempts)
+
+	found, err = suite.repo.Redeliver(webhook.ID+100, delivery.ID, suite.now)
+	suite.Require().NoError(err)
+	assert.False(suite.T(), found)
+}
+
+func (suite *WebhookRepositoryTestSuite) TestDeleteRemovesDeliveries() {
+	webhook := suite.createWebhook(true)
+	delivery := suite.createDelivery(webhook, suite.now)
+	suite.Require().NoError(suite.repo.RecordAttempt(delivery, &entity.WebhookAttempt{StatusCode: 200}))
+
+	deleted, err := suite.repo.Delete(2, webhook.ID)
+	suite.Require().NoError(err)
+	assert.False(suite.T(), deleted)
+
+	deleted, err = suite.repo.Delete(1, webhook.ID)
+	suite.Require().NoError(err)
+	assert.True(suite.T(), deleted)
+
+	var deliveries, attempts int64
+	suite.db.Model(&entity.WebhookDelivery{}).Count(&deliveries)
+	suite.db.Model(&entity.WebhookAttempt{}).Count(&attempts)
+	assert.Zero(suite.T(), deliveries)
+	assert.Zero(suite.T(), attempts)
+}
+
+func TestWebhookRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookRepositoryTestSuite))
+}
@@ -0,0 +1,98 @@
+package service_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/auth"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/service"
+	"github.com/vinibsi/todo-api/mocks"
+	"gorm.io/gorm"
+)
+
+type APITokenServiceTestSuite struct {
+	suite.Suite
+	mockRepo        *mocks.MockAPITokenRepository
+	apiTokenService service.APITokenService
+}
+
+func (suite *APITokenServiceTestSuite) SetupTest() {
+	suite.mockRepo = new(mocks.MockAPITokenRepository)
+	suite.apiTokenService = service.NewAPITokenService(suite.mockRepo)
+}
+
+func (suite *APITokenServiceTestSuite) TestCreate_StoresOnlyHash() {
+	var stored *entity.APIToken
+	suite.mockRepo.On("Create", mock.AnythingOfType("*entity.APIToken")).Return(nil).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*entity.APIToken)
+		stored.ID = 1
+	})
+
+	result, err := suite.apiTokenService.Create(userID, &dto.CreateAPITokenRequest{
+		Name:   "CI",
+		Scopes: []string{auth.ScopeTodosRead, auth.ScopeTodosRead},
+	})
+
+	suite.Require().NoError(err)
+	assert.True(suite.T(), strings.HasPrefix(result.Token, auth.APITokenPrefix))
+	assert.True(suite.T(), strings.HasPrefix(result.Token, result.Prefix))
+	assert.Equal(suite.T(), []string{auth.ScopeTodosRead}, result.Scopes)
+	assert.NotContains(suite.T(), stored.TokenHash, result.Token)
+	assert.Len(suite.T(), stored.TokenHash, 64)
+	assert.Equal(suite.T(), userID, stored.UserID)
+}
+
+func (suite *APITokenServiceTestSuite) TestCreate_ExpiryInThePast() {
+	past := time.Now().Add(-time.Hour)
+
+	_, err := suite.apiTokenService.Create(userID, &dto.CreateAPITokenRequest{
+		Name:      "CI",
+		Scopes:    []string{auth.ScopeTodosRead},
+		ExpiresAt: &past,
+	})
+
+	assert.ErrorIs(suite.T(), err, service.ErrInvalidExpiry)
+}
+
+func (suite *APITokenServiceTestSuite) TestAuthenticate() {
+	token := &entity.APIToken{ID: 3, UserID: userID, Scopes: "todos:read"}
+	suite.mockRepo.On("GetByHash", mock.AnythingOfType("string")).Return(token, nil)
+	suite.mockRepo.On("TouchLastUsed", uint(3), mock.AnythingOfType("time.Time"), time.Minute).Return(nil)
+
+	principal, err := suite.apiTokenService.Authenticate("tdo_whatever")
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), userID, principal.UserID)
+	assert.True(suite.T(), principal.HasScope(auth.ScopeTodosRead))
+	assert.False(suite.T(), principal.HasScope(auth.ScopeTodosWrite))
+	assert.False(suite.T(), principal.HasScope(auth.ScopeTokensManage))
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *APITokenServiceTestSuite) TestAuthenticate_Expired() {
+	past := time.Now().Add(-time.Minute)
+	suite.mockRepo.On("GetByHash", mock.AnythingOfType("string")).Return(&entity.APIToken{ID: 3, ExpiresAt: &past}, nil)
+
+	_, err := suite.apiTokenService.Authenticate("tdo_whatever")
+
+	assert.ErrorIs(suite.T(), err, auth.ErrInvalidToken)
+	suite.mockRepo.AssertNotCalled(suite.T(), "TouchLastUsed", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *APITokenServiceTestSuite) TestAuthenticate_Unknown() {
+	suite.mockRepo.On("GetByHash", mock.AnythingOfType("string")).Return((*entity.APIToken)(nil), gorm.ErrRecordNotFound)
+
+	_, err := suite.apiTokenService.Authenticate("tdo_unknown")
+
+	assert.ErrorIs(suite.T(), err, auth.ErrInvalidToken)
+}
+
+func TestAPITokenServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(APITokenServiceTestSuite))
+}
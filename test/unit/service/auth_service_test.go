@@ -0,0 +1,97 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/auth"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/service"
+	"github.com/vinibsi/todo-api/mocks"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+type AuthServiceTestSuite struct {
+	suite.Suite
+	mockRepo    *mocks.MockUserRepository
+	tokens      *auth.TokenManager
+	authService service.AuthService
+}
+
+func (suite *AuthServiceTestSuite) SetupTest() {
+	suite.mockRepo = new(mocks.MockUserRepository)
+	suite.tokens = auth.NewTokenManager("test-secret", time.Minute, time.Hour)
+	suite.authService = service.NewAuthService(suite.mockRepo, suite.tokens)
+}
+
+func (suite *AuthServiceTestSuite) TestRegister_Success() {
+	req := &dto.RegisterRequest{Name: "Ana", Email: " Ana@Example.com ", Password: "secret123"}
+
+	suite.mockRepo.On("GetByEmail", "ana@example.com").Return((*entity.User)(nil), gorm.ErrRecordNotFound)
+	suite.mockRepo.On("Create", mock.AnythingOfType("*entity.User")).Return(nil).Run(func(args mock.Arguments) {
+		user := args.Get(0).(*entity.User)
+		user.ID = 1
+		assert.NotEqual(suite.T(), "secret123", user.PasswordHash) // Senha nunca em texto puro
+		assert.NoError(suite.T(), bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte("secret123")))
+	})
+
+	result, err := suite.authService.Register(req)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint(1), result.ID)
+	assert.Equal(suite.T(), "ana@example.com", result.Email)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *AuthServiceTestSuite) TestRegister_EmailTaken() {
+	suite.mockRepo.On("GetByEmail", "ana@example.com").Return(&entity.User{ID: 1}, nil)
+
+	result, err := suite.authService.Register(&dto.RegisterRequest{Name: "Ana", Email: "ana@example.com", Password: "secret123"})
+
+	assert.Nil(suite.T(), result)
+	assert.ErrorIs(suite.T(), err, service.ErrEmailTaken)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin() {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("secret123"), bcrypt.MinCost)
+	user := &entity.User{ID: 7, Email: "ana@example.com", PasswordHash: string(hash)}
+	suite.mockRepo.On("GetByEmail", "ana@example.com").Return(user, nil)
+
+	tokens, err := suite.authService.Login(&dto.LoginRequest{Email: "ana@example.com", Password: "secret123"})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "Bearer", tokens.TokenType)
+
+	userID, err := suite.tokens.Parse(tokens.AccessToken, auth.AccessToken)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint(7), userID)
+
+	// Um token de acesso não pode ser usado como refresh e vice-versa
+	_, err = suite.tokens.Parse(tokens.AccessToken, auth.RefreshToken)
+	assert.ErrorIs(suite.T(), err, auth.ErrInvalidToken)
+
+	_, err = suite.authService.Login(&dto.LoginRequest{Email: "ana@example.com", Password: "wrong-password"})
+	assert.ErrorIs(suite.T(), err, service.ErrInvalidCredentials)
+}
+
+func (suite *AuthServiceTestSuite) TestRefresh() {
+	pair, err := suite.tokens.IssuePair(7)
+	suite.Require().NoError(err)
+	suite.mockRepo.On("GetByID", uint(7)).Return(&entity.User{ID: 7}, nil)
+
+	tokens, err := suite.authService.Refresh(&dto.RefreshRequest{RefreshToken: pair.RefreshToken})
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), tokens.AccessToken)
+
+	_, err = suite.authService.Refresh(&dto.RefreshRequest{RefreshToken: pair.AccessToken})
+	assert.ErrorIs(suite.T(), err, auth.ErrInvalidToken)
+}
+
+func TestAuthServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthServiceTestSuite))
+}
@@ -1,6 +1,7 @@
 package service_test
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -9,20 +10,28 @@ import (
 	"github.com/stretchr/testify/suite"
 	"github.com/vinibsi/todo-api/internal/dto"
 	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/event"
+	"github.com/vinibsi/todo-api/internal/repository"
 	"github.com/vinibsi/todo-api/internal/service"
 	"github.com/vinibsi/todo-api/mocks"
 	"gorm.io/gorm"
 )
 
+const userID uint = 42
+
 type TodoServiceTestSuite struct {
 	suite.Suite
-	mockRepo    *mocks.MockTodoRepository
-	todoService service.TodoService
+	mockRepo        *mocks.MockTodoRepository
+	mockProjectRepo *mocks.MockProjectRepository
+	mockTagRepo     *mocks.MockTagRepository
+	todoService     service.TodoService
 }
 
 func (suite *TodoServiceTestSuite) SetupTest() {
 	suite.mockRepo = new(mocks.MockTodoRepository)
-	suite.todoService = service.NewTodoService(suite.mockRepo)
+	suite.mockProjectRepo = new(mocks.MockProjectRepository)
+	suite.mockTagRepo = new(mocks.MockTagRepository)
+	suite.todoService = service.NewTodoService(suite.mockRepo, suite.mockProjectRepo, suite.mockTagRepo)
 }
 
 func (suite *TodoServiceTestSuite) TestCreate_Success() {
@@ -44,12 +53,13 @@ func (suite *TodoServiceTestSuite) TestCreate_Success() {
 
 	suite.mockRepo.On("Create", mock.AnythingOfType("*entity.Todo")).Return(nil).Run(func(args mock.Arguments) {
 		todo := args.Get(0).(*entity.Todo)
+		assert.Equal(suite.T(), userID, todo.UserID) // A tarefa pertence a quem a criou
 		todo.ID = 1
 		todo.CreatedAt = expectedTodo.CreatedAt
 		todo.UpdatedAt = expectedTodo.UpdatedAt
 	})
 
-	result, err := suite.todoService.Create(req)
+	result, err := suite.todoService.Create(userID, req)
 
 	assert.NoError(suite.T(), err)
 	assert.NotNil(suite.T(), result)
@@ -73,13 +83,76 @@ func (suite *TodoServiceTestSuite) TestCreate_DefaultPriority() {
 		assert.Equal(suite.T(), "medium", todo.Priority) // Verifica prioridade padrão
 	})
 
-	result, err := suite.todoService.Create(req)
+	result, err := suite.todoService.Create(userID, req)
 
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), "medium", result.Priority)
 	suite.mockRepo.AssertExpectations(suite.T())
 }
 
+func (suite *TodoServiceTestSuite) TestCreate_WithProject() {
+	projectID := uint(5)
+	suite.mockProjectRepo.On("GetByID", userID, projectID).Return(&entity.Project{ID: projectID, UserID: userID}, nil)
+	suite.mockRepo.On("Create", mock.AnythingOfType("*entity.Todo")).Return(nil)
+
+	result, err := suite.todoService.Create(userID, &dto.CreateTodoRequest{Title: "Test Todo", ProjectID: &projectID})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &projectID, result.ProjectID)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *TodoServiceTestSuite) TestCreate_InvalidProject() {
+	missing, archived := uint(5), uint(6)
+	suite.mockProjectRepo.On("GetByID", userID, missing).Return((*entity.Project)(nil), gorm.ErrRecordNotFound)
+	suite.mockProjectRepo.On("GetByID", userID, archived).Return(&entity.Project{ID: archived, Archived: true}, nil)
+
+	_, err := suite.todoService.Create(userID, &dto.CreateTodoRequest{Title: "Test Todo", ProjectID: &missing})
+	assert.ErrorIs(suite.T(), err, service.ErrProjectNotFound)
+
+	_, err = suite.todoService.Create(userID, &dto.CreateTodoRequest{Title: "Test Todo", ProjectID: &archived})
+	assert.ErrorIs(suite.T(), err, service.ErrProjectArchived)
+
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *TodoServiceTestSuite) TestCreate_NormalizesTags() {
+	tags := []entity.Tag{{ID: 1, UserID: userID, Name: "home office"}, {ID: 2, UserID: userID, Name: "urgent"}}
+	suite.mockTagRepo.On("FindOrCreate", userID, []string{"urgent", "home office"}).Return(tags, nil)
+	suite.mockRepo.On("Create", mock.MatchedBy(func(todo *entity.Todo) bool {
+		return len(todo.Tags) == 2
+	})).Return(nil)
+
+	result, err := suite.todoService.Create(userID, &dto.CreateTodoRequest{
+		Title: "Test Todo",
+		Tags:  []string{" Urgent", "home   Office", "URGENT"},
+	})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"home office", "urgent"}, result.Tags)
+	suite.mockTagRepo.AssertExpectations(suite.T())
+}
+
+func (suite *TodoServiceTestSuite) TestCreate_BlankTag() {
+	_, err := suite.todoService.Create(userID, &dto.CreateTodoRequest{Title: "Test Todo", Tags: []string{"   "}})
+
+	assert.ErrorIs(suite.T(), err, service.ErrInvalidTag)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *TodoServiceTestSuite) TestUpdate_MoveToInbox() {
+	projectID := uint(5)
+	inbox := uint(0)
+	suite.mockRepo.On("GetByID", userID, uint(1)).Return(&entity.Todo{ID: 1, UserID: userID, ProjectID: &projectID}, nil)
+	suite.mockRepo.On("Update", mock.AnythingOfType("*entity.Todo")).Return(nil)
+
+	result, err := suite.todoService.Update(userID, 1, &dto.UpdateTodoRequest{ProjectID: &inbox}, nil)
+
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), result.ProjectID)
+	suite.mockProjectRepo.AssertNotCalled(suite.T(), "GetByID", mock.Anything, mock.Anything)
+}
+
 func (suite *TodoServiceTestSuite) TestGetByID_Success() {
 	todo := &entity.Todo{
 		ID:          1,
@@ -89,9 +162,9 @@ func (suite *TodoServiceTestSuite) TestGetByID_Success() {
 		Completed:   false,
 	}
 
-	suite.mockRepo.On("GetByID", uint(1)).Return(todo, nil)
+	suite.mockRepo.On("GetByID", userID, uint(1)).Return(todo, nil)
 
-	result, err := suite.todoService.GetByID(1)
+	result, err := suite.todoService.GetByID(userID, 1)
 
 	assert.NoError(suite.T(), err)
 	assert.NotNil(suite.T(), result)
@@ -101,9 +174,9 @@ func (suite *TodoServiceTestSuite) TestGetByID_Success() {
 }
 
 func (suite *TodoServiceTestSuite) TestGetByID_NotFound() {
-	suite.mockRepo.On("GetByID", uint(999)).Return((*entity.Todo)(nil), gorm.ErrRecordNotFound)
+	suite.mockRepo.On("GetByID", userID, uint(999)).Return((*entity.Todo)(nil), gorm.ErrRecordNotFound)
 
-	result, err := suite.todoService.GetByID(999)
+	result, err := suite.todoService.GetByID(userID, 999)
 
 	assert.Error(suite.T(), err)
 	assert.Nil(suite.T(), result)
@@ -117,20 +190,110 @@ func (suite *TodoServiceTestSuite) TestGetAll_Success() {
 		{ID: 2, Title: "Todo 2", Priority: "medium"},
 	}
 
-	suite.mockRepo.On("GetAll", 10, 0).Return(todos, int64(2), nil)
+	suite.mockRepo.On("GetAll", repository.TodoQuery{
+		UserID: userID,
+		Sort:   []repository.SortField{{Field: "created_at", Desc: true}},
+		Limit:  10,
+		Offset: 0,
+	}).Return(todos, int64(2), nil)
 
-	result, err := suite.todoService.GetAll(1, 10)
+	result, err := suite.todoService.GetAll(userID, &dto.TodoListQuery{Page: 1, Size: 10})
 
 	assert.NoError(suite.T(), err)
 	assert.NotNil(suite.T(), result)
 	assert.Len(suite.T(), result.Data, 2)
-	assert.Equal(suite.T(), int64(2), result.Total)
+	assert.Equal(suite.T(), int64(2), *result.Total)
 	assert.Equal(suite.T(), 1, result.Page)
 	assert.Equal(suite.T(), 10, result.PageSize)
 	assert.Equal(suite.T(), 1, result.TotalPages)
 	suite.mockRepo.AssertExpectations(suite.T())
 }
 
+func (suite *TodoServiceTestSuite) TestGetAll_WithFilters() {
+	completed := false
+	dueBefore := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	expectedQuery := repository.TodoQuery{
+		UserID:    userID,
+		Completed: &completed,
+		Priority:  "high",
+		DueBefore: &dueBefore,
+		Overdue:   true,
+		Sort:      []repository.SortField{{Field: "priority", Desc: true}, {Field: "title"}},
+		Limit:     5,
+		Offset:    5,
+	}
+	suite.mockRepo.On("GetAll", expectedQuery).Return([]entity.Todo{}, int64(7), nil)
+
+	result, err := suite.todoService.GetAll(userID, &dto.TodoListQuery{
+		Page:      2,
+		Size:      5,
+		Completed: &completed,
+		Priority:  "high",
+		DueBefore: &dueBefore,
+		Overdue:   true,
+		Sort:      "-priority,title",
+	})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(7), *result.Total)
+	assert.Equal(suite.T(), 2, result.TotalPages)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *TodoServiceTestSuite) TestGetAll_InvalidSort() {
+	result, err := suite.todoService.GetAll(userID, &dto.TodoListQuery{Sort: "title;DROP TABLE todos"})
+
+	assert.Nil(suite.T(), result)
+	assert.ErrorIs(suite.T(), err, service.ErrInvalidQuery)
+	assert.Contains(suite.T(), err.Error(), "allowed fields")
+	suite.mockRepo.AssertNotCalled(suite.T(), "GetAll", mock.Anything)
+}
+
+func (suite *TodoServiceTestSuite) TestGetAll_Cursor() {
+	todos := []entity.Todo{
+		{ID: 3, Title: "Todo 3", CreatedAt: time.Now()},
+		{ID: 2, Title: "Todo 2", CreatedAt: time.Now()},
+		{ID: 1, Title: "Todo 1", CreatedAt: time.Now()},
+	}
+
+	suite.mockRepo.On("GetAll", mock.MatchedBy(func(query repository.TodoQuery) bool {
+		return query.Limit == 3 && query.SkipCount && query.After == nil
+	})).Return(todos, int64(0), nil)
+
+	result, err := suite.todoService.GetAll(userID, &dto.TodoListQuery{Size: 2, Pagination: "cursor", SkipTotal: true})
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), result.Data, 2)
+	assert.True(suite.T(), result.HasMore)
+	assert.NotEmpty(suite.T(), result.NextCursor)
+	assert.Nil(suite.T(), result.Total)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *TodoServiceTestSuite) TestGetAll_InvalidCursor() {
+	result, err := suite.todoService.GetAll(userID, &dto.TodoListQuery{Cursor: "garbage"})
+
+	assert.Nil(suite.T(), result)
+	assert.ErrorIs(suite.T(), err, service.ErrInvalidQuery)
+}
+
+func (suite *TodoServiceTestSuite) TestGetAll_Search() {
+	results := []repository.TodoSearchResult{
+		{Todo: entity.Todo{ID: 1, Title: "Buy milk"}, Rank: 0.8, Snippet: "Buy <mark>milk</mark>"},
+	}
+
+	suite.mockRepo.On("Search", "milk", mock.AnythingOfType("repository.TodoQuery")).Return(results, int64(1), nil)
+
+	result, err := suite.todoService.GetAll(userID, &dto.TodoListQuery{Page: 1, Size: 10, Q: "milk"})
+
+	assert.NoError(suite.T(), err)
+	suite.Require().Len(result.Data, 1)
+	assert.Equal(suite.T(), "Buy <mark>milk</mark>", result.Data[0].Search.Snippet)
+	assert.Equal(suite.T(), int64(1), *result.Total)
+	suite.mockRepo.AssertNotCalled(suite.T(), "GetAll", mock.Anything)
+}
+
 func (suite *TodoServiceTestSuite) TestUpdate_Success() {
 	existingTodo := &entity.Todo{
 		ID:          1,
@@ -147,7 +310,7 @@ func (suite *TodoServiceTestSuite) TestUpdate_Success() {
 		Completed: &completed,
 	}
 
-	suite.mockRepo.On("GetByID", uint(1)).Return(existingTodo, nil)
+	suite.mockRepo.On("GetByID", userID, uint(1)).Return(existingTodo, nil)
 	suite.mockRepo.On("Update", mock.AnythingOfType("*entity.Todo")).Return(nil).Run(func(args mock.Arguments) {
 		todo := args.Get(0).(*entity.Todo)
 		assert.Equal(suite.T(), "Updated Title", todo.Title)
@@ -155,20 +318,92 @@ func (suite *TodoServiceTestSuite) TestUpdate_Success() {
 		assert.Equal(suite.T(), "Original Description", todo.Description) // Não deve mudar
 	})
 
-	result, err := suite.todoService.Update(1, req)
+	result, err := suite.todoService.Update(userID, 1, req, nil)
 
 	assert.NoError(suite.T(), err)
 	assert.NotNil(suite.T(), result)
 	suite.mockRepo.AssertExpectations(suite.T())
 }
 
+func (suite *TodoServiceTestSuite) TestUpdate_IfMatchMismatch() {
+	title := "New"
+	expected := 2
+	suite.mockRepo.On("GetByID", userID, uint(1)).Return(&entity.Todo{ID: 1, Title: "Old", Version: 3}, nil)
+
+	_, err := suite.todoService.Update(userID, 1, &dto.UpdateTodoRequest{Title: &title}, &expected)
+
+	assert.ErrorIs(suite.T(), err, service.ErrVersionMismatch)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+
+func (suite *TodoServiceTestSuite) TestUpdate_StaleWrite() {
+	title := "New"
+	expected := 3
+	suite.mockRepo.On("GetByID", userID, uint(1)).Return(&entity.Todo{ID: 1, Title: "Old", Version: 3}, nil)
+	suite.mockRepo.On("Update", mock.AnythingOfType("*entity.Todo")).Return(repository.ErrStaleVersion)
+
+	// Outra requisição gravou entre a leitura e a gravação
+	_, err := suite.todoService.Update(userID, 1, &dto.UpdateTodoRequest{Title: &title}, &expected)
+	assert.ErrorIs(suite.T(), err, service.ErrVersionMismatch)
+
+	_, err = suite.todoService.Update(userID, 1, &dto.UpdateTodoRequest{Title: &title}, nil)
+	assert.ErrorIs(suite.T(), err, service.ErrConcurrentUpdate)
+}
+
+func (suite *TodoServiceTestSuite) TestPatch_ClearsDueDateAndKeepsUnchangedFields() {
+	due := time.Date(2030, time.May, 20, 0, 0, 0, 0, time.UTC)
+	start := due.Add(-7 * 24 * time.Hour)
+	existing := &entity.Todo{ID: 1, Title: "Weekly report", Priority: "medium", DueDate: &due, Version: 2,
+		Recurrence: entity.Recurrence{Rule: "FREQ=WEEKLY", Timezone: "UTC", Start: &start}}
+	suite.mockRepo.On("GetByID", userID, uint(1)).Return(existing, nil)
+	suite.mockRepo.On("Update", mock.AnythingOfType("*entity.Todo")).Return(nil).Run(func(args mock.Arguments) {
+		todo := args.Get(0).(*entity.Todo)
+		assert.Nil(suite.T(), todo.DueDate)
+		assert.Equal(suite.T(), "high", todo.Priority)
+		assert.Equal(suite.T(), "Weekly report", todo.Title)
+		// A repetição não mudou e a série não é reiniciada
+		assert.Equal(suite.T(), &start, todo.Recurrence.Start)
+	})
+
+	result, err := suite.todoService.Patch(userID, 1, func(current *dto.TodoPatchDocument) (*dto.TodoPatchDocument, error) {
+		assert.Equal(suite.T(), "FREQ=WEEKLY", current.Recurrence.Rule)
+		current.DueDate = nil
+		current.Priority = "high"
+		return current, nil
+	}, nil)
+
+	suite.Require().NoError(err)
+	assert.Nil(suite.T(), result.DueDate)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *TodoServiceTestSuite) TestPatch_ApplyError() {
+	expected := 2
+	suite.mockRepo.On("GetByID", userID, uint(1)).Return(&entity.Todo{ID: 1, Title: "Old", Version: 2}, nil)
+	patchErr := errors.New("test failed")
+
+	_, err := suite.todoService.Patch(userID, 1, func(*dto.TodoPatchDocument) (*dto.TodoPatchDocument, error) {
+		return nil, patchErr
+	}, &expected)
+	assert.ErrorIs(suite.T(), err, patchErr)
+
+	// A pré-condição é verificada antes de aplicar o patch
+	expected = 1
+	_, err = suite.todoService.Patch(userID, 1, func(*dto.TodoPatchDocument) (*dto.TodoPatchDocument, error) {
+		suite.Fail("patch applied to a stale version")
+		return nil, nil
+	}, &expected)
+	assert.ErrorIs(suite.T(), err, service.ErrVersionMismatch)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+
 func (suite *TodoServiceTestSuite) TestDelete_Success() {
-	todo := &entity.Todo{ID: 1, Title: "To be deleted"}
+	todo := &entity.Todo{ID: 1, Title: "To be deleted", Version: 3}
 
-	suite.mockRepo.On("GetByID", uint(1)).Return(todo, nil)
-	suite.mockRepo.On("Delete", uint(1)).Return(nil)
+	suite.mockRepo.On("GetByID", userID, uint(1)).Return(todo, nil)
+	suite.mockRepo.On("Delete", userID, uint(1), 3).Return(nil)
 
-	err := suite.todoService.Delete(1)
+	err := suite.todoService.Delete(userID, 1, nil)
 
 	assert.NoError(suite.T(), err)
 	suite.mockRepo.AssertExpectations(suite.T())
@@ -181,13 +416,13 @@ func (suite *TodoServiceTestSuite) TestComplete_Success() {
 		Completed: false,
 	}
 
-	suite.mockRepo.On("GetByID", uint(1)).Return(todo, nil)
+	suite.mockRepo.On("GetByID", userID, uint(1)).Return(todo, nil)
 	suite.mockRepo.On("Update", mock.AnythingOfType("*entity.Todo")).Return(nil).Run(func(args mock.Arguments) {
 		updatedTodo := args.Get(0).(*entity.Todo)
 		assert.True(suite.T(), updatedTodo.Completed)
 	})
 
-	result, err := suite.todoService.Complete(1)
+	result, err := suite.todoService.Complete(userID, 1, nil)
 
 	assert.NoError(suite.T(), err)
 	assert.NotNil(suite.T(), result)
@@ -195,6 +430,395 @@ func (suite *TodoServiceTestSuite) TestComplete_Success() {
 	suite.mockRepo.AssertExpectations(suite.T())
 }
 
+func (suite *TodoServiceTestSuite) TestComplete_OpenSubtasks() {
+	todo := &entity.Todo{ID: 1, UserID: userID, SubtasksTotal: 3, SubtasksDone: 2}
+	suite.mockRepo.On("GetByID", userID, uint(1)).Return(todo, nil)
+
+	_, err := suite.todoService.Complete(userID, 1, nil)
+
+	assert.ErrorIs(suite.T(), err, service.ErrOpenSubtasks)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+
+func (suite *TodoServiceTestSuite) TestComplete_AutoCompletesParent() {
+	todoService := service.NewTodoService(suite.mockRepo, suite.mockProjectRepo, suite.mockTagRepo,
+		service.WithAutoCompleteParent(true))
+
+	parentID := uint(1)
+	child := &entity.Todo{ID: 2, UserID: userID, ParentID: &parentID}
+	// Depois da conclusão da filha o repositório já conta todas como concluídas
+	parent := &entity.Todo{ID: 1, UserID: userID, SubtasksTotal: 2, SubtasksDone: 2}
+
+	suite.mockRepo.On("GetByID", userID, uint(2)).Return(child, nil)
+	suite.mockRepo.On("GetByID", userID, uint(1)).Return(parent, nil)
+	suite.mockRepo.On("Update", child).Return(nil).Once()
+	suite.mockRepo.On("Update", parent).Return(nil).Once()
+
+	result, err := todoService.Complete(userID, 2, nil)
+
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.Completed)
+	assert.True(suite.T(), parent.Completed)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *TodoServiceTestSuite) TestCreate_MaxDepthExceeded() {
+	rootID, middleID := uint(1), uint(2)
+	suite.mockRepo.On("GetByID", userID, uint(3)).Return(&entity.Todo{ID: 3, ParentID: &middleID}, nil)
+	suite.mockRepo.On("GetByID", userID, middleID).Return(&entity.Todo{ID: middleID, ParentID: &rootID}, nil)
+	suite.mockRepo.On("GetByID", userID, rootID).Return(&entity.Todo{ID: rootID}, nil)
+
+	parentID := uint(3)
+	_, err := suite.todoService.Create(userID, &dto.CreateTodoRequest{Title: "Too deep", ParentID: &parentID})
+
+	assert.ErrorIs(suite.T(), err, service.ErrMaxDepthExceeded)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *TodoServiceTestSuite) TestUpdate_ParentCycle() {
+	todoID, childID := uint(1), uint(2)
+	suite.mockRepo.On("GetByID", userID, todoID).Return(&entity.Todo{ID: todoID, UserID: userID}, nil)
+	suite.mockRepo.On("GetByID", userID, uint(3)).Return(&entity.Todo{ID: 3, ParentID: &childID}, nil)
+	suite.mockRepo.On("Descendants", userID, todoID).Return([]entity.Todo{
+		{ID: childID, ParentID: &todoID},
+		{ID: 3, ParentID: &childID},
+	}, nil)
+
+	parentID := uint(3)
+	_, err := suite.todoService.Update(userID, todoID, &dto.UpdateTodoRequest{ParentID: &parentID}, nil)
+	assert.ErrorIs(suite.T(), err, service.ErrInvalidParent)
+
+	_, err = suite.todoService.Update(userID, todoID, &dto.UpdateTodoRequest{ParentID: &todoID}, nil)
+	assert.ErrorIs(suite.T(), err, service.ErrInvalidParent)
+
+	suite.mockRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+
+func (suite *TodoServiceTestSuite) TestComplete_RecurringRollsForward() {
+	now := time.Date(2025, time.March, 5, 12, 0, 0, 0, time.UTC)
+	publisher := &recordingPublisher{}
+	todoService := service.NewTodoService(suite.mockRepo, suite.mockProjectRepo, suite.mockTagRepo,
+		service.WithClock(func() time.Time { return now }), service.WithPublisher(publisher))
+
+	start := time.Date(2025, time.March, 3, 9, 0, 0, 0, time.UTC) // segunda-feira
+	todo := &entity.Todo{
+		ID:         1,
+		UserID:     userID,
+		DueDate:    &start,
+		Recurrence: entity.Recurrence{Rule: "FREQ=WEEKLY;BYDAY=MO,FR", Timezone: "UTC", Start: &start},
+	}
+	suite.mockRepo.On("GetByID", userID, uint(1)).Return(todo, nil)
+	suite.mockRepo.On("Update", todo).Return(nil)
+
+	result, err := todoService.Complete(userID, 1, nil)
+
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), result.Completed)
+	assert.Equal(suite.T(), time.Date(2025, time.March, 7, 9, 0, 0, 0, time.UTC), *result.DueDate)
+	assert.Equal(suite.T(), 1, result.Recurrence.CompletedOccurrences)
+	// A tarefa reaberta para a próxima ocorrência não é anunciada como concluída
+	assert.Equal(suite.T(), []string{event.TodoUpdated}, publisher.types())
+}
+
+func (suite *TodoServiceTestSuite) TestComplete_RecurringSeriesEnds() {
+	due := time.Date(2025, time.March, 3, 9, 0, 0, 0, time.UTC)
+	todo := &entity.Todo{
+		ID:         1,
+		UserID:     userID,
+		DueDate:    &due,
+		Recurrence: entity.Recurrence{Rule: "FREQ=DAILY;COUNT=3", Timezone: "UTC", Start: &due, Completed: 2},
+	}
+	suite.mockRepo.On("GetByID", userID, uint(1)).Return(todo, nil)
+	suite.mockRepo.On("Update", todo).Return(nil)
+
+	result, err := suite.todoService.Complete(userID, 1, nil)
+
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.Completed)
+	assert.Equal(suite.T(), due, *result.DueDate)
+}
+
+func (suite *TodoServiceTestSuite) TestCreate_InvalidRecurrence() {
+	_, err := suite.todoService.Create(userID, &dto.CreateTodoRequest{
+		Title:      "Test Todo",
+		Recurrence: &dto.RecurrenceRequest{Rule: "FREQ=HOURLY"},
+	})
+
+	assert.ErrorIs(suite.T(), err, service.ErrInvalidRecurrence)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// recordingPublisher guarda os eventos publicados pelo serviço
+type recordingPublisher struct {
+	events []event.Event
+}
+
+func (p *recordingPublisher) Publish(e event.Event) {
+	p.events = append(p.events, e)
+}
+
+func (p *recordingPublisher) types() []string {
+	types := make([]string, len(p.events))
+	for i, e := range p.events {
+		types[i] = e.Type
+	}
+	return types
+}
+
+func (suite *TodoServiceTestSuite) TestCreate_PublishesEvent() {
+	publisher := &recordingPublisher{}
+	todoService := service.NewTodoService(suite.mockRepo, suite.mockProjectRepo, suite.mockTagRepo, service.WithPublisher(publisher))
+	suite.mockRepo.On("Create", mock.AnythingOfType("*entity.Todo")).Run(func(args mock.Arguments) {
+		args.Get(0).(*entity.Todo).ID = 9
+	}).Return(nil)
+
+	_, err := todoService.Create(userID, &dto.CreateTodoRequest{Title: "Publish me"})
+
+	suite.Require().NoError(err)
+	suite.Require().Len(publisher.events, 1)
+	assert.Equal(suite.T(), event.TodoCreated, publisher.events[0].Type)
+	assert.Equal(suite.T(), userID, publisher.events[0].UserID)
+	assert.Equal(suite.T(), uint(9), publisher.events[0].TodoID)
+	assert.Equal(suite.T(), "Publish me", publisher.events[0].Todo.Title)
+}
+
+func (suite *TodoServiceTestSuite) TestUpdate_CompletingPublishesBothEvents() {
+	publisher := &recordingPublisher{}
+	todoService := service.NewTodoService(suite.mockRepo, suite.mockProjectRepo, suite.mockTagRepo, service.WithPublisher(publisher))
+	suite.mockRepo.On("GetByID", userID, uint(1)).Return(&entity.Todo{ID: 1, Title: "Open"}, nil)
+	suite.mockRepo.On("Update", mock.AnythingOfType("*entity.Todo")).Return(nil)
+
+	completed := true
+	_, err := todoService.Update(userID, 1, &dto.UpdateTodoRequest{Completed: &completed}, nil)
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), []string{event.TodoUpdated, event.TodoCompleted}, publisher.types())
+}
+
+func (suite *TodoServiceTestSuite) TestDelete_PublishesForSubtasks() {
+	publisher := &recordingPublisher{}
+	todoService := service.NewTodoService(suite.mockRepo, suite.mockProjectRepo, suite.mockTagRepo, service.WithPublisher(publisher))
+	parentID := uint(1)
+	suite.mockRepo.On("GetByID", userID, uint(1)).Return(&entity.Todo{ID: 1, Title: "Parent", SubtasksTotal: 1}, nil)
+	suite.mockRepo.On("Descendants", userID, uint(1)).Return([]entity.Todo{{ID: 2, ParentID: &parentID, Title: "Child"}}, nil)
+	suite.mockRepo.On("Delete", userID, uint(1), 0).Return(nil)
+
+	suite.Require().NoError(todoService.Delete(userID, 1, nil))
+
+	suite.Require().Len(publisher.events, 2)
+	assert.Equal(suite.T(), []string{event.TodoDeleted, event.TodoDeleted}, publisher.types())
+	assert.Equal(suite.T(), uint(2), publisher.events[1].TodoID)
+}
+
+func (suite *TodoServiceTestSuite) TestUpdate_RecordsFieldDiff() {
+	history := new(mocks.MockTodoHistoryRepository)
+	todoService := service.NewTodoService(suite.mockRepo, suite.mockProjectRepo, suite.mockTagRepo, service.WithHistory(history))
+	suite.mockRepo.On("GetByID", userID, uint(1)).Return(&entity.Todo{ID: 1, Title: "Old", Priority: "low"}, nil)
+	suite.mockRepo.On("Update", mock.AnythingOfType("*entity.Todo")).Return(nil)
+	history.On("Record", mock.MatchedBy(func(entry *entity.TodoHistory) bool {
+		return entry.TodoID == 1 && entry.UserID == userID && entry.ActorID == userID &&
+			entry.Operation == entity.HistoryUpdate &&
+			entry.Changes == `{"title":{"from":"Old","to":"New"}}`
+	})).Return(nil).Once()
+
+	title, priority := "New", "low"
+	_, err := todoService.Update(userID, 1, &dto.UpdateTodoRequest{Title: &title, Priority: &priority}, nil)
+
+	suite.Require().NoError(err)
+	history.AssertExpectations(suite.T())
+}
+
+func (suite *TodoServiceTestSuite) TestUpdate_NoChangeSkipsHistory() {
+	history := new(mocks.MockTodoHistoryRepository)
+	todoService := service.NewTodoService(suite.mockRepo, suite.mockProjectRepo, suite.mockTagRepo, service.WithHistory(history))
+	suite.mockRepo.On("GetByID", userID, uint(1)).Return(&entity.Todo{ID: 1, Title: "Same"}, nil)
+	suite.mockRepo.On("Update", mock.AnythingOfType("*entity.Todo")).Return(nil)
+
+	title := "Same"
+	_, err := todoService.Update(userID, 1, &dto.UpdateTodoRequest{Title: &title}, nil)
+
+	suite.Require().NoError(err)
+	history.AssertNotCalled(suite.T(), "Record", mock.Anything)
+}
+
+func (suite *TodoServiceTestSuite) TestRevert_RevisionNotFound() {
+	history := new(mocks.MockTodoHistoryRepository)
+	todoService := service.NewTodoService(suite.mockRepo, suite.mockProjectRepo, suite.mockTagRepo, service.WithHistory(history))
+	suite.mockRepo.On("GetByID", userID, uint(1)).Return(&entity.Todo{ID: 1, Title: "Todo"}, nil)
+	history.On("GetRevision", userID, uint(1), 7).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := todoService.Revert(userID, 1, 7)
+
+	assert.ErrorIs(suite.T(), err, service.ErrRevisionNotFound)
+}
+
+func (suite *TodoServiceTestSuite) TestRestore_ParentInTrash() {
+	suite.mockRepo.On("Restore", userID, uint(2)).Return(repository.ErrParentDeleted)
+
+	_, err := suite.todoService.Restore(userID, 2)
+
+	assert.ErrorIs(suite.T(), err, service.ErrParentInTrash)
+	suite.mockRepo.AssertNotCalled(suite.T(), "GetByID", mock.Anything, mock.Anything)
+}
+
+func (suite *TodoServiceTestSuite) TestRestore_NotInTrash() {
+	suite.mockRepo.On("Restore", userID, uint(5)).Return(gorm.ErrRecordNotFound)
+
+	_, err := suite.todoService.Restore(userID, 5)
+
+	assert.ErrorIs(suite.T(), err, service.ErrNotInTrash)
+}
+
+// fakeTransactor repassa os mocks da suíte como repositórios da transação
+type fakeTransactor struct {
+	suite   *TodoServiceTestSuite
+	history repository.TodoHistoryRepository
+}
+
+func (t fakeTransactor) Transaction(fn func(repository.Repositories) error) error {
+	return fn(repository.Repositories{
+		Transactor: t,
+		Todos:      t.suite.mockRepo,
+		Projects:   t.suite.mockProjectRepo,
+		Tags:       t.suite.mockTagRepo,
+		History:    t.history,
+	})
+}
+
+func (suite *TodoServiceTestSuite) TestUpdate_HistoryFailureAbortsTransaction() {
+	history := new(mocks.MockTodoHistoryRepository)
+	publisher := &recordingPublisher{}
+	todoService := service.NewTodoService(suite.mockRepo, suite.mockProjectRepo, suite.mockTagRepo,
+		service.WithHistory(history), service.WithPublisher(publisher),
+		service.WithTransactor(fakeTransactor{suite: suite, history: history}))
+	suite.mockRepo.On("GetByID", userID, uint(1)).Return(&entity.Todo{ID: 1, Title: "Old"}, nil)
+	suite.mockRepo.On("Update", mock.AnythingOfType("*entity.Todo")).Return(nil)
+	history.On("Record", mock.AnythingOfType("*entity.TodoHistory")).Return(errors.New("history unavailable"))
+
+	title := "New"
+	_, err := todoService.Update(userID, 1, &dto.UpdateTodoRequest{Title: &title}, nil)
+
+	// O erro desfaz a transação da alteração e nenhum evento é publicado
+	assert.EqualError(suite.T(), err, "history unavailable")
+	assert.Empty(suite.T(), publisher.events)
+}
+
+func (suite *TodoServiceTestSuite) TestDeleteProject_MovesTodosToInbox() {
+	publisher := &recordingPublisher{}
+	history := new(mocks.MockTodoHistoryRepository)
+	todoService := service.NewTodoService(suite.mockRepo, suite.mockProjectRepo, suite.mockTagRepo,
+		service.WithHistory(history), service.WithPublisher(publisher),
+		service.WithTransactor(fakeTransactor{suite: suite, history: history}))
+	projectID := uint(5)
+	suite.mockRepo.On("Each", mock.AnythingOfType("repository.TodoQuery"), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		query := args.Get(0).(repository.TodoQuery)
+		suite.Require().Equal(projectID, *query.ProjectID)
+		fn := args.Get(2).(func([]entity.Todo) error)
+		suite.Require().NoError(fn([]entity.Todo{{ID: 1, Title: "Moved", ProjectID: &projectID, Version: 1}}))
+	}).Return(nil)
+	suite.mockProjectRepo.On("Delete", userID, projectID, false).Return(nil)
+	suite.mockRepo.On("GetByID", userID, uint(1)).Return(&entity.Todo{ID: 1, Title: "Moved", Version: 2}, nil)
+	history.On("Record", mock.MatchedBy(func(entry *entity.TodoHistory) bool {
+		return entry.TodoID == 1 && entry.Operation == entity.HistoryUpdate
+	})).Return(nil).Once()
+
+	err := todoService.DeleteProject(userID, projectID, false)
+
+	suite.Require().NoError(err)
+	suite.Require().Equal([]string{event.TodoUpdated}, publisher.types())
+	assert.Nil(suite.T(), publisher.events[0].Todo.ProjectID)
+	assert.Equal(suite.T(), 2, publisher.events[0].Todo.Version)
+	history.AssertExpectations(suite.T())
+}
+
+func (suite *TodoServiceTestSuite) TestDeleteProject_CascadePublishesDeleted() {
+	publisher := &recordingPublisher{}
+	todoService := service.NewTodoService(suite.mockRepo, suite.mockProjectRepo, suite.mockTagRepo,
+		service.WithPublisher(publisher), service.WithTransactor(fakeTransactor{suite: suite}))
+	projectID := uint(5)
+	suite.mockRepo.On("Each", mock.AnythingOfType("repository.TodoQuery"), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		fn := args.Get(2).(func([]entity.Todo) error)
+		suite.Require().NoError(fn([]entity.Todo{{ID: 1, ProjectID: &projectID}, {ID: 2, ProjectID: &projectID}}))
+	}).Return(nil)
+	suite.mockProjectRepo.On("Delete", userID, projectID, true).Return(nil)
+
+	err := todoService.DeleteProject(userID, projectID, true)
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), []string{event.TodoDeleted, event.TodoDeleted}, publisher.types())
+}
+
+func (suite *TodoServiceTestSuite) TestDeleteProject_NotFound() {
+	publisher := &recordingPublisher{}
+	todoService := service.NewTodoService(suite.mockRepo, suite.mockProjectRepo, suite.mockTagRepo,
+		service.WithPublisher(publisher), service.WithTransactor(fakeTransactor{suite: suite}))
+	suite.mockRepo.On("Each", mock.AnythingOfType("repository.TodoQuery"), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		fn := args.Get(2).(func([]entity.Todo) error)
+		suite.Require().NoError(fn([]entity.Todo{{ID: 1}}))
+	}).Return(nil)
+	suite.mockProjectRepo.On("Delete", userID, uint(9), true).Return(gorm.ErrRecordNotFound)
+
+	err := todoService.DeleteProject(userID, 9, true)
+
+	assert.ErrorIs(suite.T(), err, service.ErrProjectNotFound)
+	assert.Empty(suite.T(), publisher.events)
+}
+
+func (suite *TodoServiceTestSuite) TestComplete_ParentFailureAbortsTransaction() {
+	publisher := &recordingPublisher{}
+	todoService := service.NewTodoService(suite.mockRepo, suite.mockProjectRepo, suite.mockTagRepo,
+		service.WithAutoCompleteParent(true), service.WithPublisher(publisher),
+		service.WithTransactor(fakeTransactor{suite: suite}))
+
+	parentID := uint(1)
+	child := &entity.Todo{ID: 2, UserID: userID, ParentID: &parentID}
+	parent := &entity.Todo{ID: 1, UserID: userID, SubtasksTotal: 1, SubtasksDone: 1}
+	suite.mockRepo.On("GetByID", userID, uint(2)).Return(child, nil)
+	suite.mockRepo.On("GetByID", userID, uint(1)).Return(parent, nil)
+	suite.mockRepo.On("Update", child).Return(nil).Once()
+	suite.mockRepo.On("Update", parent).Return(errors.New("parent unavailable")).Once()
+
+	_, err := todoService.Complete(userID, 2, nil)
+
+	// A falha ao concluir a tarefa pai desfaz também a conclusão da filha
+	assert.EqualError(suite.T(), err, "parent unavailable")
+	assert.Empty(suite.T(), publisher.events)
+}
+
+func (suite *TodoServiceTestSuite) TestBulk_PublishesOnlyCommittedOperations() {
+	publisher := &recordingPublisher{}
+	todoService := service.NewTodoService(suite.mockRepo, suite.mockProjectRepo, suite.mockTagRepo,
+		service.WithPublisher(publisher), service.WithTransactor(fakeTransactor{suite: suite}))
+	suite.mockRepo.On("Create", mock.AnythingOfType("*entity.Todo")).Return(nil)
+	suite.mockRepo.On("GetByID", userID, uint(404)).Return((*entity.Todo)(nil), gorm.ErrRecordNotFound)
+	operations := []service.BulkOperation{
+		{Op: service.BulkCreate, Create: &dto.CreateTodoRequest{Title: "First"}},
+		{Op: service.BulkComplete, ID: 404},
+	}
+
+	result, err := todoService.Bulk(userID, service.BulkAtomic, operations)
+
+	suite.Require().NoError(err)
+	assert.False(suite.T(), result.Committed)
+	assert.ErrorIs(suite.T(), result.Items[0].Err, service.ErrBulkRolledBack)
+	assert.ErrorIs(suite.T(), result.Items[1].Err, service.ErrTodoNotFound)
+	assert.Empty(suite.T(), publisher.events)
+
+	result, err = todoService.Bulk(userID, service.BulkBestEffort, operations)
+
+	suite.Require().NoError(err)
+	assert.True(suite.T(), result.Committed)
+	assert.NoError(suite.T(), result.Items[0].Err)
+	assert.ErrorIs(suite.T(), result.Items[1].Err, service.ErrTodoNotFound)
+	assert.Equal(suite.T(), []string{event.TodoCreated}, publisher.types())
+}
+
+func (suite *TodoServiceTestSuite) TestBulk_RequiresTransactor() {
+	_, err := suite.todoService.Bulk(userID, service.BulkAtomic, []service.BulkOperation{{Op: service.BulkComplete, ID: 1}})
+
+	assert.ErrorIs(suite.T(), err, service.ErrBulkUnavailable)
+}
+
 func TestTodoServiceTestSuite(t *testing.T) {
 	suite.Run(t, new(TodoServiceTestSuite))
 }
@@ -0,0 +1,163 @@
+package transfer_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/transfer"
+)
+
+type TransferTestSuite struct {
+	suite.Suite
+}
+
+func (suite *TransferTestSuite) todos() []dto.TodoResponse {
+	due := time.Date(2025, time.March, 3, 9, 0, 0, 0, time.UTC)
+	created := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	project := uint(3)
+	return []dto.TodoResponse{
+		{ID: 1, Title: "Report, monthly", Priority: "high", DueDate: &due, ProjectID: &project, Tags: []string{"work", "finance"},
+			Recurrence: &dto.RecurrenceResponse{Rule: "FREQ=MONTHLY", Timezone: "UTC", Exdates: []string{"2025-04-03"}},
+			CreatedAt:  created, UpdatedAt: created},
+		{ID: 2, Title: "Call", Description: "line one\nline two", Priority: "low", Tags: []string{}, CreatedAt: created, UpdatedAt: created},
+	}
+}
+
+func (suite *TransferTestSuite) write(format string, todos []dto.TodoResponse) string {
+	var out bytes.Buffer
+	writer, err := transfer.NewWriter(format, &out)
+	suite.Require().NoError(err)
+	for i := range todos {
+		suite.Require().NoError(writer.Write(&todos[i]))
+	}
+	suite.Require().NoError(writer.Close())
+	return out.String()
+}
+
+func (suite *TransferTestSuite) TestCSVRoundTrip() {
+	output := suite.write(transfer.FormatCSV, suite.todos())
+	assert.True(suite.T(), strings.HasPrefix(output, "id,title,description,priority,completed,due_date"))
+
+	rows, err := transfer.ReadAll(transfer.FormatCSV, strings.NewReader(output))
+	suite.Require().NoError(err)
+	suite.Require().Len(rows, 2)
+
+	first := rows[0]
+	assert.Equal(suite.T(), 2, first.Line)
+	suite.Require().NoError(first.Err)
+	assert.Equal(suite.T(), "Report, monthly", first.Todo.Title)
+	assert.Equal(suite.T(), []string{"work", "finance"}, first.Todo.Tags)
+	assert.Equal(suite.T(), uint(3), *first.Todo.ProjectID)
+	assert.True(suite.T(), suite.todos()[0].DueDate.Equal(*first.Todo.DueDate))
+	suite.Require().NotNil(first.Todo.Recurrence)
+	assert.Equal(suite.T(), "FREQ=MONTHLY", first.Todo.Recurrence.Rule)
+	assert.Equal(suite.T(), []string{"2025-04-03"}, first.Todo.Recurrence.Exdates)
+
+	// O registro anterior ocupa duas linhas por causa da quebra na descrição
+	assert.Equal(suite.T(), "line one\nline two", rows[1].Todo.Description)
+	assert.Nil(suite.T(), rows[1].Todo.Recurrence)
+}
+
+func (suite *TransferTestSuite) TestCSVEscapesFormulas() {
+	created := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	todos := []dto.TodoResponse{
+		{ID: 1, Title: `=HYPERLINK("https://evil.example.com","x")`, Description: "@SUM(A1)", Tags: []string{"-1", "ok"}, CreatedAt: created, UpdatedAt: created},
+		{ID: 2, Title: "+cmd", Description: "\tindented", CreatedAt: created, UpdatedAt: created},
+		{ID: 3, Title: "'=already quoted", Description: "'plain", CreatedAt: created, UpdatedAt: created},
+	}
+
+	output := suite.write(transfer.FormatCSV, todos)
+
+	records, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+	suite.Require().NoError(err)
+	suite.Require().Len(records, 4)
+	// Nenhuma célula de texto começa com um caractere de fórmula
+	assert.Equal(suite.T(), []string{`'=HYPERLINK("https://evil.example.com","x")`, "'@SUM(A1)"}, records[1][1:3])
+	assert.Equal(suite.T(), "'-1;ok", records[1][8])
+	assert.Equal(suite.T(), []string{"'+cmd", "'\tindented"}, records[2][1:3])
+	assert.Equal(suite.T(), []string{"''=already quoted", "'plain"}, records[3][1:3])
+
+	// A importação devolve os valores originais
+	rows, err := transfer.ReadAll(transfer.FormatCSV, strings.NewReader(output))
+	suite.Require().NoError(err)
+	suite.Require().Len(rows, 3)
+	assert.Equal(suite.T(), todos[0].Title, rows[0].Todo.Title)
+	assert.Equal(suite.T(), "@SUM(A1)", rows[0].Todo.Description)
+	assert.Equal(suite.T(), []string{"-1", "ok"}, rows[0].Todo.Tags)
+	assert.Equal(suite.T(), "+cmd", rows[1].Todo.Title)
+	assert.Equal(suite.T(), "'=already quoted", rows[2].Todo.Title)
+	assert.Equal(suite.T(), "'plain", rows[2].Todo.Description)
+}
+
+func (suite *TransferTestSuite) TestCSVRowErrors() {
+	input := "title,due_date,priority,unknown\n" +
+		"Valid,,low,x\n" +
+		",,,\n" +
+		"Bad date,tomorrow,low,x\n" +
+		"Too,many,columns,here,now\n"
+
+	rows, err := transfer.ReadAll(transfer.FormatCSV, strings.NewReader(input))
+	suite.Require().NoError(err)
+	suite.Require().Len(rows, 4)
+	assert.NoError(suite.T(), rows[0].Err)
+	assert.True(suite.T(), rows[1].Blank)
+	assert.Equal(suite.T(), 4, rows[2].Line)
+	assert.ErrorContains(suite.T(), rows[2].Err, "due_date")
+	assert.Equal(suite.T(), 5, rows[3].Line)
+	assert.ErrorContains(suite.T(), rows[3].Err, "columns")
+
+	_, err = transfer.ReadAll(transfer.FormatCSV, strings.NewReader("name,priority\nx,low\n"))
+	assert.ErrorIs(suite.T(), err, transfer.ErrInvalidFile)
+}
+
+func (suite *TransferTestSuite) TestJSONRoundTrip() {
+	output := suite.write(transfer.FormatJSON, suite.todos())
+
+	var decoded []dto.TodoResponse
+	suite.Require().NoError(json.Unmarshal([]byte(output), &decoded))
+	assert.Len(suite.T(), decoded, 2)
+
+	rows, err := transfer.ReadAll(transfer.FormatJSON, strings.NewReader(output))
+	suite.Require().NoError(err)
+	suite.Require().Len(rows, 2)
+	assert.Equal(suite.T(), 2, rows[0].Line)
+	assert.Equal(suite.T(), 3, rows[1].Line)
+	assert.Equal(suite.T(), "FREQ=MONTHLY", rows[0].Todo.Recurrence.Rule)
+
+	assert.Equal(suite.T(), "[]\n", suite.write(transfer.FormatJSON, nil))
+
+	_, err = transfer.ReadAll(transfer.FormatJSON, strings.NewReader(`{"title": "not an array"}`))
+	assert.ErrorIs(suite.T(), err, transfer.ErrInvalidFile)
+}
+
+func (suite *TransferTestSuite) TestNDJSON() {
+	output := suite.write(transfer.FormatNDJSON, suite.todos())
+	assert.Equal(suite.T(), 2, strings.Count(output, "\n"))
+
+	rows, err := transfer.ReadAll(transfer.FormatNDJSON, strings.NewReader(output+"\n{\"title\": 5}\n"))
+	suite.Require().NoError(err)
+	suite.Require().Len(rows, 4)
+	assert.Equal(suite.T(), "Call", rows[1].Todo.Title)
+	assert.True(suite.T(), rows[2].Blank)
+	assert.Equal(suite.T(), 4, rows[3].Line)
+	assert.Error(suite.T(), rows[3].Err)
+
+	assert.Empty(suite.T(), suite.write(transfer.FormatNDJSON, nil))
+}
+
+func (suite *TransferTestSuite) TestFormatFromFilename() {
+	assert.Equal(suite.T(), transfer.FormatCSV, transfer.FormatFromFilename("todos.CSV"))
+	assert.Equal(suite.T(), transfer.FormatNDJSON, transfer.FormatFromFilename("backup.jsonl"))
+	assert.Empty(suite.T(), transfer.FormatFromFilename("todos.txt"))
+}
+
+func TestTransferTestSuite(t *testing.T) {
+	suite.Run(t, new(TransferTestSuite))
+}
@@ -0,0 +1,67 @@
+package trash_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/trash"
+	"github.com/vinibsi/todo-api/mocks"
+)
+
+type PurgerTestSuite struct {
+	suite.Suite
+	repo   *mocks.MockTodoRepository
+	now    time.Time
+	purger *trash.Purger
+}
+
+func (suite *PurgerTestSuite) SetupTest() {
+	suite.repo = new(mocks.MockTodoRepository)
+	suite.now = time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	suite.purger = trash.NewPurger(suite.repo, trash.Config{
+		Retention: 7 * 24 * time.Hour,
+		BatchSize: 2,
+		Now:       func() time.Time { return suite.now },
+	})
+}
+
+func (suite *PurgerTestSuite) TestRunOnce_PurgesInBatches() {
+	cutoff := suite.now.Add(-7 * 24 * time.Hour)
+	suite.repo.On("PurgeDeletedBefore", cutoff, 2).Return(3, nil).Once()
+	suite.repo.On("PurgeDeletedBefore", cutoff, 2).Return(1, nil).Once()
+	suite.repo.On("PurgeDeletedBefore", cutoff, 2).Return(0, nil).Once()
+
+	purged, err := suite.purger.RunOnce(context.Background())
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 4, purged)
+	suite.repo.AssertExpectations(suite.T())
+}
+
+func (suite *PurgerTestSuite) TestRunOnce_StopsOnError() {
+	suite.repo.On("PurgeDeletedBefore", suite.now.Add(-7*24*time.Hour), 2).Return(0, errors.New("database down")).Once()
+
+	purged, err := suite.purger.RunOnce(context.Background())
+
+	assert.Error(suite.T(), err)
+	assert.Zero(suite.T(), purged)
+	suite.repo.AssertNumberOfCalls(suite.T(), "PurgeDeletedBefore", 1)
+}
+
+func (suite *PurgerTestSuite) TestRunOnce_CanceledContext() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := suite.purger.RunOnce(ctx)
+
+	assert.ErrorIs(suite.T(), err, context.Canceled)
+	suite.repo.AssertNotCalled(suite.T(), "PurgeDeletedBefore")
+}
+
+func TestPurgerTestSuite(t *testing.T) {
+	suite.Run(t, new(PurgerTestSuite))
+}
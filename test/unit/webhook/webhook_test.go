@@ -0,0 +1,242 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vinibsi/todo-api/internal/dto"
+	"github.com/vinibsi/todo-api/internal/entity"
+	"github.com/vinibsi/todo-api/internal/event"
+	"github.com/vinibsi/todo-api/internal/webhook"
+	"github.com/vinibsi/todo-api/mocks"
+)
+
+type WebhookTestSuite struct {
+	suite.Suite
+	repo *mocks.MockWebhookRepository
+	now  time.Time
+}
+
+func (suite *WebhookTestSuite) SetupTest() {
+	suite.repo = new(mocks.MockWebhookRepository)
+	suite.now = time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+}
+
+// worker usa um cliente sem restrições: os servidores de teste escutam em
+// loopback, que o cliente padrão recusa
+func (suite *WebhookTestSuite) worker() *webhook.Worker {
+	return suite.workerWith(&http.Client{})
+}
+
+func (suite *WebhookTestSuite) workerWith(client *http.Client) *webhook.Worker {
+	return webhook.NewWorker(suite.repo, webhook.Config{
+		MaxAttempts: 3,
+		Client:      client,
+		Now:         func() time.Time { return suite.now },
+	})
+}
+
+func (suite *WebhookTestSuite) TestSignAndVerify() {
+	body := []byte(`{"type":"todo.created"}`)
+	signature := webhook.Sign("secret-secret-secret", 1700000000, body)
+
+	assert.Regexp(suite.T(), "^sha256=[0-9a-f]{64}$", signature)
+	assert.True(suite.T(), webhook.Verify("secret-secret-secret", 1700000000, body, signature))
+	assert.False(suite.T(), webhook.Verify("another-secret-value", 1700000000, body, signature))
+	assert.False(suite.T(), webhook.Verify("secret-secret-secret", 1700000001, body, signature))
+	assert.False(suite.T(), webhook.Verify("secret-secret-secret", 1700000000, []byte(`{}`), signature))
+}
+
+func (suite *WebhookTestSuite) TestDispatcher_EnqueuesForSubscribedWebhooks() {
+	suite.repo.On("ListActive", uint(7)).Return([]entity.Webhook{
+		{ID: 1, Events: "todo.created,todo.deleted"},
+		{ID: 2, Events: "todo.completed"},
+		{ID: 3, Events: "todo.created"},
+	}, nil)
+	suite.repo.On("CreateDeliveries", mock.MatchedBy(func(deliveries []entity.WebhookDelivery) bool {
+		if len(deliveries) != 2 || deliveries[0].WebhookID != 1 || deliveries[1].WebhookID != 3 {
+			return false
+		}
+
+		var payload webhook.Payload
+		if err := json.Unmarshal([]byte(deliveries[0].Payload), &payload); err != nil {
+			return false
+		}
+		return payload.Type == event.TodoCreated && payload.Data.Title == "Write report" &&
+			deliveries[0].Payload == deliveries[1].Payload &&
+			deliveries[0].Status == entity.DeliveryPending && deliveries[0].NextAttemptAt != nil
+	})).Return(nil)
+
+	webhook.NewDispatcher(suite.repo).Publish(event.Event{
+		Type:   event.TodoCreated,
+		UserID: 7,
+		TodoID: 10,
+		Todo:   &dto.TodoResponse{ID: 10, Title: "Write report"},
+	})
+
+	suite.repo.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookTestSuite) TestWorker_SendsSignedRequest() {
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	delivery := entity.WebhookDelivery{
+		ID:        5,
+		WebhookID: 1,
+		Event:     event.TodoCreated,
+		Payload:   `{"id":"evt_1","type":"todo.created"}`,
+		Status:    entity.DeliveryPending,
+		Webhook:   &entity.Webhook{ID: 1, URL: server.URL, Secret: "secret-secret-secret"},
+	}
+	suite.repo.On("ClaimDue", suite.now, 50, 2*time.Minute).Return([]entity.WebhookDelivery{delivery}, nil)
+	suite.repo.On("RecordAttempt", mock.MatchedBy(func(d *entity.WebhookDelivery) bool {
+		return d.Status == entity.DeliverySucceeded && d.Attempts == 1 && d.DeliveredAt != nil && d.NextAttemptAt == nil
+	}), mock.MatchedBy(func(a *entity.WebhookAttempt) bool {
+		return a.StatusCode == http.StatusOK && a.Error == ""
+	})).Return(nil)
+
+	sent, err := suite.worker().RunOnce(context.Background())
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 1, sent)
+	suite.Require().NotNil(received)
+	assert.Equal(suite.T(), delivery.Payload, string(body))
+	assert.Equal(suite.T(), event.TodoCreated, received.Header.Get(webhook.HeaderEvent))
+	assert.Equal(suite.T(), "5", received.Header.Get(webhook.HeaderDelivery))
+
+	timestamp, err := strconv.ParseInt(received.Header.Get(webhook.HeaderTimestamp), 10, 64)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), suite.now.Unix(), timestamp)
+	assert.True(suite.T(), webhook.Verify("secret-secret-secret", timestamp, body, received.Header.Get(webhook.HeaderSignature)))
+	suite.repo.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookTestSuite) TestWorker_RetriesWithBackoffThenFails() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	target := &entity.Webhook{ID: 1, URL: server.URL, Secret: "secret-secret-secret"}
+	suite.repo.On("ClaimDue", suite.now, 50, 2*time.Minute).Return([]entity.WebhookDelivery{
+		{ID: 1, Payload: "{}", Status: entity.DeliveryPending, Attempts: 1, Webhook: target},
+		{ID: 2, Payload: "{}", Status: entity.DeliveryPending, Attempts: 2, Webhook: target},
+	}, nil)
+
+	// Segunda falha: espera o dobro do intervalo base
+	retryAt := suite.now.Add(time.Minute)
+	suite.repo.On("RecordAttempt", mock.MatchedBy(func(d *entity.WebhookDelivery) bool {
+		return d.ID == 1 && d.Status == entity.DeliveryPending && d.Attempts == 2 &&
+			d.NextAttemptAt != nil && d.NextAttemptAt.Equal(retryAt) && d.LastStatusCode == http.StatusServiceUnavailable
+	}), mock.MatchedBy(func(a *entity.WebhookAttempt) bool {
+		return a.StatusCode == http.StatusServiceUnavailable && a.Error == "webhook responded with status 503"
+	})).Return(nil)
+	// Terceira falha com MaxAttempts = 3: a entrega é marcada como falha
+	suite.repo.On("RecordAttempt", mock.MatchedBy(func(d *entity.WebhookDelivery) bool {
+		return d.ID == 2 && d.Status == entity.DeliveryFailed && d.Attempts == 3 && d.NextAttemptAt == nil
+	}), mock.Anything).Return(nil)
+
+	sent, err := suite.worker().RunOnce(context.Background())
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 0, sent)
+	suite.repo.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookTestSuite) TestWorker_RejectsPrivateAddresses() {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	suite.repo.On("ClaimDue", suite.now, 50, 2*time.Minute).Return([]entity.WebhookDelivery{
+		{ID: 1, Payload: "{}", Status: entity.DeliveryPending, Webhook: &entity.Webhook{ID: 1, URL: server.URL}},
+	}, nil)
+	suite.repo.On("RecordAttempt", mock.MatchedBy(func(d *entity.WebhookDelivery) bool {
+		return d.Status == entity.DeliveryPending && d.Attempts == 1 && d.LastStatusCode == 0
+	}), mock.MatchedBy(func(a *entity.WebhookAttempt) bool {
+		return strings.Contains(a.Error, webhook.ErrForbiddenAddress.Error())
+	})).Return(nil)
+
+	// Sem Client o worker usa webhook.NewClient
+	sent, err := webhook.NewWorker(suite.repo, webhook.Config{Now: func() time.Time { return suite.now }}).RunOnce(context.Background())
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 0, sent)
+	assert.Equal(suite.T(), 0, requests)
+	suite.repo.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookTestSuite) TestWorker_DoesNotFollowRedirects() {
+	redirected := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/internal" {
+			redirected = true
+			return
+		}
+		http.Redirect(w, r, "/internal", http.StatusFound)
+	}))
+	defer server.Close()
+
+	suite.repo.On("ClaimDue", suite.now, 50, 2*time.Minute).Return([]entity.WebhookDelivery{
+		{ID: 1, Payload: "{}", Status: entity.DeliveryPending, Webhook: &entity.Webhook{ID: 1, URL: server.URL}},
+	}, nil)
+	suite.repo.On("RecordAttempt", mock.MatchedBy(func(d *entity.WebhookDelivery) bool {
+		return d.LastStatusCode == http.StatusFound
+	}), mock.MatchedBy(func(a *entity.WebhookAttempt) bool {
+		return a.Error == "webhook responded with status 302"
+	})).Return(nil)
+
+	// Mantém a política de redirecionamento do cliente padrão, liberando loopback
+	client := webhook.NewClient()
+	client.Transport = http.DefaultTransport
+	sent, err := suite.workerWith(client).RunOnce(context.Background())
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 0, sent)
+	assert.False(suite.T(), redirected)
+	suite.repo.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookTestSuite) TestIsPublicAddr() {
+	for addr, public := range map[string]bool{
+		"93.184.216.34":    true,
+		"2606:4700::1111":  true,
+		"127.0.0.1":        false,
+		"::1":              false,
+		"10.1.2.3":         false,
+		"172.16.0.1":       false,
+		"192.168.0.10":     false,
+		"169.254.169.254":  false,
+		"fe80::1":          false,
+		"fd00::1":          false,
+		"0.0.0.0":          false,
+		"::":               false,
+		"::ffff:127.0.0.1": false,
+	} {
+		assert.Equal(suite.T(), public, webhook.IsPublicAddr(netip.MustParseAddr(addr)), addr)
+	}
+}
+
+func TestWebhookTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookTestSuite))
+}